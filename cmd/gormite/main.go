@@ -7,6 +7,7 @@ import (
 
 	"github.com/KoNekoD/gormite/pkg/runners"
 	"github.com/gookit/goutil/cflag"
+	"github.com/gookit/goutil/cliutil"
 	"github.com/pkg/errors"
 )
 
@@ -17,7 +18,21 @@ func toolValidate(val any) (err error) {
 	return err
 }
 
-var possibleScenarios = []string{runners.ScenarioTypeDiff, runners.ScenarioTypeValidate}
+func formatValidate(val any) (err error) {
+	if !slices.Contains([]string{runners.OutputFormatText, runners.OutputFormatJson}, val.(string)) {
+		err = errors.New("invalid output format")
+	}
+	return err
+}
+
+var possibleScenarios = []string{
+	runners.ScenarioTypeDiff,
+	runners.ScenarioTypeValidate,
+	runners.ScenarioTypeMigrate,
+	runners.ScenarioTypeImport,
+}
+
+var possibleMigrateActions = []string{runners.MigrateActionUp, runners.MigrateActionDown, runners.MigrateActionStatus}
 
 func main() {
 	scenario := runners.ScenarioTypeDiff
@@ -29,15 +44,70 @@ func main() {
 
 	c := cflag.New(func(c *cflag.CFlags) { c.Desc = "Gormite CLI" })
 	ctx := context.Background()
+
+	if scenario == runners.ScenarioTypeMigrate {
+		migrateOpts := runners.MigrateRunnerOptions{Steps: 1}
+
+		if len(args) == 0 || !slices.Contains(possibleMigrateActions, args[0]) {
+			cliutil.Redln("ERROR:", "expected migrate action, allowed: up, down [n], status")
+			os.Exit(1)
+		}
+		migrateOpts.Action = args[0]
+		args = args[1:]
+
+		if migrateOpts.Action == runners.MigrateActionDown && len(args) > 0 && runners.IsMigrateStepsArg(args[0]) {
+			steps, err := runners.ParseMigrateSteps(args[0])
+			if err != nil {
+				cliutil.Redln("ERROR:", err.Error())
+				os.Exit(1)
+			}
+			migrateOpts.Steps = steps
+			args = args[1:]
+		}
+
+		c.StringVar(&migrateOpts.Dsn, "dsn", "", "database connection string, gormite.database.dsn from config by default")
+		c.StringVar(&migrateOpts.Env, "env", "", "environment profile from config")
+		c.StringVar(&migrateOpts.ConfigPath, "config", "gormite.yaml", "config file path, read only when dsn is not passed;false;c")
+		c.StringVar(&migrateOpts.Dir, "dir", "migrations", "migrations directory;false;d")
+
+		c.Func = func(c *cflag.CFlags) error { return runners.NewMigrateRunner(migrateOpts).Run(ctx) }
+
+		c.MustParse(args)
+		return
+	}
+
+	if scenario == runners.ScenarioTypeImport {
+		importOpts := runners.ImportRunnerOptions{}
+
+		c.StringVar(&importOpts.Dsn, "dsn", "", "database connection string, gormite.database.dsn from config by default")
+		c.StringVar(&importOpts.Env, "env", "", "environment profile from config")
+		c.StringVar(&importOpts.ConfigPath, "config", "gormite.yaml", "config file path;true;config,c")
+		c.StringVar(&importOpts.Mapping, "mapping", "", "mapping key from config, not needed when config has only one;false;m")
+
+		c.Func = func(c *cflag.CFlags) error { return runners.NewImportRunner(importOpts).Run(ctx) }
+
+		c.MustParse(args)
+		return
+	}
+
 	opts := runners.DiffRunnerOptions{Scenario: scenario}
 
 	switch scenario {
 	case runners.ScenarioTypeDiff:
 		c.StringVar(&opts.Tool, "tool", "", "migration tool, allowed: migrate, goose;true;t")
 		c.AddValidator("tool", toolValidate)
+		c.StringVar(&opts.Output, "output", "migrations", "migrations output dir, - prints migration to stdout;false;o")
+		c.BoolVar(&opts.AllowDestructive, "allow-destructive", false, "generate migration even if it drops tables, columns or schemas")
+		c.StringVar(&opts.FileNameTemplate, "name-template", runners.DefaultFileNameTemplate, "migration file name without extension, {version} is replaced with timestamp")
 	}
 
-	c.StringVar(&opts.Dsn, "dsn", "", "database connection string;true")
+	c.StringVar(&opts.Format, "format", runners.OutputFormatText, "output format, allowed: text, json;false;f")
+	c.AddValidator("format", formatValidate)
+
+	c.StringVar(&opts.Dsn, "dsn", "", "database connection string, gormite.database.dsn from config by default, not needed with --from-snapshot")
+	c.StringVar(&opts.Env, "env", "", "environment profile from config")
+	c.StringVar(&opts.FromSnapshot, "from-snapshot", "", "diff against schema snapshot file instead of database, the file is refreshed after diff")
+	c.StringVar(&opts.Snapshot, "snapshot", "", "write schema snapshot file after diff")
 	c.StringVar(&opts.ConfigPath, "config", "gormite.yaml", "config file path;true;config,c")
 
 	c.Func = func(c *cflag.CFlags) error { return runners.NewDiffRunner(opts).Run(ctx) }
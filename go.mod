@@ -11,7 +11,6 @@ require (
 	github.com/KoNekoD/smt v0.0.2
 	github.com/charmbracelet/log v0.4.0
 	github.com/elliotchance/orderedmap/v3 v3.1.0
-	github.com/elliotchance/pie/v2 v2.9.1
 	github.com/fatih/structtag v1.2.0
 	github.com/google/go-cmp v0.6.0
 	github.com/gookit/goutil v0.6.18
@@ -19,6 +18,7 @@ require (
 	github.com/jackc/pgx/v5 v5.7.5
 	github.com/pkg/errors v0.9.1
 	golang.org/x/exp v0.0.0-20250718183923-645b1fa84792
+	golang.org/x/tools v0.35.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
@@ -49,8 +49,9 @@ require (
 	github.com/tidwall/sjson v1.2.5 // indirect
 	github.com/xo/terminfo v0.0.0-20220910002029-abceb7e1c41e // indirect
 	golang.org/x/crypto v0.37.0 // indirect
+	golang.org/x/mod v0.26.0 // indirect
 	golang.org/x/sync v0.16.0 // indirect
-	golang.org/x/sys v0.32.0 // indirect
+	golang.org/x/sys v0.34.0 // indirect
 	golang.org/x/term v0.31.0 // indirect
 	golang.org/x/text v0.24.0 // indirect
 )
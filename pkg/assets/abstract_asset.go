@@ -113,7 +113,7 @@ func (a *AbstractAsset) GetName() string {
 func (a *AbstractAsset) GetQuotedName(platform AssetsPlatform) string {
 	keywords := platform.GetReservedKeywordsList()
 
-	parts := strings.Split(a.name, ".")
+	parts := strings.Split(a.GetName(), ".")
 	for k, v := range parts {
 		if a.IsQuoted() || keywords.IsKeyword(v) {
 			parts[k] = platform.QuoteIdentifier(v)
@@ -0,0 +1,81 @@
+package assets
+
+import (
+	"regexp"
+	"strings"
+)
+
+// CheckConstraint - Class for a check constraint.
+type CheckConstraint struct {
+	*AbstractAsset
+
+	// expression - Boolean sql expression without the surrounding CHECK keyword.
+	expression string
+}
+
+func NewCheckConstraint(name string, expression string) *CheckConstraint {
+	v := &CheckConstraint{
+		AbstractAsset: NewAbstractAsset(),
+		expression:    expression,
+	}
+
+	v.SetName(name)
+
+	return v
+}
+
+func (c *CheckConstraint) GetExpression() string {
+	return c.expression
+}
+
+var expressionSpacesRegexp = regexp.MustCompile(`\s+`)
+
+// GetNormalizedExpression - Returns the expression without redundant spaces and outer parentheses and with keywords
+// lowercased, so expressions written by hand can be compared with the ones the database returns.
+func (c *CheckConstraint) GetNormalizedExpression() string {
+	return normalizeExpression(c.expression)
+}
+
+// normalizeExpression - Returns the sql expression without redundant spaces and outer parentheses and with keywords
+// lowercased.
+func normalizeExpression(expression string) string {
+	expression = strings.TrimSpace(expressionSpacesRegexp.ReplaceAllString(expression, " "))
+
+	for isWrappedInParentheses(expression) {
+		expression = strings.TrimSpace(expression[1 : len(expression)-1])
+	}
+
+	return lowerOutsideQuotes(expression)
+}
+
+// lowerOutsideQuotes - Lowercases the expression except string literals, their case is significant.
+func lowerOutsideQuotes(expression string) string {
+	parts := strings.Split(expression, "'")
+	for i := 0; i < len(parts); i += 2 {
+		parts[i] = strings.ToLower(parts[i])
+	}
+
+	return strings.Join(parts, "'")
+}
+
+// isWrappedInParentheses - Returns whether the first parenthesis of the expression is closed by the last one.
+func isWrappedInParentheses(expression string) bool {
+	if !strings.HasPrefix(expression, "(") || !strings.HasSuffix(expression, ")") {
+		return false
+	}
+
+	depth := 0
+	for i, r := range expression {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 && i != len(expression)-1 {
+				return false
+			}
+		}
+	}
+
+	return depth == 0
+}
@@ -54,6 +54,12 @@ func WithColumnComment(comment string) ColumnOption {
 	}
 }
 
+func WithColumnGenerated(expression string) ColumnOption {
+	return func(c *Column) {
+		c.generated = &expression
+	}
+}
+
 // Column - Object representation of a database column.
 type Column struct {
 	*AbstractAsset
@@ -70,6 +76,8 @@ type Column struct {
 	platformOptions  map[string]any
 	columnDefinition *string
 	comment          string
+	// generated - Expression of the stored generated column, see GENERATED ALWAYS AS
+	generated *string
 }
 
 // NewColumn - Creates a new Column.
@@ -221,6 +229,27 @@ func (c *Column) GetComment() string {
 	return c.comment
 }
 
+func (c *Column) SetGenerated(expression *string) *Column {
+	c.generated = expression
+	return c
+}
+
+func (c *Column) GetGenerated() *string {
+	return c.generated
+}
+
+// GetNormalizedGenerated - Returns the generated expression in the form which is used to compare it with the one the
+// database returns, see CheckConstraint.GetNormalizedExpression.
+func (c *Column) GetNormalizedGenerated() *string {
+	if c.generated == nil {
+		return nil
+	}
+
+	expression := normalizeExpression(*c.generated)
+
+	return &expression
+}
+
 func (c *Column) ToArray() map[string]any {
 	columnDefault := ""
 	if c.columnDefault != nil {
@@ -240,6 +269,7 @@ func (c *Column) ToArray() map[string]any {
 		"autoincrement":    c.autoincrement,
 		"columnDefinition": c.columnDefinition,
 		"comment":          c.comment,
+		"generated":        c.generated,
 	}
 
 	for k, v := range c.platformOptions {
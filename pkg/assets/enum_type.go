@@ -0,0 +1,51 @@
+package assets
+
+// EnumType - Database type with the fixed ordered set of string values, e.g. CREATE TYPE name AS ENUM on postgres.
+type EnumType struct {
+	*AbstractAsset
+
+	values []string
+
+	// renamedValues - Rename hints of the values, keys are values and values are their old names
+	renamedValues map[string]string
+}
+
+func NewEnumType(name string, values []string) *EnumType {
+	v := &EnumType{
+		AbstractAsset: NewAbstractAsset(),
+		values:        values,
+		renamedValues: make(map[string]string),
+	}
+
+	v.SetName(name)
+
+	return v
+}
+
+func (e *EnumType) GetValues() []string {
+	return e.values
+}
+
+func (e *EnumType) HasValue(value string) bool {
+	for _, v := range e.values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SetValueRenamedFrom - Marks the value as renamed from the old value, so the value is renamed instead of added.
+func (e *EnumType) SetValueRenamedFrom(value string, oldValue string) {
+	e.renamedValues[value] = oldValue
+}
+
+// GetValueRenamedFrom - Returns old name of the value set by SetValueRenamedFrom.
+func (e *EnumType) GetValueRenamedFrom(value string) *string {
+	if oldValue, ok := e.renamedValues[value]; ok {
+		return &oldValue
+	}
+
+	return nil
+}
@@ -3,7 +3,6 @@ package assets
 import (
 	"github.com/KoNekoD/gormite/pkg/utils"
 	"github.com/KoNekoD/smt/pkg/smt"
-	"golang.org/x/exp/maps"
 	"strings"
 )
 
@@ -15,7 +14,7 @@ type ForeignKeyConstraint struct {
 	// column names the foreign key constraint is associated with
 	//
 	// Names of the referencing table columns
-	localColumnNames map[string]*Identifier
+	localColumnNames []*Identifier
 
 	// foreignTableName Table or asset identifier instance of the
 	// referenced table name the foreign key constraint is associated with
@@ -27,7 +26,7 @@ type ForeignKeyConstraint struct {
 	// column names the foreign key constraint is associated with
 	//
 	// Names of the referenced table columns
-	foreignColumnNames map[string]*Identifier
+	foreignColumnNames []*Identifier
 
 	options map[string]any
 }
@@ -44,22 +43,21 @@ func NewForeignKeyConstraint(
 
 	v.SetName(name)
 
-	v.localColumnNames = v.createIdentifierMap(localColumnNames)
+	v.localColumnNames = v.createIdentifiers(localColumnNames)
 	v.foreignTableName = NewIdentifier(foreignTableName)
-	v.foreignColumnNames = v.createIdentifierMap(foreignColumnNames)
+	v.foreignColumnNames = v.createIdentifiers(foreignColumnNames)
 
 	v.options = options
 
 	return v
 }
 
-func (c *ForeignKeyConstraint) createIdentifierMap(
-	names []string,
-) map[string]*Identifier {
-	identifiers := make(map[string]*Identifier)
+// createIdentifiers - Order of the columns is kept, it matters for composite keys
+func (c *ForeignKeyConstraint) createIdentifiers(names []string) []*Identifier {
+	identifiers := make([]*Identifier, 0, len(names))
 
 	for _, name := range names {
-		identifiers[name] = NewIdentifier(name)
+		identifiers = append(identifiers, NewIdentifier(name))
 	}
 
 	return identifiers
@@ -68,7 +66,7 @@ func (c *ForeignKeyConstraint) createIdentifierMap(
 // GetLocalColumns Returns the names of the referencing table columns
 // the foreign key constraint is associated with
 func (c *ForeignKeyConstraint) GetLocalColumns() []string {
-	return maps.Keys(c.localColumnNames)
+	return smt.MapSlice(c.localColumnNames, func(column *Identifier) string { return column.GetName() })
 }
 
 // GetQuotedLocalColumns Returns the quoted representation of the referencing
@@ -135,7 +133,7 @@ func (c *ForeignKeyConstraint) GetQuotedForeignTableName(
 // GetForeignColumns Returns the names of the referenced table columns
 // the foreign key constraint is associated with
 func (c *ForeignKeyConstraint) GetForeignColumns() []string {
-	return maps.Keys(c.foreignColumnNames)
+	return smt.MapSlice(c.foreignColumnNames, func(column *Identifier) string { return column.GetName() })
 }
 
 // GetQuotedForeignColumns Returns the quoted representation of the referenced
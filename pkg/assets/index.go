@@ -15,6 +15,9 @@ type Index struct {
 	// the index is associated with
 	columns map[string]*Identifier
 
+	// columnNames Order of the columns, it matters for composite indexes
+	columnNames []string
+
 	isPrimary bool
 
 	isUnique bool
@@ -61,13 +64,17 @@ func NewIndex(
 }
 
 func (i *Index) addColumn(column string) {
+	if _, ok := i.columns[column]; !ok {
+		i.columnNames = append(i.columnNames, column)
+	}
+
 	i.columns[column] = NewIdentifier(column)
 }
 
 // GetColumns Returns the names of the referencing table columns the
 // constraint is associated with
 func (i *Index) GetColumns() []string {
-	return maps.Keys(i.columns)
+	return slices.Clone(i.columnNames)
 }
 
 func (i *Index) GetColumn(key string) *Identifier {
@@ -95,10 +102,16 @@ func (i *Index) GetQuotedColumns(platform AssetsPlatform) []string {
 	var length *string
 	columns := make([]string, 0)
 
-	for _, column := range i.columns {
+	for _, columnName := range i.columnNames {
 		length, subParts = smt.SliceShift(subParts)
 
-		quotedColumn := column.GetQuotedName(platform)
+		quotedColumn := i.columns[columnName].GetQuotedName(platform)
+		if i.IsColumnExpression(columnName) {
+			quotedColumn = columnName
+			if !isWrappedInParentheses(columnName) {
+				quotedColumn = "(" + columnName + ")"
+			}
+		}
 
 		if length != nil {
 			quotedColumn += "(" + *length + ")"
@@ -151,13 +164,10 @@ func (i *Index) SpansColumns(columnNames []string) bool {
 	numberOfColumns := len(columns)
 	sameColumns := true
 
-	slices.Sort(columns)
-	slices.Sort(columnNames)
-
 	for j := 0; j < numberOfColumns; j++ {
 		if len(columnNames) > j {
-			indexColumn := i.trimQuotes(strings.ToLower(columns[j]))
-			inputColumn := i.trimQuotes(strings.ToLower(columnNames[j]))
+			indexColumn := i.normalizeColumn(columns[j])
+			inputColumn := i.normalizeColumn(columnNames[j])
 
 			if indexColumn == inputColumn {
 				continue
@@ -192,6 +202,10 @@ func (i *Index) IsFulfilledBy(other *Index) bool {
 			return false
 		}
 
+		if !i.hasSameIndexOptions(other) {
+			return false
+		}
+
 		if !i.IsUnique() && !i.IsPrimary() {
 			// this is a special case: If the current key is neither primary or unique, any unique or
 			// primary key will always have the same effect for the index and there cannot be any constraint
@@ -223,7 +237,8 @@ func (i *Index) overrules(other *Index) bool {
 
 	return i.SpansColumns(other.GetColumns()) &&
 		(i.IsPrimary() || i.IsUnique()) &&
-		i.samePartialIndex(other)
+		i.samePartialIndex(other) &&
+		i.hasSameIndexOptions(other)
 }
 
 // GetFlags Returns platform specific flags for indexes
@@ -301,3 +316,139 @@ func (i *Index) hasSameColumnLengths(other *Index) bool {
 
 	return same
 }
+
+// IsColumnExpression Returns whether the column of the index is an sql
+// expression, e.g. lower(email), instead of the column name
+func (i *Index) IsColumnExpression(column string) bool {
+	return isIndexExpression(column)
+}
+
+func isIndexExpression(column string) bool {
+	return strings.Contains(column, "(")
+}
+
+// GetUsing Returns the index method, e.g. gin, empty string for the
+// default btree method
+func (i *Index) GetUsing() string {
+	using, _ := i.options["using"].(string)
+	if using = strings.ToLower(using); using == "btree" {
+		return ""
+	}
+
+	return using
+}
+
+// GetIncludeColumns Returns the non-key columns of the index which are
+// stored in the index, see INCLUDE clause
+func (i *Index) GetIncludeColumns() []string {
+	include, _ := i.options["include"].([]string)
+
+	return slices.Clone(include)
+}
+
+// GetColumnOrder Returns the normalized ordering of the index column,
+// e.g. DESC NULLS LAST, empty string for the default ordering
+func (i *Index) GetColumnOrder(column string) string {
+	orders, _ := i.options["orders"].(map[string]string)
+	order, _ := NormalizeIndexOrder(orders[column])
+
+	return order
+}
+
+// GetColumnOpclass Returns the operator class of the index column, e.g. gin_trgm_ops
+func (i *Index) GetColumnOpclass(column string) string {
+	opclasses, _ := i.options["opclasses"].(map[string]string)
+
+	return strings.ToLower(opclasses[column])
+}
+
+// NormalizeIndexOrder Returns the ordering of the index column in the form
+// the database reports it, e.g. "desc nulls first" is DESC, false is
+// returned when the ordering is invalid
+func NormalizeIndexOrder(order string) (string, bool) {
+	desc, nullsFirst := false, false
+	nullsSet := false
+
+	words := strings.Fields(strings.ToUpper(order))
+	for j := 0; j < len(words); j++ {
+		switch {
+		case words[j] == "ASC" && j == 0:
+		case words[j] == "DESC" && j == 0:
+			desc = true
+		case words[j] == "NULLS" && !nullsSet && j+1 < len(words) && (words[j+1] == "FIRST" || words[j+1] == "LAST"):
+			nullsSet = true
+			nullsFirst = words[j+1] == "FIRST"
+			j++
+		default:
+			return "", false
+		}
+	}
+
+	// Nulls are first in descending order and last in ascending order by default
+	if !nullsSet {
+		nullsFirst = desc
+	}
+
+	switch {
+	case desc && !nullsFirst:
+		return "DESC NULLS LAST", true
+	case desc:
+		return "DESC", true
+	case nullsFirst:
+		return "NULLS FIRST", true
+	}
+
+	return "", true
+}
+
+// normalizeColumn Returns the column name or the expression of the index
+// in the form which is used to compare indexes
+func (i *Index) normalizeColumn(column string) string {
+	if i.IsColumnExpression(column) {
+		return normalizeExpression(column)
+	}
+
+	return i.trimQuotes(strings.ToLower(column))
+}
+
+// hasSameIndexOptions Returns whether the index has the same method,
+// included columns and column orderings and operator classes as the other
+func (i *Index) hasSameIndexOptions(other *Index) bool {
+	if i.GetUsing() != other.GetUsing() {
+		return false
+	}
+
+	if !slices.Equal(i.normalizeColumns(i.GetIncludeColumns()), other.normalizeColumns(other.GetIncludeColumns())) {
+		return false
+	}
+
+	otherColumns := make(map[string]string, len(other.columnNames))
+	for _, column := range other.columnNames {
+		otherColumns[other.normalizeColumn(column)] = column
+	}
+
+	for _, column := range i.columnNames {
+		otherColumn, ok := otherColumns[i.normalizeColumn(column)]
+		if !ok {
+			return false
+		}
+
+		if i.GetColumnOrder(column) != other.GetColumnOrder(otherColumn) ||
+			i.GetColumnOpclass(column) != other.GetColumnOpclass(otherColumn) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (i *Index) normalizeColumns(columns []string) []string {
+	normalized := make([]string, 0, len(columns))
+	for _, column := range columns {
+		normalized = append(normalized, i.normalizeColumn(column))
+	}
+
+	slices.Sort(normalized)
+
+	return normalized
+}
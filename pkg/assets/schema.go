@@ -1,7 +1,9 @@
 package assets
 
 import (
+	"fmt"
 	"github.com/KoNekoD/gormite/pkg/dtos"
+	"github.com/KoNekoD/gormite/pkg/types"
 	"golang.org/x/exp/maps"
 	"strings"
 )
@@ -34,6 +36,10 @@ type Schema struct {
 
 	sequences map[string]*Sequence
 
+	enumTypes map[string]*EnumType
+
+	views map[string]*View
+
 	schemaConfig *dtos.SchemaConfig
 }
 
@@ -48,6 +54,8 @@ func NewSchema(
 		namespaces:    make(map[string]string),
 		tables:        make(map[string]*Table),
 		sequences:     make(map[string]*Sequence),
+		enumTypes:     make(map[string]*EnumType),
+		views:         make(map[string]*View),
 	}
 
 	if schemaConfig == nil {
@@ -271,9 +279,140 @@ func (s *Schema) CreateSequence(
 	return seq
 }
 
+// CreateEnumType - Creates a new enum type with the values in their order.
+func (s *Schema) CreateEnumType(name string, values []string) *EnumType {
+	enumType := NewEnumType(name, values)
+	enumTypeName := s.normalizeName(enumType)
+
+	if _, ok := s.enumTypes[enumTypeName]; ok {
+		panic("enum type already exists " + enumTypeName)
+	}
+
+	if namespaceName := enumType.GetNamespaceName(); namespaceName != "" &&
+		!enumType.IsInDefaultNamespace(s.GetName()) && !s.HasNamespace(namespaceName) {
+		s.CreateNamespace(namespaceName)
+	}
+
+	s.enumTypes[enumTypeName] = enumType
+
+	return enumType
+}
+
+func (s *Schema) HasEnumType(name string) bool {
+	name = s.getFullQualifiedAssetName(name)
+	_, ok := s.enumTypes[name]
+
+	return ok
+}
+
+func (s *Schema) GetEnumType(name string) *EnumType {
+	name = s.getFullQualifiedAssetName(name)
+
+	v, ok := s.enumTypes[name]
+	if !ok {
+		panic("enum type " + name + " not found")
+	}
+
+	return v
+}
+
+func (s *Schema) GetEnumTypes() map[string]*EnumType {
+	return s.enumTypes
+}
+
+// CreateView - Creates a new view.
+func (s *Schema) CreateView(name string, sql string) *View {
+	view := NewView(name, sql)
+	s.addView(view)
+
+	return view
+}
+
+func (s *Schema) addView(view *View) {
+	viewName := s.normalizeName(view)
+
+	if _, ok := s.views[viewName]; ok {
+		panic("view already exists " + viewName)
+	}
+
+	s.views[viewName] = view
+}
+
+func (s *Schema) GetViews() []*View {
+	return maps.Values(s.views)
+}
+
+func (s *Schema) HasView(name string) bool {
+	name = s.getFullQualifiedAssetName(name)
+	_, ok := s.views[name]
+
+	return ok
+}
+
 func (s *Schema) dropSequence(name string) *Schema {
 	name = s.getFullQualifiedAssetName(name)
 	delete(s.sequences, name)
 
 	return s
 }
+
+// FilterAssets - Removes tables, sequences, enum types and views which are not included by the filter.
+// Sequence of the table column ("<table>__<column>__seq", "<table>_<column>_seq") and the id sequence
+// ("<table>__id__seq") follow the table, enum types used by the included tables are kept.
+func (s *Schema) FilterAssets(isIncluded func(name string) bool) *Schema {
+	includedTables := make(map[string]bool)
+	sequenceOwners := make(map[string]string)
+	for name, table := range s.tables {
+		tableName := table.GetName()
+		includedTables[tableName] = isIncluded(tableName)
+
+		sequenceOwners[fmt.Sprintf("%s__id__seq", tableName)] = tableName
+		for _, column := range table.GetColumns() {
+			sequenceOwners[fmt.Sprintf("%s__%s__seq", tableName, column.GetName())] = tableName
+			sequenceOwners[fmt.Sprintf("%s_%s_seq", tableName, column.GetName())] = tableName
+		}
+
+		if !includedTables[tableName] {
+			delete(s.tables, name)
+		}
+	}
+
+	for name, sequence := range s.sequences {
+		included := isIncluded(sequence.GetName())
+		if ownerName, ok := sequenceOwners[sequence.GetName()]; ok {
+			included = includedTables[ownerName]
+		}
+
+		if !included {
+			delete(s.sequences, name)
+		}
+	}
+
+	usedEnumTypes := make(map[string]bool)
+	for _, table := range s.tables {
+		for _, column := range table.GetColumns() {
+			columnType := column.GetColumnType()
+			if arrayType, ok := columnType.(*types.ArrayType); ok {
+				columnType = arrayType.GetElementType()
+			}
+
+			if enumType, ok := columnType.(*types.EnumType); ok {
+				usedEnumTypes[s.getFullQualifiedAssetName(enumType.GetName())] = true
+			}
+		}
+	}
+
+	for name, enumType := range s.enumTypes {
+		if !usedEnumTypes[name] && !isIncluded(enumType.GetName()) {
+			delete(s.enumTypes, name)
+		}
+	}
+
+	for name, view := range s.views {
+		if !isIncluded(view.GetName()) {
+			delete(s.views, name)
+		}
+	}
+
+	return s
+}
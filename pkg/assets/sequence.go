@@ -1,9 +1,5 @@
 package assets
 
-import (
-	"fmt"
-)
-
 // Sequence - Sequence structure.
 type Sequence struct {
 	*AbstractAsset
@@ -80,32 +76,3 @@ func (s *Sequence) SetCache(cache int) *Sequence {
 
 	return s
 }
-
-// isAutoIncrementsFor - Checks if this sequence is an autoincrement sequence for a given table.
-// This is used inside the comparator to not report sequences as missing,
-// when the "from" schema implicitly creates the sequences.
-func (s *Sequence) IsAutoIncrementsFor(table *Table) bool {
-	primaryKey := table.GetPrimaryKey()
-
-	if primaryKey == nil {
-		return false
-	}
-
-	pkColumns := primaryKey.GetColumns()
-
-	if len(pkColumns) != 1 {
-		return false
-	}
-
-	column := table.GetColumn(pkColumns[0])
-
-	if !column.GetAutoincrement() {
-		return false
-	}
-
-	sequenceName := s.GetShortestName(table.GetNamespaceName())
-	tableName := table.GetShortestName(table.GetNamespaceName())
-	tableSequenceName := fmt.Sprintf("%s__%s__seq", tableName, column.GetShortestName(table.GetNamespaceName()))
-
-	return tableSequenceName == sequenceName
-}
@@ -21,6 +21,9 @@ type Table struct {
 	// renamedColumns - keys are new names, values are old names
 	renamedColumns map[string]string
 
+	// renamedFrom - old name of the table, the table is renamed instead of drop and create
+	renamedFrom *string
+
 	indexes map[string]*Index
 
 	primaryKeyName *string
@@ -29,6 +32,8 @@ type Table struct {
 
 	fkConstraints map[string]*ForeignKeyConstraint
 
+	checkConstraints map[string]*CheckConstraint
+
 	options map[string]any
 
 	schemaConfig *dtos.SchemaConfig
@@ -55,6 +60,7 @@ func NewTable(
 		primaryKeyName:    nil,
 		uniqueConstraints: make(map[string]*UniqueConstraint),
 		fkConstraints:     make(map[string]*ForeignKeyConstraint),
+		checkConstraints:  make(map[string]*CheckConstraint),
 		options: map[string]any{
 			"createoptions": make(map[string]string),
 		},
@@ -318,6 +324,28 @@ func (t *Table) RenameColumn(oldName string, newName string) *Column {
 	return column
 }
 
+// SetColumnRenamedFrom - Marks the column as renamed from the column which is absent in the table.
+func (t *Table) SetColumnRenamedFrom(name string, oldName string) *Table {
+	name = t.normalizeIdentifier(&name)
+	oldName = t.normalizeIdentifier(&oldName)
+
+	if name != oldName {
+		t.renamedColumns[name] = oldName
+	}
+
+	return t
+}
+
+func (t *Table) GetRenamedFrom() *string {
+	return t.renamedFrom
+}
+
+// SetRenamedFrom - Marks the table as renamed from the table with given name.
+func (t *Table) SetRenamedFrom(oldName string) *Table {
+	t.renamedFrom = &oldName
+	return t
+}
+
 func (t *Table) ModifyColumn(name string, options map[string]any) *Table {
 	column := t.GetColumn(name)
 
@@ -378,6 +406,11 @@ func (t *Table) AddForeignKeyConstraint(
 }
 
 func (t *Table) AddOption(name string, value any) *Table {
+	// Tables can be created without options
+	if t.options == nil {
+		t.options = make(map[string]any)
+	}
+
 	t.options[name] = value
 
 	return t
@@ -455,6 +488,51 @@ func (t *Table) RemoveUniqueConstraint(name string) {
 	delete(t.uniqueConstraints, name)
 }
 
+// AddCheckConstraint - Adds a check constraint with the given name and boolean expression.
+func (t *Table) AddCheckConstraint(name string, expression string) *Table {
+	name = t.normalizeIdentifier(&name)
+
+	if t.HasCheckConstraint(name) {
+		panic("check constraint " + name + " already exists")
+	}
+
+	t.checkConstraints[name] = NewCheckConstraint(name, expression)
+
+	return t
+}
+
+// HasCheckConstraint - Returns whether this table has a check constraint with the given name.
+func (t *Table) HasCheckConstraint(name string) bool {
+	name = t.normalizeIdentifier(&name)
+
+	_, ok := t.checkConstraints[name]
+
+	return ok
+}
+
+// GetCheckConstraint - Returns the check constraint with the given name.
+func (t *Table) GetCheckConstraint(name string) *CheckConstraint {
+	name = t.normalizeIdentifier(&name)
+
+	v, ok := t.checkConstraints[name]
+	if !ok {
+		panic("check constraint " + name + " not found")
+	}
+
+	return v
+}
+
+// RemoveCheckConstraint - Removes the check constraint with the given name.
+func (t *Table) RemoveCheckConstraint(name string) {
+	name = t.normalizeIdentifier(&name)
+
+	if !t.HasCheckConstraint(name) {
+		panic("check constraint " + name + " not found")
+	}
+
+	delete(t.checkConstraints, name)
+}
+
 func (t *Table) GetColumns() []*Column {
 	return smt.IterToSlice(t.columns.Values())
 }
@@ -527,6 +605,11 @@ func (t *Table) GetUniqueConstraints() map[string]*UniqueConstraint {
 	return t.uniqueConstraints
 }
 
+// GetCheckConstraints - Returns the check constraints.
+func (t *Table) GetCheckConstraints() map[string]*CheckConstraint {
+	return t.checkConstraints
+}
+
 // GetForeignKeys - Returns the foreign key constraints.
 func (t *Table) GetForeignKeys() map[string]*ForeignKeyConstraint {
 	return t.fkConstraints
@@ -728,13 +811,16 @@ func (t *Table) SetComment(comment *string) *Table {
 	return t
 }
 
+// GetComment - Returns the table comment, nil if the table has no comment.
 func (t *Table) GetComment() *string {
 	v, ok := t.options["comment"]
 	if !ok {
-		panic("not found")
+		return nil
 	}
 
-	return v.(*string)
+	comment, _ := v.(*string)
+
+	return comment
 }
 
 /**
@@ -789,7 +875,7 @@ func (t *Table) createIndex(
 	}
 
 	for _, columnName := range columns {
-		if !t.HasColumn(columnName) {
+		if !isIndexExpression(columnName) && !t.HasColumn(columnName) {
 			panic("column does not exist: " + columnName)
 		}
 	}
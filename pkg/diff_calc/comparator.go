@@ -3,9 +3,11 @@ package diff_calc
 import (
 	"github.com/KoNekoD/gormite/pkg/assets"
 	"github.com/KoNekoD/gormite/pkg/diff_dtos"
+	"github.com/KoNekoD/gormite/pkg/types"
 	"github.com/KoNekoD/smt/pkg/smt"
 	"github.com/google/go-cmp/cmp"
 	"maps"
+	"regexp"
 	"slices"
 	"strings"
 )
@@ -13,11 +15,19 @@ import (
 // Comparator - Compares two Schemas and return an instance of SchemaDiff.
 type Comparator struct {
 	platform DiffCalcPlatform
+	// sequenceNaming - Names id sequences of the renamed tables, so they are renamed with the tables
+	sequenceNaming SequenceNaming
 }
 
 // NewComparator - The comparator can be only instantiated by a schema manager.
 func NewComparator(platform DiffCalcPlatform) *Comparator {
-	return &Comparator{platform: platform}
+	return &Comparator{platform: platform, sequenceNaming: defaultSequenceNaming{}}
+}
+
+// SetSequenceNaming - Sets the naming of id sequences of the schemas, "<table>__id__seq" is used when it is not set.
+func (c *Comparator) SetSequenceNaming(sequenceNaming SequenceNaming) *Comparator {
+	c.sequenceNaming = sequenceNaming
+	return c
 }
 
 // CompareSchemas - Returns the differences between the schemas.
@@ -47,9 +57,21 @@ func (c *Comparator) CompareSchemas(oldSchema, newSchema *assets.Schema) *diff_d
 		droppedSchemas = append(droppedSchemas, oldNamespace)
 	}
 
+	// Keys are old names of the renamed tables, values are new names
+	renamedTables := make(map[string]string)
+
 	for _, newTable := range newSchema.GetTables() {
 		newTableName := newTable.GetShortestName(newSchema.GetName())
 		if !oldSchema.HasTable(newTableName) {
+			if oldTable := c.getRenamedTable(oldSchema, newSchema, newTable); oldTable != nil {
+				alteredTables = append(
+					alteredTables,
+					c.CompareTables(oldTable, newTable).SetNewName(newTableName),
+				)
+				renamedTables[oldTable.GetShortestName(oldSchema.GetName())] = newTableName
+				continue
+			}
+
 			createdTables = append(
 				createdTables,
 				newSchema.GetTable(newTableName),
@@ -71,20 +93,29 @@ func (c *Comparator) CompareSchemas(oldSchema, newSchema *assets.Schema) *diff_d
 		oldTableName := oldTable.GetShortestName(oldSchema.GetName())
 
 		oldTable = oldSchema.GetTable(oldTableName)
-		if newSchema.HasTable(oldTableName) {
+		if _, ok := renamedTables[oldTableName]; ok || newSchema.HasTable(oldTableName) {
 			continue
 		}
 
 		droppedTables = append(droppedTables, oldTable)
 	}
 
+	renamedSequences := c.getRenamedSequences(oldSchema, newSchema, renamedTables)
+
+	renamedToSequences := make(map[*assets.Sequence]bool)
+	for _, sequence := range renamedSequences {
+		renamedToSequences[sequence] = true
+	}
+
 	for _, newSequence := range newSchema.GetSequences() {
 		newSequenceName := newSequence.GetShortestName(newSchema.GetName())
 
+		if renamedToSequences[newSequence] {
+			continue
+		}
+
 		if !oldSchema.HasSequence(newSequenceName) {
-			if !c.isAutoIncrementSequenceInSchema(oldSchema, newSequence) {
-				createdSequences = append(createdSequences, newSequence)
-			}
+			createdSequences = append(createdSequences, newSequence)
 		} else {
 			if c.diffSequence(
 				newSequence,
@@ -99,19 +130,17 @@ func (c *Comparator) CompareSchemas(oldSchema, newSchema *assets.Schema) *diff_d
 	}
 
 	for _, oldSequence := range oldSchema.GetSequences() {
-		if c.isAutoIncrementSequenceInSchema(newSchema, oldSequence) {
-			continue
-		}
-
 		oldSequenceName := oldSequence.GetShortestName(oldSchema.GetName())
 
-		if newSchema.HasSequence(oldSequenceName) {
+		if _, ok := renamedSequences[oldSequenceName]; ok || newSchema.HasSequence(oldSequenceName) {
 			continue
 		}
 
 		droppedSequences = append(droppedSequences, oldSequence)
 	}
 
+	createdEnumTypes, alteredEnumTypes, droppedEnumTypes := c.diffEnumTypes(oldSchema, newSchema)
+
 	return diff_dtos.NewSchemaDiff(
 		createdSchemas,
 		droppedSchemas,
@@ -121,20 +150,186 @@ func (c *Comparator) CompareSchemas(oldSchema, newSchema *assets.Schema) *diff_d
 		createdSequences,
 		alteredSequences,
 		droppedSequences,
-	)
+		renamedSequences,
+	).SetEnumTypes(createdEnumTypes, alteredEnumTypes, droppedEnumTypes)
 }
 
-func (c *Comparator) isAutoIncrementSequenceInSchema(
-	schema *assets.Schema,
-	sequence *assets.Sequence,
-) bool {
-	for _, table := range schema.GetTables() {
-		if sequence.IsAutoIncrementsFor(table) {
-			return true
+// diffEnumTypes - Returns enum types to create, alter and drop, enum types are matched by name.
+func (c *Comparator) diffEnumTypes(
+	oldSchema, newSchema *assets.Schema,
+) ([]*assets.EnumType, []*diff_dtos.EnumTypeDiff, []*assets.EnumType) {
+	created := make([]*assets.EnumType, 0)
+	altered := make([]*diff_dtos.EnumTypeDiff, 0)
+	dropped := make([]*assets.EnumType, 0)
+
+	newEnumTypes := newSchema.GetEnumTypes()
+	for _, key := range slices.Sorted(maps.Keys(newEnumTypes)) {
+		newEnumType := newEnumTypes[key]
+		name := newEnumType.GetShortestName(newSchema.GetName())
+
+		if !oldSchema.HasEnumType(name) {
+			created = append(created, newEnumType)
+			continue
+		}
+
+		oldEnumType := oldSchema.GetEnumType(name)
+		if enumTypeDiff := c.diffEnumType(oldEnumType, newEnumType); !enumTypeDiff.IsEmpty() {
+			altered = append(altered, enumTypeDiff.SetColumns(c.getEnumTypeColumns(oldSchema, oldEnumType)))
 		}
 	}
 
-	return false
+	oldEnumTypes := oldSchema.GetEnumTypes()
+	for _, key := range slices.Sorted(maps.Keys(oldEnumTypes)) {
+		oldEnumType := oldEnumTypes[key]
+
+		if !newSchema.HasEnumType(oldEnumType.GetShortestName(oldSchema.GetName())) {
+			dropped = append(dropped, oldEnumType)
+		}
+	}
+
+	return created, altered, dropped
+}
+
+// getEnumTypeColumns - Returns columns of the enum type and of the arrays of it, ordered by table name.
+func (c *Comparator) getEnumTypeColumns(schema *assets.Schema, enumType *assets.EnumType) []*diff_dtos.EnumTypeColumn {
+	tables := schema.GetTables()
+	slices.SortFunc(tables, func(a, b *assets.Table) int { return strings.Compare(a.GetName(), b.GetName()) })
+
+	columns := make([]*diff_dtos.EnumTypeColumn, 0)
+	for _, table := range tables {
+		for _, column := range table.GetColumns() {
+			columnType := column.GetColumnType()
+			if arrayType, ok := columnType.(*types.ArrayType); ok {
+				columnType = arrayType.GetElementType()
+			}
+
+			columnEnumType, ok := columnType.(*types.EnumType)
+			if !ok || !schema.HasEnumType(columnEnumType.GetName()) {
+				continue
+			}
+
+			if schema.GetEnumType(columnEnumType.GetName()) == enumType {
+				columns = append(columns, diff_dtos.NewEnumTypeColumn(table, column))
+			}
+		}
+	}
+
+	return columns
+}
+
+// diffEnumType - Compares values of the enum types, values are renamed by the rename hints. Hints of the old enum
+// type are applied in reverse, so the down migration renames the value back.
+func (c *Comparator) diffEnumType(oldEnumType, newEnumType *assets.EnumType) *diff_dtos.EnumTypeDiff {
+	renamedValues := make(map[string]string)
+	renamedTo := make(map[string]bool)
+
+	for _, value := range newEnumType.GetValues() {
+		if oldEnumType.HasValue(value) {
+			continue
+		}
+
+		candidates := make([]string, 0)
+		if renamedFrom := newEnumType.GetValueRenamedFrom(value); renamedFrom != nil {
+			candidates = append(candidates, *renamedFrom)
+		}
+		for _, oldValue := range oldEnumType.GetValues() {
+			if renamedFrom := oldEnumType.GetValueRenamedFrom(oldValue); renamedFrom != nil && *renamedFrom == value {
+				candidates = append(candidates, oldValue)
+			}
+		}
+
+		for _, oldValue := range candidates {
+			_, isRenamed := renamedValues[oldValue]
+			if oldEnumType.HasValue(oldValue) && !newEnumType.HasValue(oldValue) && !isRenamed {
+				renamedValues[oldValue] = value
+				renamedTo[value] = true
+				break
+			}
+		}
+	}
+
+	added := make([]string, 0)
+	for _, value := range newEnumType.GetValues() {
+		if !oldEnumType.HasValue(value) && !renamedTo[value] {
+			added = append(added, value)
+		}
+	}
+
+	dropped := make([]string, 0)
+	for _, value := range oldEnumType.GetValues() {
+		if _, ok := renamedValues[value]; !ok && !newEnumType.HasValue(value) {
+			dropped = append(dropped, value)
+		}
+	}
+
+	return diff_dtos.NewEnumTypeDiff(newEnumType, added, renamedValues, dropped)
+}
+
+// getRenamedTable - Returns the old table which is renamed to the new table by the rename hint.
+// Hints of the old schema are applied in reverse, so the down migration renames the table back.
+func (c *Comparator) getRenamedTable(oldSchema, newSchema *assets.Schema, newTable *assets.Table) *assets.Table {
+	newTableName := newTable.GetShortestName(newSchema.GetName())
+
+	if renamedFrom := newTable.GetRenamedFrom(); renamedFrom != nil {
+		if oldSchema.HasTable(*renamedFrom) && !newSchema.HasTable(*renamedFrom) {
+			return oldSchema.GetTable(*renamedFrom)
+		}
+	}
+
+	for _, oldTable := range oldSchema.GetTables() {
+		renamedFrom := oldTable.GetRenamedFrom()
+		if renamedFrom == nil || !strings.EqualFold(*renamedFrom, newTableName) {
+			continue
+		}
+
+		if !newSchema.HasTable(oldTable.GetShortestName(oldSchema.GetName())) {
+			return oldTable
+		}
+	}
+
+	return nil
+}
+
+// getRenamedSequences - Returns id sequences of the renamed tables, which are named by the naming strategy, keys
+// are old names.
+func (c *Comparator) getRenamedSequences(
+	oldSchema *assets.Schema,
+	newSchema *assets.Schema,
+	renamedTables map[string]string,
+) map[string]*assets.Sequence {
+	renamedSequences := make(map[string]*assets.Sequence)
+
+	for oldTableName, newTableName := range renamedTables {
+		for _, column := range newSchema.GetTable(newTableName).GetColumns() {
+			oldSequenceName := c.getSequenceName(oldTableName, column.GetName())
+			newSequenceName := c.getSequenceName(newTableName, column.GetName())
+
+			if !oldSchema.HasSequence(oldSequenceName) || newSchema.HasSequence(oldSequenceName) {
+				continue
+			}
+
+			if !newSchema.HasSequence(newSequenceName) || oldSchema.HasSequence(newSequenceName) {
+				continue
+			}
+
+			renamedSequences[oldSequenceName] = newSchema.GetSequence(newSequenceName)
+		}
+	}
+
+	return renamedSequences
+}
+
+// getSequenceName - Returns name of the id sequence of the table column, it lives in the schema of the table.
+func (c *Comparator) getSequenceName(tableName string, columnName string) string {
+	table := assets.NewIdentifier(tableName)
+	namespace := table.GetNamespaceName()
+	name := c.sequenceNaming.SequenceName(table.GetShortestName(namespace), columnName)
+
+	if namespace == "" {
+		return name
+	}
+
+	return namespace + "." + name
 }
 
 func (c *Comparator) diffSequence(
@@ -194,7 +389,14 @@ func (c *Comparator) CompareTables(oldTable, newTable *assets.Table) *diff_dtos.
 		)
 	}
 
-	renamedColumnNames := newTable.GetRenamedColumns()
+	renamedColumnNames := maps.Clone(newTable.GetRenamedColumns())
+
+	// Renames of the old table are applied in reverse, so the down migration renames the column back
+	for newColumnName, oldColumnName := range oldTable.GetRenamedColumns() {
+		if _, ok := renamedColumnNames[oldColumnName]; !ok {
+			renamedColumnNames[oldColumnName] = newColumnName
+		}
+	}
 
 	for addedColumnName, addedColumn := range addedColumns {
 		if _, ok := renamedColumnNames[addedColumn.GetName()]; !ok {
@@ -202,9 +404,15 @@ func (c *Comparator) CompareTables(oldTable, newTable *assets.Table) *diff_dtos.
 		}
 
 		removedColumnName := strings.ToLower(renamedColumnNames[addedColumn.GetName()])
+
+		droppedColumn, ok := droppedColumns[removedColumnName]
+		if !ok {
+			continue
+		}
+
 		// Explicitly renamed columns need to be diffed, because their types can also have changed
 		modifiedColumns[removedColumnName] = diff_dtos.NewColumnDiff(
-			droppedColumns[removedColumnName],
+			droppedColumn,
 			addedColumn,
 		)
 
@@ -283,7 +491,46 @@ func (c *Comparator) CompareTables(oldTable, newTable *assets.Table) *diff_dtos.
 		addedForeignKeys = append(addedForeignKeys, newForeignKey)
 	}
 
-	return diff_dtos.NewTableDiff(
+	addedChecks, droppedChecks := c.diffCheckConstraints(oldTable, newTable)
+
+	// Dependents of the recreated columns are dropped together with them, so they are created again
+	if spansRecreatedColumn := c.getRecreatedColumnsMatcher(modifiedColumns); spansRecreatedColumn != nil {
+		for _, name := range slices.Sorted(maps.Keys(newIndexes)) {
+			newIndex := newIndexes[name]
+			if !spansRecreatedColumn(newIndex.GetColumns()...) || addedIndexes[name] != nil ||
+				slices.Contains(modifiedIndexes, newIndex) {
+				continue
+			}
+
+			// Renamed index is gone when it is renamed, so it is created with the new name
+			if oldIndexName, ok := findRenamedIndex(renamedIndexes, newIndex); ok {
+				delete(renamedIndexes, oldIndexName)
+				droppedIndexes[oldIndexName] = oldTable.GetIndex(oldIndexName)
+				addedIndexes[name] = newIndex
+				continue
+			}
+
+			modifiedIndexes = append(modifiedIndexes, newIndex)
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(newTable.GetForeignKeys())) {
+			newForeignKey := newTable.GetForeignKeys()[name]
+			if spansRecreatedColumn(newForeignKey.GetUnquotedLocalColumns()...) &&
+				!slices.Contains(addedForeignKeys, newForeignKey) && !slices.Contains(modifiedForeignKeys, newForeignKey) {
+				modifiedForeignKeys = append(modifiedForeignKeys, newForeignKey)
+			}
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(newTable.GetCheckConstraints())) {
+			newCheck := newTable.GetCheckConstraint(name)
+			if spansRecreatedColumn(newCheck.GetNormalizedExpression()) && !slices.Contains(addedChecks, newCheck) {
+				droppedChecks = append(droppedChecks, oldTable.GetCheckConstraint(name))
+				addedChecks = append(addedChecks, newCheck)
+			}
+		}
+	}
+
+	tableDiff := diff_dtos.NewTableDiff(
 		oldTable,
 		droppedForeignKeys,
 		addedColumns,
@@ -295,7 +542,90 @@ func (c *Comparator) CompareTables(oldTable, newTable *assets.Table) *diff_dtos.
 		renamedIndexes,
 		addedForeignKeys,
 		modifiedForeignKeys,
-	)
+	).SetCheckConstraints(addedChecks, droppedChecks)
+
+	if oldComment, newComment := getTableComment(oldTable), getTableComment(newTable); oldComment != newComment {
+		tableDiff.SetNewComment(newComment)
+	}
+
+	return tableDiff
+}
+
+// getRecreatedColumnsMatcher - Returns the function which checks whether any of the column names or expressions
+// references a recreated column, nil is returned when no column is recreated.
+func (c *Comparator) getRecreatedColumnsMatcher(modifiedColumns map[string]*diff_dtos.ColumnDiff) func(...string) bool {
+	names := make([]string, 0)
+	for _, columnDiff := range modifiedColumns {
+		if columnDiff.RequiresRecreation() {
+			names = append(names, regexp.QuoteMeta(strings.ToLower(columnDiff.GetNewColumn().GetName())))
+		}
+	}
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	slices.Sort(names)
+	columnRegexp := regexp.MustCompile(`(^|[^\w$])"?(` + strings.Join(names, `|`) + `)"?($|[^\w$])`)
+
+	return func(expressions ...string) bool {
+		return slices.ContainsFunc(
+			expressions, func(expression string) bool { return columnRegexp.MatchString(strings.ToLower(expression)) },
+		)
+	}
+}
+
+// findRenamedIndex - Returns old name of the index which is renamed to the index.
+func findRenamedIndex(renamedIndexes map[string]*assets.Index, index *assets.Index) (string, bool) {
+	for oldIndexName, renamedIndex := range renamedIndexes {
+		if renamedIndex == index {
+			return oldIndexName, true
+		}
+	}
+
+	return "", false
+}
+
+// getTableComment - Returns the table comment, table without comment has empty one like columns do.
+func getTableComment(table *assets.Table) string {
+	if comment := table.GetComment(); comment != nil {
+		return *comment
+	}
+
+	return ""
+}
+
+// diffCheckConstraints - Returns check constraints to add and drop, constraints are matched by name and changed
+// expression leads to drop and add of the constraint.
+func (c *Comparator) diffCheckConstraints(
+	oldTable, newTable *assets.Table,
+) ([]*assets.CheckConstraint, []*assets.CheckConstraint) {
+	added := make([]*assets.CheckConstraint, 0)
+	dropped := make([]*assets.CheckConstraint, 0)
+
+	for _, name := range slices.Sorted(maps.Keys(newTable.GetCheckConstraints())) {
+		newCheck := newTable.GetCheckConstraint(name)
+
+		if !oldTable.HasCheckConstraint(name) {
+			added = append(added, newCheck)
+			continue
+		}
+
+		oldCheck := oldTable.GetCheckConstraint(name)
+
+		if oldCheck.GetNormalizedExpression() != newCheck.GetNormalizedExpression() {
+			dropped = append(dropped, oldCheck)
+			added = append(added, newCheck)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(oldTable.GetCheckConstraints())) {
+		if !newTable.HasCheckConstraint(name) {
+			dropped = append(dropped, oldTable.GetCheckConstraint(name))
+		}
+	}
+
+	return added, dropped
 }
 
 // detectRenamedColumns - Try to find columns that only changed their name, rename operations maybe cheaper than add/drop
@@ -0,0 +1,16 @@
+package diff_calc
+
+import "fmt"
+
+// SequenceNaming - Names id sequences of the tables, table names are passed without schema,
+// e.g. local_schema.NamingStrategy.
+type SequenceNaming interface {
+	SequenceName(tableName string, columnName string) string
+}
+
+// defaultSequenceNaming - Id sequence is named by the table only, like local_schema.DefaultNamingStrategy does.
+type defaultSequenceNaming struct{}
+
+func (defaultSequenceNaming) SequenceName(tableName string, columnName string) string {
+	return fmt.Sprintf("%s__id__seq", tableName)
+}
@@ -0,0 +1,38 @@
+package diff_dtos
+
+import (
+	"github.com/KoNekoD/gormite/pkg/enums"
+	"slices"
+	"strings"
+)
+
+// Change - Single entry of the diff classified by its impact on the existing data.
+type Change struct {
+	Object      string             `json:"object"`
+	Description string             `json:"description"`
+	Safety      enums.ChangeSafety `json:"safety"`
+}
+
+func NewChange(object string, description string, safety enums.ChangeSafety) *Change {
+	return &Change{Object: object, Description: description, Safety: safety}
+}
+
+func (c *Change) String() string {
+	return c.Description + " " + c.Object
+}
+
+// FilterChanges - Returns changes with given safety.
+func FilterChanges(changes []*Change, safety enums.ChangeSafety) []*Change {
+	return slices.DeleteFunc(slices.Clone(changes), func(c *Change) bool { return c.Safety != safety })
+}
+
+func sortChanges(changes []*Change) {
+	slices.SortFunc(
+		changes, func(a, b *Change) int {
+			if v := strings.Compare(a.Object, b.Object); v != 0 {
+				return v
+			}
+			return strings.Compare(a.Description, b.Description)
+		},
+	)
+}
@@ -3,6 +3,8 @@ package diff_dtos
 import (
 	"fmt"
 	"github.com/KoNekoD/gormite/pkg/assets"
+	"github.com/KoNekoD/gormite/pkg/enums"
+	"github.com/KoNekoD/gormite/pkg/types"
 	"strings"
 )
 
@@ -42,6 +44,7 @@ func (c *ColumnDiff) CountChangedProperties() int {
 		c.HasNameChanged(),
 		c.HasTypeChanged(),
 		c.HasCommentChanged(),
+		c.HasGeneratedChanged(),
 	)
 }
 
@@ -59,10 +62,21 @@ func (c *ColumnDiff) HasNameChanged() bool {
 }
 
 func (c *ColumnDiff) HasTypeChanged() bool {
-	oldType := fmt.Sprintf("%T", c.oldColumn.GetColumnType())
-	newType := fmt.Sprintf("%T", c.newColumn.GetColumnType())
+	return getTypeString(c.oldColumn.GetColumnType()) != getTypeString(c.newColumn.GetColumnType())
+}
+
+// getTypeString - Returns go type of the column type, element types of arrays and names of enums are included,
+// e.g. *types.TextType[].
+func getTypeString(columnType types.AbstractTypeInterface) string {
+	if arrayType, ok := columnType.(*types.ArrayType); ok {
+		return getTypeString(arrayType.GetElementType()) + "[]"
+	}
+
+	if enumType, ok := columnType.(*types.EnumType); ok {
+		return fmt.Sprintf("%T(%s)", enumType, enumType.GetName())
+	}
 
-	return oldType != newType
+	return fmt.Sprintf("%T", columnType)
 }
 
 func (c *ColumnDiff) HasDefaultChanged() bool {
@@ -135,3 +149,97 @@ func (c *ColumnDiff) HasNotNullChanged() bool {
 func (c *ColumnDiff) HasCommentChanged() bool {
 	return c.oldColumn.GetComment() != c.newColumn.GetComment()
 }
+
+// HasGeneratedChanged - Returns whether the column became generated or plain or its expression changed, expressions
+// are compared in the normalized form.
+func (c *ColumnDiff) HasGeneratedChanged() bool {
+	oldGenerated := c.oldColumn.GetNormalizedGenerated()
+	newGenerated := c.newColumn.GetNormalizedGenerated()
+
+	if oldGenerated == nil || newGenerated == nil {
+		return oldGenerated != newGenerated
+	}
+
+	return *oldGenerated != *newGenerated
+}
+
+// RequiresRecreation - Expression of the generated column can't be altered and plain column can't become generated,
+// so the column is dropped and added again, which drops its indexes, foreign keys and check constraints too.
+func (c *ColumnDiff) RequiresRecreation() bool {
+	return c.HasGeneratedChanged() && c.newColumn.GetGenerated() != nil
+}
+
+// GetSafety - Returns whether the column change may truncate the existing values or fail on them.
+func (c *ColumnDiff) GetSafety() enums.ChangeSafety {
+	// Plain column is recreated to become generated, its values are lost
+	if c.HasGeneratedChanged() && c.oldColumn.GetGenerated() == nil {
+		return enums.ChangeSafetyDestructive
+	}
+
+	if c.HasTypeChanged() && !c.isTypeWidened() {
+		return enums.ChangeSafetyLossy
+	}
+
+	if c.HasFixedChanged() || c.HasUnsignedChanged() && c.newColumn.GetUnsigned() {
+		return enums.ChangeSafetyLossy
+	}
+
+	if c.HasNotNullChanged() && c.newColumn.GetNotNull() {
+		return enums.ChangeSafetyLossy
+	}
+
+	switch c.newColumn.GetColumnType().(type) {
+	case *types.StringType, *types.AsciiStringType, *types.BinaryType:
+		if isNarrowed(c.oldColumn.GetLength(), c.newColumn.GetLength()) {
+			return enums.ChangeSafetyLossy
+		}
+	case *types.DecimalType:
+		if isNarrowed(c.oldColumn.GetPrecision(), c.newColumn.GetPrecision()) ||
+			isNarrowed(c.oldColumn.GetScale(), c.newColumn.GetScale()) {
+			return enums.ChangeSafetyLossy
+		}
+	}
+
+	return enums.ChangeSafetySafe
+}
+
+// isTypeWidened - Returns whether all values of the old type fit into the new type.
+func (c *ColumnDiff) isTypeWidened() bool {
+	switch c.newColumn.GetColumnType().(type) {
+	case *types.IntegerType:
+		_, ok := c.oldColumn.GetColumnType().(*types.SmallIntType)
+		return ok
+	case *types.BigintType:
+		switch c.oldColumn.GetColumnType().(type) {
+		case *types.SmallIntType, *types.IntegerType:
+			return true
+		}
+	case *types.FloatType:
+		_, ok := c.oldColumn.GetColumnType().(*types.SmallFloatType)
+		return ok
+	case *types.TextType:
+		switch c.oldColumn.GetColumnType().(type) {
+		case *types.StringType, *types.AsciiStringType:
+			return true
+		}
+	case *types.StringType:
+		_, ok := c.oldColumn.GetColumnType().(*types.AsciiStringType)
+		return ok
+	case *types.DateTimeType, *types.DateTimeImmutableType:
+		switch c.oldColumn.GetColumnType().(type) {
+		case *types.DateTimeType, *types.DateTimeImmutableType:
+			return true
+		}
+	}
+
+	return false
+}
+
+// isNarrowed - Unlimited value is nil, so any limit narrows it.
+func isNarrowed(oldValue, newValue *int) bool {
+	if newValue == nil {
+		return false
+	}
+
+	return oldValue == nil || *newValue < *oldValue
+}
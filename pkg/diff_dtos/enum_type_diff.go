@@ -0,0 +1,110 @@
+package diff_dtos
+
+import (
+	"github.com/KoNekoD/gormite/pkg/assets"
+	"github.com/KoNekoD/gormite/pkg/enums"
+)
+
+// EnumTypeDiff - Changes of the values of the enum type.
+type EnumTypeDiff struct {
+	newEnumType *assets.EnumType
+	addedValues []string
+	// renamedValues - keys are old values
+	renamedValues map[string]string
+	droppedValues []string
+	// columns - Columns of the old enum type, they are converted when the type is recreated
+	columns []*EnumTypeColumn
+}
+
+// EnumTypeColumn - Column of the enum type or of the array of it.
+type EnumTypeColumn struct {
+	table  *assets.Table
+	column *assets.Column
+}
+
+func NewEnumTypeColumn(table *assets.Table, column *assets.Column) *EnumTypeColumn {
+	return &EnumTypeColumn{table: table, column: column}
+}
+
+func (c *EnumTypeColumn) GetTable() *assets.Table {
+	return c.table
+}
+
+func (c *EnumTypeColumn) GetColumn() *assets.Column {
+	return c.column
+}
+
+func NewEnumTypeDiff(
+	newEnumType *assets.EnumType,
+	addedValues []string,
+	renamedValues map[string]string,
+	droppedValues []string,
+) *EnumTypeDiff {
+	return &EnumTypeDiff{
+		newEnumType:   newEnumType,
+		addedValues:   addedValues,
+		renamedValues: renamedValues,
+		droppedValues: droppedValues,
+	}
+}
+
+func (d *EnumTypeDiff) GetNewEnumType() *assets.EnumType {
+	return d.newEnumType
+}
+
+// GetAddedValues - Returns added values in the order of the new enum type.
+func (d *EnumTypeDiff) GetAddedValues() []string {
+	return d.addedValues
+}
+
+func (d *EnumTypeDiff) GetRenamedValues() map[string]string {
+	return d.renamedValues
+}
+
+func (d *EnumTypeDiff) GetDroppedValues() []string {
+	return d.droppedValues
+}
+
+// SetColumns - Sets columns of the old enum type.
+func (d *EnumTypeDiff) SetColumns(columns []*EnumTypeColumn) *EnumTypeDiff {
+	d.columns = columns
+	return d
+}
+
+func (d *EnumTypeDiff) GetColumns() []*EnumTypeColumn {
+	return d.columns
+}
+
+// RequiresRecreation - Values can't be dropped from the enum type in place, the type is recreated with the new values
+// and its columns are converted to it.
+func (d *EnumTypeDiff) RequiresRecreation() bool {
+	return len(d.droppedValues) > 0
+}
+
+func (d *EnumTypeDiff) IsEmpty() bool {
+	return len(d.addedValues) == 0 && len(d.renamedValues) == 0 && len(d.droppedValues) == 0
+}
+
+// GetChanges - Returns changes of the enum type, values are dropped by recreation of the type, which fails when rows
+// still have them.
+func (d *EnumTypeDiff) GetChanges() []*Change {
+	name := d.newEnumType.GetName()
+	changes := make([]*Change, 0)
+
+	for _, value := range d.addedValues {
+		changes = append(changes, NewChange(name, "add enum value "+value+" to", enums.ChangeSafetySafe))
+	}
+
+	for oldValue, value := range d.renamedValues {
+		changes = append(
+			changes,
+			NewChange(name, "rename enum value "+oldValue+" to "+value+" of", enums.ChangeSafetySafe),
+		)
+	}
+
+	for _, value := range d.droppedValues {
+		changes = append(changes, NewChange(name, "drop enum value "+value+" of", enums.ChangeSafetyDestructive))
+	}
+
+	return changes
+}
@@ -2,6 +2,7 @@ package diff_dtos
 
 import (
 	"github.com/KoNekoD/gormite/pkg/assets"
+	"github.com/KoNekoD/gormite/pkg/enums"
 )
 
 // SchemaDiff - Differences between two schemas.
@@ -14,6 +15,11 @@ type SchemaDiff struct {
 	createdSequences []*assets.Sequence
 	alteredSequences []*assets.Sequence
 	droppedSequences []*assets.Sequence
+	// renamedSequences - keys are old names
+	renamedSequences map[string]*assets.Sequence
+	createdEnumTypes []*assets.EnumType
+	alteredEnumTypes []*EnumTypeDiff
+	droppedEnumTypes []*assets.EnumType
 }
 
 func NewSchemaDiff(
@@ -25,6 +31,7 @@ func NewSchemaDiff(
 	createdSequences []*assets.Sequence,
 	alteredSequences []*assets.Sequence,
 	droppedSequences []*assets.Sequence,
+	renamedSequences map[string]*assets.Sequence,
 ) *SchemaDiff {
 	return &SchemaDiff{
 		createdSchemas:   createdSchemas,
@@ -35,6 +42,7 @@ func NewSchemaDiff(
 		createdSequences: createdSequences,
 		alteredSequences: alteredSequences,
 		droppedSequences: droppedSequences,
+		renamedSequences: renamedSequences,
 	}
 }
 
@@ -70,6 +78,42 @@ func (s *SchemaDiff) GetDroppedSequences() []*assets.Sequence {
 	return s.droppedSequences
 }
 
+func (s *SchemaDiff) GetRenamedSequences() map[string]*assets.Sequence {
+	return s.renamedSequences
+}
+
+// SetEnumTypes - Sets the enum types to create, alter and drop.
+func (s *SchemaDiff) SetEnumTypes(created []*assets.EnumType, altered []*EnumTypeDiff, dropped []*assets.EnumType) *SchemaDiff {
+	s.createdEnumTypes = created
+	s.alteredEnumTypes = altered
+	s.droppedEnumTypes = dropped
+	return s
+}
+
+func (s *SchemaDiff) GetCreatedEnumTypes() []*assets.EnumType {
+	return s.createdEnumTypes
+}
+
+func (s *SchemaDiff) GetAlteredEnumTypes() []*EnumTypeDiff {
+	return s.alteredEnumTypes
+}
+
+func (s *SchemaDiff) GetDroppedEnumTypes() []*assets.EnumType {
+	return s.droppedEnumTypes
+}
+
+// HasAddedEnumValues - Returns whether values are added to the enum types in place. Postgres can't use added values
+// in the transaction which adds them, so such migrations are applied without transaction.
+func (s *SchemaDiff) HasAddedEnumValues() bool {
+	for _, enumTypeDiff := range s.alteredEnumTypes {
+		if len(enumTypeDiff.GetAddedValues()) > 0 && !enumTypeDiff.RequiresRecreation() {
+			return true
+		}
+	}
+
+	return false
+}
+
 // IsEmpty - Returns whether the diff is empty (contains no changes).
 func (s *SchemaDiff) IsEmpty() bool {
 	return len(s.createdSchemas) == 0 &&
@@ -79,5 +123,67 @@ func (s *SchemaDiff) IsEmpty() bool {
 		len(s.droppedTables) == 0 &&
 		len(s.createdSequences) == 0 &&
 		len(s.alteredSequences) == 0 &&
-		len(s.droppedSequences) == 0
+		len(s.droppedSequences) == 0 &&
+		len(s.renamedSequences) == 0 &&
+		len(s.createdEnumTypes) == 0 &&
+		len(s.alteredEnumTypes) == 0 &&
+		len(s.droppedEnumTypes) == 0
+}
+
+// GetChanges - Returns all entries of the diff classified by safety, sorted by object name.
+func (s *SchemaDiff) GetChanges() []*Change {
+	changes := make([]*Change, 0)
+
+	for _, name := range s.createdSchemas {
+		changes = append(changes, NewChange(name, "create schema", enums.ChangeSafetySafe))
+	}
+
+	for _, name := range s.droppedSchemas {
+		changes = append(changes, NewChange(name, "drop schema", enums.ChangeSafetyDestructive))
+	}
+
+	for _, table := range s.createdTables {
+		changes = append(changes, NewChange(table.GetName(), "create table", enums.ChangeSafetySafe))
+	}
+
+	for _, tableDiff := range s.alteredTables {
+		changes = append(changes, tableDiff.GetChanges()...)
+	}
+
+	for _, table := range s.droppedTables {
+		changes = append(changes, NewChange(table.GetName(), "drop table", enums.ChangeSafetyDestructive))
+	}
+
+	for _, sequence := range s.createdSequences {
+		changes = append(changes, NewChange(sequence.GetName(), "create sequence", enums.ChangeSafetySafe))
+	}
+
+	for _, sequence := range s.alteredSequences {
+		changes = append(changes, NewChange(sequence.GetName(), "alter sequence", enums.ChangeSafetySafe))
+	}
+
+	for oldName, sequence := range s.renamedSequences {
+		changes = append(changes, NewChange(sequence.GetName(), "rename sequence "+oldName+" to", enums.ChangeSafetySafe))
+	}
+
+	// Current value of the sequence is lost
+	for _, sequence := range s.droppedSequences {
+		changes = append(changes, NewChange(sequence.GetName(), "drop sequence", enums.ChangeSafetyLossy))
+	}
+
+	for _, enumType := range s.createdEnumTypes {
+		changes = append(changes, NewChange(enumType.GetName(), "create enum type", enums.ChangeSafetySafe))
+	}
+
+	for _, enumTypeDiff := range s.alteredEnumTypes {
+		changes = append(changes, enumTypeDiff.GetChanges()...)
+	}
+
+	for _, enumType := range s.droppedEnumTypes {
+		changes = append(changes, NewChange(enumType.GetName(), "drop enum type", enums.ChangeSafetyDestructive))
+	}
+
+	sortChanges(changes)
+
+	return changes
 }
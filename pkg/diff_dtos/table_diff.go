@@ -2,6 +2,7 @@ package diff_dtos
 
 import (
 	"github.com/KoNekoD/gormite/pkg/assets"
+	"github.com/KoNekoD/gormite/pkg/enums"
 )
 
 // TableDiff - Table Diff.
@@ -17,6 +18,10 @@ type TableDiff struct {
 	renamedIndexes      map[string]*assets.Index
 	addedForeignKeys    []*assets.ForeignKeyConstraint
 	modifiedForeignKeys []*assets.ForeignKeyConstraint
+	addedChecks         []*assets.CheckConstraint
+	droppedChecks       []*assets.CheckConstraint
+	newComment          *string
+	newName             *string
 }
 
 func NewTableDiff(
@@ -52,6 +57,34 @@ func (d *TableDiff) GetOldTable() *assets.Table {
 	return d.oldTable
 }
 
+// GetNewName - Returns the name the table is renamed to, nil if the table is not renamed.
+func (d *TableDiff) GetNewName() *string {
+	return d.newName
+}
+
+func (d *TableDiff) SetNewName(newName string) *TableDiff {
+	d.newName = &newName
+	return d
+}
+
+// SetCheckConstraints - Sets the check constraints to add and drop, modified check constraints are dropped and added
+// again, because postgres can not alter them.
+func (d *TableDiff) SetCheckConstraints(added, dropped []*assets.CheckConstraint) *TableDiff {
+	d.addedChecks = added
+	d.droppedChecks = dropped
+	return d
+}
+
+// GetNewComment - Returns the new table comment, nil if the comment is not changed. Empty comment removes it.
+func (d *TableDiff) GetNewComment() *string {
+	return d.newComment
+}
+
+func (d *TableDiff) SetNewComment(newComment string) *TableDiff {
+	d.newComment = &newComment
+	return d
+}
+
 func (d *TableDiff) GetAddedColumns() map[string]*assets.Column {
 	return d.addedColumns
 }
@@ -92,9 +125,19 @@ func (d *TableDiff) GetDroppedForeignKeys() []*assets.ForeignKeyConstraint {
 	return d.droppedForeignKeys
 }
 
+func (d *TableDiff) GetAddedCheckConstraints() []*assets.CheckConstraint {
+	return d.addedChecks
+}
+
+func (d *TableDiff) GetDroppedCheckConstraints() []*assets.CheckConstraint {
+	return d.droppedChecks
+}
+
 // IsEmpty - Returns whether the diff is empty (contains no changes).
 func (d *TableDiff) IsEmpty() bool {
-	return len(d.addedColumns) == 0 &&
+	return d.newName == nil &&
+		d.newComment == nil &&
+		len(d.addedColumns) == 0 &&
 		len(d.changedColumns) == 0 &&
 		len(d.droppedColumns) == 0 &&
 		len(d.addedIndexes) == 0 &&
@@ -103,5 +146,102 @@ func (d *TableDiff) IsEmpty() bool {
 		len(d.renamedIndexes) == 0 &&
 		len(d.addedForeignKeys) == 0 &&
 		len(d.modifiedForeignKeys) == 0 &&
-		len(d.droppedForeignKeys) == 0
+		len(d.droppedForeignKeys) == 0 &&
+		len(d.addedChecks) == 0 &&
+		len(d.droppedChecks) == 0
+}
+
+// GetChanges - Returns entries of the table diff classified by safety.
+func (d *TableDiff) GetChanges() []*Change {
+	tableName := d.oldTable.GetName()
+	changes := make([]*Change, 0)
+
+	if d.newName != nil {
+		changes = append(changes, NewChange(*d.newName, "rename table "+tableName+" to", enums.ChangeSafetySafe))
+	}
+
+	if d.newComment != nil {
+		changes = append(changes, NewChange(tableName, "alter comment", enums.ChangeSafetySafe))
+	}
+
+	// Existing rows get null in the added not null column without default
+	for _, column := range d.addedColumns {
+		safety := enums.ChangeSafetySafe
+		if column.GetNotNull() && column.GetColumnDefault() == nil && column.GetGenerated() == nil && !column.GetAutoincrement() {
+			safety = enums.ChangeSafetyLossy
+		}
+
+		changes = append(changes, NewChange(tableName+"."+column.GetName(), "add column", safety))
+	}
+
+	for _, columnDiff := range d.changedColumns {
+		description := "alter column"
+		if columnDiff.HasNameChanged() {
+			description = "rename column " + columnDiff.GetOldColumn().GetName() + " to"
+		}
+
+		changes = append(
+			changes,
+			NewChange(tableName+"."+columnDiff.GetNewColumn().GetName(), description, columnDiff.GetSafety()),
+		)
+	}
+
+	for _, column := range d.droppedColumns {
+		changes = append(
+			changes,
+			NewChange(tableName+"."+column.GetName(), "drop column", enums.ChangeSafetyDestructive),
+		)
+	}
+
+	// Unique indexes, foreign keys and checks fail on the existing rows which violate them
+	for _, index := range d.addedIndexes {
+		changes = append(changes, NewChange(tableName+"."+index.GetName(), "add index", getIndexSafety(index)))
+	}
+
+	for _, index := range d.modifiedIndexes {
+		changes = append(changes, NewChange(tableName+"."+index.GetName(), "alter index", getIndexSafety(index)))
+	}
+
+	for oldName, index := range d.renamedIndexes {
+		changes = append(
+			changes,
+			NewChange(tableName+"."+index.GetName(), "rename index "+oldName+" to", enums.ChangeSafetySafe),
+		)
+	}
+
+	for _, index := range d.droppedIndexes {
+		changes = append(changes, NewChange(tableName+"."+index.GetName(), "drop index", enums.ChangeSafetySafe))
+	}
+
+	for _, fk := range d.addedForeignKeys {
+		changes = append(changes, NewChange(tableName+"."+fk.GetName(), "add foreign key", enums.ChangeSafetyLossy))
+	}
+
+	for _, fk := range d.modifiedForeignKeys {
+		changes = append(changes, NewChange(tableName+"."+fk.GetName(), "alter foreign key", enums.ChangeSafetyLossy))
+	}
+
+	for _, fk := range d.droppedForeignKeys {
+		changes = append(changes, NewChange(tableName+"."+fk.GetName(), "drop foreign key", enums.ChangeSafetySafe))
+	}
+
+	for _, check := range d.addedChecks {
+		changes = append(changes, NewChange(tableName+"."+check.GetName(), "add check", enums.ChangeSafetyLossy))
+	}
+
+	for _, check := range d.droppedChecks {
+		changes = append(changes, NewChange(tableName+"."+check.GetName(), "drop check", enums.ChangeSafetySafe))
+	}
+
+	sortChanges(changes)
+
+	return changes
+}
+
+func getIndexSafety(index *assets.Index) enums.ChangeSafety {
+	if index.IsUnique() {
+		return enums.ChangeSafetyLossy
+	}
+
+	return enums.ChangeSafetySafe
 }
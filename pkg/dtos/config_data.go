@@ -1,34 +1,140 @@
 package dtos
 
 import (
+	"github.com/KoNekoD/gormite/pkg/enums"
 	"github.com/pkg/errors"
+	"golang.org/x/exp/maps"
 	"gopkg.in/yaml.v3"
 	"os"
+	"regexp"
+	"slices"
+	"strings"
 )
 
 type ConfigData struct {
 	Gormite struct {
 		Orm struct {
 			Mapping map[string]*ConfigDataMapping
+			// IdStrategy - How ids of the tables are generated, sequence is used when empty, see enums.IdStrategy
+			IdStrategy enums.IdStrategy `yaml:"id_strategy"`
+			Naming     ConfigDataNaming `yaml:"naming"`
 		}
+		Database     ConfigDataDatabase                `yaml:"database"`
+		Environments map[string]*ConfigDataEnvironment `yaml:"environments"`
+		SchemaFilter SchemaFilter                      `yaml:"schema_filter"`
 	}
 }
 
 type ConfigDataMapping struct {
 	Dir string
+	// Schema - Postgres schema of the mapping tables, default schema is used when empty
+	Schema string
 }
 
+// ConfigDataNaming - Naming strategy of the tables, indexes, constraints and sequences, see local_schema.NamingStrategy.
+type ConfigDataNaming struct {
+	// Strategy - Name of the registered strategy, default is used when empty
+	Strategy string `yaml:"strategy"`
+	// Plural - Table names of the structs are pluralized, e.g. OrderItem is order_items
+	Plural bool `yaml:"plural"`
+	// TablePrefix - Prefix of the table names of the structs, e.g. app_ makes app_user of User
+	TablePrefix string `yaml:"table_prefix"`
+}
+
+type ConfigDataDatabase struct {
+	Dsn string `yaml:"dsn"`
+}
+
+// ConfigDataEnvironment - Named profile selected with --env, its values win over the top-level ones.
+type ConfigDataEnvironment struct {
+	Database ConfigDataDatabase `yaml:"database"`
+}
+
+// envVariableRegexp - Matches ${NAME} and ${NAME:-default}
+var envVariableRegexp = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?}`)
+
 func NewConfigData(path string) (*ConfigData, error) {
 	configContent, err := os.ReadFile(path)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
 
+	var document yaml.Node
+	if err = yaml.Unmarshal(configContent, &document); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	expandEnvNode(&document)
+
 	config := ConfigData{}
 
-	if err = yaml.Unmarshal(configContent, &config); err != nil {
+	if err = document.Decode(&config); err != nil {
 		return nil, errors.WithStack(err)
 	}
 
+	if err = config.Gormite.SchemaFilter.Compile(); err != nil {
+		return nil, err
+	}
+
 	return &config, nil
 }
+
+// GetDsn - Returns dsn of the environment profile, top-level one is used when env is empty
+// or the profile has no dsn.
+func (c *ConfigData) GetDsn(env string) (string, error) {
+	dsn := c.Gormite.Database.Dsn
+
+	if env != "" {
+		environment, ok := c.Gormite.Environments[env]
+		if !ok {
+			keys := maps.Keys(c.Gormite.Environments)
+			slices.Sort(keys)
+
+			return "", errors.Errorf("unknown environment %s, allowed: %s", env, strings.Join(keys, ", "))
+		}
+
+		if environment.Database.Dsn != "" {
+			dsn = environment.Database.Dsn
+		}
+	}
+
+	return dsn, nil
+}
+
+// expandEnvNode - Expands variables of the decoded scalar values, so values of the variables are never parsed as
+// yaml and comments are not expanded. Type of the plain scalar is resolved again after expansion.
+func expandEnvNode(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode {
+		value := expandEnv(node.Value)
+		if value != node.Value && node.Style == 0 {
+			node.Tag = ""
+		}
+
+		node.Value = value
+	}
+
+	for i, child := range node.Content {
+		if node.Kind == yaml.MappingNode && i%2 == 0 {
+			continue // keys are not expanded
+		}
+
+		expandEnvNode(child)
+	}
+}
+
+// expandEnv - Replaces ${NAME} with the environment variable, ${NAME:-default} falls back
+// to default when the variable is unset or empty.
+func expandEnv(content string) string {
+	return envVariableRegexp.ReplaceAllStringFunc(
+		content, func(match string) string {
+			parts := envVariableRegexp.FindStringSubmatch(match)
+
+			value := os.Getenv(parts[1])
+			if value == "" && len(parts[2]) > 0 {
+				value = parts[3]
+			}
+
+			return value
+		},
+	)
+}
@@ -1,9 +1,18 @@
 package dtos
 
 type FetchTableOptionsByTableDto struct {
-	Relname  string  `db:"relname"`
-	Unlogged bool    `db:"unlogged"`
-	Comment  *string `db:"comment"`
+	Relname    string  `db:"relname"`
+	SchemaName string  `db:"schema_name"`
+	Unlogged   bool    `db:"unlogged"`
+	Comment    *string `db:"comment"`
+}
+
+func (f *FetchTableOptionsByTableDto) GetSchemaName() string {
+	return f.SchemaName
+}
+
+func (f *FetchTableOptionsByTableDto) GetTableName() string {
+	return f.Relname
 }
 
 func (f *FetchTableOptionsByTableDto) ToArray() map[string]any {
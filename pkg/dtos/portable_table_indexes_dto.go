@@ -6,4 +6,9 @@ type PortableTableIndexesDto struct {
 	NonUnique  bool
 	Primary    bool
 	Where      *string
+	Using      string
+	Order      string
+	Opclass    string
+	// Included - Column is a non-key column of the INCLUDE clause
+	Included bool
 }
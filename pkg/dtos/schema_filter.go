@@ -0,0 +1,84 @@
+package dtos
+
+import (
+	"github.com/pkg/errors"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// SchemaFilter - Include/exclude lists for names of tables, sequences and views.
+// Pattern wrapped in slashes is a regular expression, everything else is a glob.
+type SchemaFilter struct {
+	Include []string `yaml:"include"`
+	Exclude []string `yaml:"exclude"`
+
+	includeMatchers []func(name string) bool
+	excludeMatchers []func(name string) bool
+}
+
+// Compile - Validates patterns, must be called before IsIncluded.
+func (f *SchemaFilter) Compile() error {
+	var err error
+
+	if f.includeMatchers, err = compileSchemaFilterPatterns(f.Include); err != nil {
+		return errors.Wrap(err, "invalid schema_filter include")
+	}
+
+	if f.excludeMatchers, err = compileSchemaFilterPatterns(f.Exclude); err != nil {
+		return errors.Wrap(err, "invalid schema_filter exclude")
+	}
+
+	return nil
+}
+
+// IsIncluded - Returns whether the asset with given name is managed by gormite.
+// Empty include list includes everything, exclude list wins over include list.
+func (f *SchemaFilter) IsIncluded(name string) bool {
+	for _, matches := range f.excludeMatchers {
+		if matches(name) {
+			return false
+		}
+	}
+
+	if len(f.includeMatchers) == 0 {
+		return true
+	}
+
+	for _, matches := range f.includeMatchers {
+		if matches(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func compileSchemaFilterPatterns(patterns []string) ([]func(name string) bool, error) {
+	matchers := make([]func(name string) bool, 0, len(patterns))
+
+	for _, pattern := range patterns {
+		if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+			re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+
+			matchers = append(matchers, re.MatchString)
+			continue
+		}
+
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, errors.Wrapf(err, "pattern %s", pattern)
+		}
+
+		matchers = append(
+			matchers, func(name string) bool {
+				matched, _ := path.Match(pattern, name)
+				return matched
+			},
+		)
+	}
+
+	return matchers, nil
+}
@@ -0,0 +1,16 @@
+package dtos
+
+type SelectCheckConstraintsDto struct {
+	TableName  string `db:"table_name"`
+	SchemaName string `db:"schema_name"`
+	Conname    string `db:"conname"`
+	Condef     string `db:"condef"`
+}
+
+func (s *SelectCheckConstraintsDto) GetSchemaName() string {
+	return s.SchemaName
+}
+
+func (s *SelectCheckConstraintsDto) GetTableName() string {
+	return s.TableName
+}
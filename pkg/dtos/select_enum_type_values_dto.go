@@ -0,0 +1,7 @@
+package dtos
+
+type SelectEnumTypeValuesDto struct {
+	SchemaName string `db:"schema_name"`
+	TypeName   string `db:"type_name"`
+	Label      string `db:"label"`
+}
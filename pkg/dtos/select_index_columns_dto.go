@@ -9,6 +9,9 @@ type SelectIndexColumnsDto struct {
 	Indkey       string  `db:"indkey"`
 	Indrelid     *string `db:"indrelid"`
 	Where        *string `db:"where"`
+	Using        string  `db:"using"`
+	// IndexColumns - JSON array of the index columns, see PostgreSQLSchemaManager.SelectIndexColumns
+	IndexColumns *string `db:"index_columns"`
 }
 
 func (s *SelectIndexColumnsDto) GetSchemaName() string {
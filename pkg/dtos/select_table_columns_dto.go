@@ -6,6 +6,7 @@ type SelectTableColumnsDto struct {
 	Attnum             int     `db:"attnum"`
 	Field              string  `db:"field"`
 	Type               string  `db:"type"`
+	IsEnum             bool    `db:"is_enum"`
 	CompleteType       string  `db:"complete_type"`
 	Collation          *string `db:"collation"`
 	DomainType         *string `db:"domain_type"`
@@ -14,6 +15,7 @@ type SelectTableColumnsDto struct {
 	Attidentity        rune    `db:"attidentity"`
 	Pri                *string `db:"pri"`
 	Default            *string `db:"default"`
+	IsGenerated        bool    `db:"is_generated"`
 	Comment            *string `db:"comment"`
 }
 
@@ -0,0 +1,14 @@
+package enums
+
+type ChangeSafety string
+
+const (
+	// ChangeSafetySafe - Change keeps all the existing data
+	ChangeSafetySafe ChangeSafety = "safe"
+
+	// ChangeSafetyLossy - Change may truncate the existing data or fail on it
+	ChangeSafetyLossy ChangeSafety = "lossy"
+
+	// ChangeSafetyDestructive - Change drops the existing data
+	ChangeSafetyDestructive ChangeSafety = "destructive"
+)
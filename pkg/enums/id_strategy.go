@@ -0,0 +1,20 @@
+package enums
+
+type IdStrategy string
+
+const (
+	// IdStrategySequence - Standalone <table>__id__seq sequence, values are taken from it by the application
+	IdStrategySequence IdStrategy = "sequence"
+
+	// IdStrategySerial - Standalone <table>__id__seq sequence, which is the default of the id column
+	IdStrategySerial IdStrategy = "serial"
+
+	// IdStrategyIdentity - Id column is GENERATED BY DEFAULT AS IDENTITY
+	IdStrategyIdentity IdStrategy = "identity"
+
+	// IdStrategyNone - Id is always set by the application
+	IdStrategyNone IdStrategy = "none"
+)
+
+// IdStrategies - All the id strategies, sequence is the default one
+var IdStrategies = []IdStrategy{IdStrategySequence, IdStrategySerial, IdStrategyIdentity, IdStrategyNone}
@@ -1,11 +1,15 @@
 package local_schema
 
 import (
+	"github.com/KoNekoD/gormite/pkg/enums"
 	"github.com/pkg/errors"
 	"go/ast"
-	"go/parser"
 	"go/token"
+	gotypes "go/types"
 	"golang.org/x/exp/maps"
+	"golang.org/x/tools/go/packages"
+	"path/filepath"
+	"reflect"
 	"slices"
 	"strings"
 )
@@ -20,38 +24,78 @@ func (s *store) collectAst() error {
 		}
 	}
 
-	return nil
+	return s.applyTableMetadata()
 }
 
 func (s *store) collectMappingKeyAst(mappingKey string) error {
 	mapping := s.config.Gormite.Orm.Mapping[mappingKey]
 
-	parsed, err := parser.ParseDir(token.NewFileSet(), mapping.Dir, nil, parser.ParseComments)
+	loadedPackage, err := s.loadPackage(mapping.Dir)
 	if err != nil {
-		return errors.WithStack(err)
+		return err
 	}
 
-	if len(parsed) != 1 {
-		return errors.Errorf("expected 1 package, got %d", len(parsed))
+	for _, file := range loadedPackage.Syntax {
+		fileName := s.fileSet.Position(file.Package).Filename
+		if err := s.collectMappingKeyFileAst(fileName, file, mapping.Schema); err != nil {
+			return errors.WithStack(err)
+		}
 	}
 
-	firstKey := maps.Keys(parsed)[0]
+	// Comments are collected when structs of all files are known, since detached comment may map struct of other file
+	for _, file := range loadedPackage.Syntax {
+		if err := s.collectMappingKeyFileComments(file); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	return s.collectTableNameMethods(loadedPackage.Syntax)
+}
 
-	parsedPackage := parsed[firstKey]
+// loadPackageMode - Syntax with comments and types info of the package, imports are type-checked from the source too,
+// so export data of the installed go toolchain is not required.
+const loadPackageMode = packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps |
+	packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo
 
-	for fileName, fileData := range parsedPackage.Files {
-		if err := s.collectMappingKeyFileAst(fileName, fileData); err != nil {
-			return errors.WithStack(err)
+// loadPackage - Loads the package of the dir and adds its types info to the store, so field types are resolved through
+// named types and aliases. Load and type errors are returned, since columns of the broken package can't be resolved.
+func (s *store) loadPackage(dir string) (*packages.Package, error) {
+	loaded, err := packages.Load(&packages.Config{Mode: loadPackageMode, Dir: dir, Fset: s.fileSet}, ".")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load package %s", dir)
+	}
+
+	if len(loaded) != 1 {
+		return nil, errors.Errorf("expected 1 package in %s, got %d", dir, len(loaded))
+	}
+
+	loadedPackage := loaded[0]
+
+	if len(loadedPackage.Errors) > 0 {
+		messages := make([]string, 0, len(loadedPackage.Errors))
+		for _, loadError := range loadedPackage.Errors {
+			messages = append(messages, loadError.Error())
 		}
+
+		return nil, errors.Errorf("failed to load package %s: %s", dir, strings.Join(messages, "; "))
 	}
 
-	return nil
+	maps.Copy(s.typesInfo.Types, loadedPackage.TypesInfo.Types)
+	maps.Copy(s.typesInfo.Defs, loadedPackage.TypesInfo.Defs)
+	maps.Copy(s.typesInfo.Uses, loadedPackage.TypesInfo.Uses)
+
+	return loadedPackage, nil
 }
 
-func (s *store) collectMappingKeyFileAst(fileName string, fileData *ast.File) error {
+func (s *store) collectMappingKeyFileAst(fileName string, fileData *ast.File, namespace string) error {
 	for objectName, object := range fileData.Scope.Objects {
-		if _, ok := object.Decl.(*ast.FuncDecl); ok {
-			continue // ignore functions
+		typeSpec, ok := object.Decl.(*ast.TypeSpec)
+		if !ok {
+			continue // ignore functions, constants and variables
+		}
+
+		if _, ok := typeSpec.Type.(*ast.StructType); !ok {
+			continue // ignore non struct types, they are resolved as field types
 		}
 
 		if _, ok := s.objectsMap[objectName]; ok {
@@ -60,24 +104,318 @@ func (s *store) collectMappingKeyFileAst(fileName string, fileData *ast.File) er
 
 		s.objectsMap[objectName] = object
 
-		if _, ok := s.structNamesIdentsMap[objectName]; ok {
-			panic("duplicate struct " + objectName)
+		if namespace != "" {
+			s.namespacesMap[objectName] = namespace
+		}
+
+		s.structNamesIdentsMap[object.Name] = typeSpec.Name
+
+		for _, field := range typeSpec.Type.(*ast.StructType).Fields.List {
+			ident, ok := field.Type.(*ast.Ident)
+			if !ok {
+				continue
+			}
+
+			isFlattened := field.Tag != nil && reflect.StructTag(strings.Trim(field.Tag.Value, "`")).Get(columnTagName) == "*"
+			if len(field.Names) == 0 || isFlattened {
+				s.embeddedNamesMap[ident.Name] = true
+			}
 		}
-		s.structNamesIdentsMap[object.Name] = object.Decl.(*ast.TypeSpec).Name
 	}
 
+	s.importsMap[fileName] = fileData.Imports
+
+	return nil
+}
+
+// collectMappingKeyFileComments - Collects mappings of the struct comments and detached "// StructName table_name"
+// comments, struct comment wins over the detached one. Other comments are documentation, e.g. of functions.
+func (s *store) collectMappingKeyFileComments(fileData *ast.File) error {
+	fieldComments, structDocs := s.collectDocComments(fileData)
+	constComments := s.collectConstComments(fileData)
+	detachedNames := make(map[string]string)
+
 	for _, comment := range fileData.Comments {
-		commentStr := strings.Trim(comment.Text(), "//")
-		commentParts := strings.Split(commentStr, " ")
+		if fieldComments[comment] || constComments[comment] {
+			continue
+		}
+
+		commentStr, directive, err := cutCommentDirective(comment, strings.Trim(comment.Text(), "//"))
+		if err != nil {
+			return errors.WithStack(err)
+		}
 
-		if len(commentParts) < 2 {
-			return errors.Errorf("expected 2 parts in comment %s", commentStr)
+		structName, isStructDoc := structDocs[comment]
+		if !isStructDoc {
+			if directive != nil {
+				return errors.Errorf("gormite directive must be in the struct comment: %s", comment.Text())
+			}
+
+			// Invalid checks of the detached comment are not errors, e.g. "check:" in comment of the function body
+			fields := strings.Fields(commentStr)
+			if len(fields) == 0 || s.objectsMap[fields[0]] == nil {
+				continue
+			}
+
+			if mapping, err := parseMappingLine(commentStr, fields[0]); err == nil && mapping != nil && mapping.tableName != "" {
+				detachedNames[fields[0]] = mapping.tableName
+			}
+
+			continue
+		}
+
+		if directive != nil {
+			s.tableDirectivesMap[structName] = directive
+		}
+
+		mapping, err := parseMappingLine(commentStr, structName)
+		if err != nil {
+			return errors.Wrapf(err, "invalid comment %s", comment.Text())
+		}
+
+		if mapping == nil {
+			continue // prose, e.g. documentation of the entity
 		}
 
-		s.namesMap[commentParts[0]] = commentParts[1]
+		if len(mapping.checks) > 0 {
+			s.checksMap[structName] = append(s.checksMap[structName], mapping.checks...)
+		}
+
+		if mapping.renamedFrom != "" {
+			s.renamedFromMap[structName] = mapping.renamedFrom
+		}
+
+		if mapping.idStrategy != "" {
+			if !slices.Contains(enums.IdStrategies, enums.IdStrategy(mapping.idStrategy)) {
+				return errors.Errorf("invalid id strategy %s of %s", mapping.idStrategy, structName)
+			}
+
+			s.idStrategiesMap[structName] = enums.IdStrategy(mapping.idStrategy)
+		}
+
+		if directive == nil && mapping.tableName != "" {
+			s.namesMap[structName] = mapping.tableName
+		}
 	}
 
-	s.importsMap[fileName] = fileData.Imports
+	for structName, tableName := range detachedNames {
+		if _, ok := s.namesMap[structName]; !ok {
+			s.namesMap[structName] = tableName
+		}
+	}
 
 	return nil
 }
+
+// collectDocComments - Collects comments of the structs into tableCommentsMap and returns comment groups of the struct
+// fields, they are comments of columns and not mapping comments, and names of the structs by their comments.
+func (s *store) collectDocComments(fileData *ast.File) (map[*ast.CommentGroup]bool, map[*ast.CommentGroup]string) {
+	fieldComments := make(map[*ast.CommentGroup]bool)
+	structDocs := make(map[*ast.CommentGroup]string)
+
+	for _, decl := range fileData.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+
+			structType, ok := typeSpec.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+
+			doc := typeSpec.Doc
+			if doc == nil && len(genDecl.Specs) == 1 {
+				doc = genDecl.Doc
+			}
+
+			if doc != nil {
+				structDocs[doc] = typeSpec.Name.Name
+			}
+
+			if comment := getTableComment(doc, typeSpec.Name.Name); comment != "" {
+				s.tableCommentsMap[typeSpec.Name.Name] = comment
+			}
+
+			for _, field := range structType.Fields.List {
+				for _, group := range []*ast.CommentGroup{field.Doc, field.Comment} {
+					if group != nil {
+						fieldComments[group] = true
+					}
+				}
+			}
+		}
+	}
+
+	return fieldComments, structDocs
+}
+
+// getTableComment - Struct comment is the table comment, like comments of the fields are comments of the columns.
+// The "StructName table_name" first line with its checks and hints and gormite directive lines are skipped,
+// comment= of the directive wins over it.
+func getTableComment(doc *ast.CommentGroup, structName string) string {
+	if doc == nil {
+		return ""
+	}
+
+	lines := strings.Split(strings.TrimSpace(doc.Text()), "\n")
+
+	if mapping, err := parseMappingLine(lines[0], structName); err != nil || mapping != nil {
+		lines = lines[1:]
+	}
+
+	commentLines := make([]string, 0)
+	for _, line := range lines {
+		if !isTableDirectiveLine(line) {
+			commentLines = append(commentLines, line)
+		}
+	}
+
+	return strings.TrimSpace(strings.Join(commentLines, "\n"))
+}
+
+// getColumnComment - Doc comment of the field is the column comment, line comment is used when there is no doc.
+func getColumnComment(field *ast.Field) string {
+	if field.Doc != nil {
+		return strings.TrimSpace(field.Doc.Text())
+	}
+
+	if field.Comment != nil {
+		return strings.TrimSpace(field.Comment.Text())
+	}
+
+	return ""
+}
+
+// isStructType - Checks whether the field type is a struct, which is flattened into the table when embedded.
+func (s *store) isStructType(fieldType ast.Expr) bool {
+	if ident, ok := fieldType.(*ast.Ident); ok {
+		if _, ok := s.objectsMap[ident.Name]; ok {
+			return true
+		}
+	}
+
+	if typ := s.typesInfo.TypeOf(fieldType); typ != nil {
+		_, ok := typ.Underlying().(*gotypes.Struct)
+		return ok
+	}
+
+	return false
+}
+
+// getStructType - Returns declaration of the struct type, structs of other packages are parsed from their sources.
+func (s *store) getStructType(fieldType ast.Expr) (*ast.StructType, error) {
+	var object gotypes.Object
+
+	switch fType := fieldType.(type) {
+	case *ast.Ident:
+		if mappingObject, ok := s.objectsMap[fType.Name]; ok {
+			return mappingObject.Decl.(*ast.TypeSpec).Type.(*ast.StructType), nil
+		}
+		object = s.typesInfo.Uses[fType]
+	case *ast.SelectorExpr:
+		object = s.typesInfo.Uses[fType.Sel]
+	}
+
+	if object == nil || !object.Pos().IsValid() {
+		return nil, errors.Errorf("declaration of %s not found", gotypes.ExprString(fieldType))
+	}
+
+	dir := filepath.Dir(s.fileSet.Position(object.Pos()).Filename)
+
+	typeSpecs, ok := s.packagesTypeSpecsMap[dir]
+	if !ok {
+		var err error
+		if typeSpecs, err = s.collectPackageTypeSpecs(dir); err != nil {
+			return nil, err
+		}
+
+		s.packagesTypeSpecsMap[dir] = typeSpecs
+	}
+
+	if typeSpec, ok := typeSpecs[object.Name()]; ok {
+		if structType, ok := typeSpec.Type.(*ast.StructType); ok {
+			return structType, nil
+		}
+	}
+
+	return nil, errors.Errorf("struct %s not found in %s", object.Name(), dir)
+}
+
+// collectPackageTypeSpecs - Loads package which is not a mapping, e.g. package of embedded structs.
+func (s *store) collectPackageTypeSpecs(dir string) (map[string]*ast.TypeSpec, error) {
+	loadedPackage, err := s.loadPackage(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	typeSpecs := make(map[string]*ast.TypeSpec)
+
+	for _, file := range loadedPackage.Syntax {
+		s.importsMap[s.fileSet.Position(file.Package).Filename] = file.Imports
+
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+
+			for _, spec := range genDecl.Specs {
+				if typeSpec, ok := spec.(*ast.TypeSpec); ok {
+					typeSpecs[typeSpec.Name.Name] = typeSpec
+				}
+			}
+		}
+	}
+
+	return typeSpecs, nil
+}
+
+// mappingLine - Table name, hints and checks of the "StructName [schema.]table_name" line.
+type mappingLine struct {
+	tableName   string
+	renamedFrom string
+	idStrategy  string
+	checks      []*checkDefinition
+}
+
+// parseMappingLine - Parses the first line of the comment, e.g. "// User users renamed_from:old_users id_strategy:identity".
+// Table name is optional with hints and checks. Nil is returned for prose, e.g. "// User is a customer of the shop",
+// hints and checks of the following lines are prose too.
+func parseMappingLine(commentStr string, structName string) (*mappingLine, error) {
+	line, _, _ := strings.Cut(strings.TrimSpace(commentStr), "\n")
+
+	if fields := strings.Fields(line); len(fields) == 0 || fields[0] != structName {
+		return nil, nil
+	}
+
+	line, checks, err := cutCommentChecks(line)
+	if err != nil {
+		return nil, err
+	}
+
+	mapping := &mappingLine{checks: checks}
+
+	for i, commentPart := range strings.Fields(line)[1:] {
+		if value, ok := strings.CutPrefix(commentPart, renamedFromTagName+":"); ok {
+			mapping.renamedFrom = value
+			continue
+		}
+
+		if value, ok := strings.CutPrefix(commentPart, idStrategyTagName+":"); ok {
+			mapping.idStrategy = value
+			continue
+		}
+
+		if i != 0 {
+			return nil, nil
+		}
+
+		mapping.tableName = commentPart
+	}
+
+	return mapping, nil
+}
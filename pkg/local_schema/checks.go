@@ -0,0 +1,132 @@
+package local_schema
+
+import (
+	"github.com/pkg/errors"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type checkDefinition struct {
+	name       string
+	expression string
+}
+
+// parseCheckTag - Parses value of the check tag, e.g. "price_positive:(price > 0), (price < 1000)", checks without
+// name get the name of the column check, see addCheckConstraint.
+func parseCheckTag(value string) ([]*checkDefinition, error) {
+	definitions := make([]*checkDefinition, 0)
+
+	for {
+		value = strings.TrimLeftFunc(value, func(r rune) bool { return unicode.IsSpace(r) || r == ',' })
+		if value == "" {
+			return definitions, nil
+		}
+
+		definition, rest, err := parseCheckDefinition(value)
+		if err != nil {
+			return nil, err
+		}
+
+		definitions = append(definitions, definition)
+		value = rest
+	}
+}
+
+// cutCommentChecks - Cuts "check:name:(expression)" definitions out of the struct comment, expressions can contain
+// spaces, so they are cut before the comment is split into parts.
+func cutCommentChecks(comment string) (string, []*checkDefinition, error) {
+	prefix := checkTagName + ":"
+	definitions := make([]*checkDefinition, 0)
+
+	for {
+		start := strings.Index(comment, prefix)
+		for start > 0 && !unicode.IsSpace(rune(comment[start-1])) {
+			next := strings.Index(comment[start+1:], prefix)
+			if next == -1 {
+				start = -1
+				break
+			}
+			start += next + 1
+		}
+
+		if start == -1 {
+			return comment, definitions, nil
+		}
+
+		definition, rest, err := parseCheckDefinition(comment[start+len(prefix):])
+		if err != nil {
+			return "", nil, err
+		}
+
+		if definition.name == "" {
+			return "", nil, errors.Errorf("name of table check %s is required", definition.expression)
+		}
+
+		definitions = append(definitions, definition)
+		comment = comment[:start] + rest
+	}
+}
+
+// parseCheckDefinition - Parses "[name:](expression)" from the start of value and returns the rest of value.
+// Parentheses inside string literals are not counted.
+func parseCheckDefinition(value string) (*checkDefinition, string, error) {
+	name := ""
+	if !strings.HasPrefix(value, "(") {
+		var ok bool
+		name, value, ok = strings.Cut(value, ":")
+		if !ok || name == "" || strings.ContainsFunc(name, unicode.IsSpace) {
+			return nil, "", errors.Errorf("invalid check %s, expected name:(expression)", name+value)
+		}
+	}
+
+	if !strings.HasPrefix(value, "(") {
+		return nil, "", errors.Errorf("expression of check %s must be wrapped in parentheses", name)
+	}
+
+	depth := 0
+	inString := false
+	for i, r := range value {
+		switch {
+		case r == '\'':
+			inString = !inString
+		case inString:
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+			if depth == 0 {
+				expression := strings.TrimSpace(value[1:i])
+				if expression == "" {
+					return nil, "", errors.Errorf("expression of check %s is empty", name)
+				}
+
+				return &checkDefinition{name: name, expression: expression}, value[i+1:], nil
+			}
+		}
+	}
+
+	return nil, "", errors.Errorf("unbalanced parentheses in check %s", name)
+}
+
+// addCheckConstraint - Adds check constraint to the table, constraint without name is named like postgres does it,
+// e.g. products_price_check, products_price_check1.
+func (t *tableBag) addCheckConstraint(definition *checkDefinition, columnName string) error {
+	name := definition.name
+	if name == "" {
+		baseName := t.table.GetShortestName(t.table.GetNamespaceName()) + "_" + columnName + "_check"
+
+		name = baseName
+		for i := 1; t.table.HasCheckConstraint(name); i++ {
+			name = baseName + strconv.Itoa(i)
+		}
+	}
+
+	if t.table.HasCheckConstraint(name) {
+		return errors.Errorf("duplicate check %s of table %s", name, t.table.GetName())
+	}
+
+	t.table.AddCheckConstraint(name, definition.expression)
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package local_schema
+
+import (
+	"github.com/KoNekoD/gormite/pkg/utils"
+	"github.com/fatih/structtag"
+	"github.com/pkg/errors"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	gotypes "go/types"
+	"slices"
+	"strings"
+)
+
+type enumTypeDefinition struct {
+	values []string
+	// renamedFrom - Rename hints of the values, keys are values and values are their old names
+	renamedFrom map[string]string
+}
+
+// addEnumType - Declares enum type of the named go type and returns its name. Values of the enum type are string
+// constants of the go type in the order of declaration. Name is the snake cased name of the go type, enum tag
+// overrides it.
+func (s *store) addEnumType(fieldType ast.Expr, tags *structtag.Tags) (string, error) {
+	var named *gotypes.Named
+	if typ := s.typesInfo.TypeOf(fieldType); typ != nil {
+		named, _ = gotypes.Unalias(typ).(*gotypes.Named)
+	}
+
+	if named == nil || named.Obj().Pkg() == nil {
+		return "", errors.Errorf("enum type must be named go type, got %s", gotypes.ExprString(fieldType))
+	}
+
+	name := utils.ToSnakeCase(named.Obj().Name())
+	if enumTag, _ := tags.Get(enumTagName); enumTag != nil {
+		name = enumTag.Value()
+	}
+
+	definition, err := s.getEnumTypeDefinition(named)
+	if err != nil {
+		return "", err
+	}
+
+	if existing, ok := s.enumTypes[name]; ok && !slices.Equal(existing.values, definition.values) {
+		return "", errors.Errorf("enum type %s is declared with different values %v and %v", name, existing.values, definition.values)
+	}
+
+	s.enumTypes[name] = definition
+
+	return name, nil
+}
+
+// getEnumTypeDefinition - Collects string constants of the named type declared in its package.
+func (s *store) getEnumTypeDefinition(named *gotypes.Named) (*enumTypeDefinition, error) {
+	scope := named.Obj().Pkg().Scope()
+
+	constants := make([]*gotypes.Const, 0)
+	for _, objectName := range scope.Names() {
+		if c, ok := scope.Lookup(objectName).(*gotypes.Const); ok && gotypes.Identical(c.Type(), named) {
+			constants = append(constants, c)
+		}
+	}
+
+	if len(constants) == 0 {
+		return nil, errors.Errorf("enum type %s has no constants", named.Obj().Name())
+	}
+
+	slices.SortFunc(constants, func(a, b *gotypes.Const) int { return int(a.Pos() - b.Pos()) })
+
+	definition := &enumTypeDefinition{values: make([]string, 0, len(constants)), renamedFrom: make(map[string]string)}
+	for _, c := range constants {
+		if c.Val().Kind() != constant.String {
+			return nil, errors.Errorf("constant %s of enum type %s must be string", c.Name(), named.Obj().Name())
+		}
+
+		value := constant.StringVal(c.Val())
+		if slices.Contains(definition.values, value) {
+			continue // aliases of the value, e.g. StatusDefault = StatusNew
+		}
+
+		definition.values = append(definition.values, value)
+
+		if renamedFrom, ok := s.constRenamedFromMap[c.Pos()]; ok {
+			definition.renamedFrom[value] = renamedFrom
+		}
+	}
+
+	return definition, nil
+}
+
+// collectConstComments - Collects renamed_from hints of the constants into constRenamedFromMap and returns comment
+// groups of the constants, they are not mapping comments.
+func (s *store) collectConstComments(fileData *ast.File) map[*ast.CommentGroup]bool {
+	constComments := make(map[*ast.CommentGroup]bool)
+
+	for _, decl := range fileData.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.CONST {
+			continue
+		}
+
+		if genDecl.Doc != nil {
+			constComments[genDecl.Doc] = true
+		}
+
+		for _, spec := range genDecl.Specs {
+			valueSpec := spec.(*ast.ValueSpec)
+
+			for _, group := range []*ast.CommentGroup{valueSpec.Doc, valueSpec.Comment} {
+				if group == nil {
+					continue
+				}
+
+				constComments[group] = true
+
+				for _, commentPart := range strings.Fields(group.Text()) {
+					if value, ok := strings.CutPrefix(commentPart, renamedFromTagName+":"); ok && len(valueSpec.Names) == 1 {
+						s.constRenamedFromMap[valueSpec.Names[0].Pos()] = value
+					}
+				}
+			}
+		}
+	}
+
+	return constComments
+}
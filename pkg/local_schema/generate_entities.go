@@ -0,0 +1,681 @@
+package local_schema
+
+import (
+	"fmt"
+	"github.com/KoNekoD/gormite/pkg/assets"
+	"github.com/KoNekoD/gormite/pkg/enums"
+	"github.com/KoNekoD/gormite/pkg/types"
+	"github.com/KoNekoD/gormite/pkg/utils"
+	"github.com/charmbracelet/log"
+	"github.com/fatih/structtag"
+	"github.com/pkg/errors"
+	"go/format"
+	"maps"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// GeneratedEntity - Source of the entity struct generated for a table.
+type GeneratedEntity struct {
+	StructName string
+	TableName  string
+	FileName   string
+	Content    []byte
+}
+
+type generatedField struct {
+	name    string
+	goType  string
+	tags    *structtag.Tags
+	comment string
+}
+
+// GenerateEntities - Generates entity structs which are read back by IntrospectLocalSchema
+// into the same tables. Columns which can't be described with tags are skipped with warning. Names which are
+// generated by the naming strategy on introspection are not set by tags.
+func GenerateEntities(schema *assets.Schema, packageName string, naming NamingStrategy) ([]*GeneratedEntity, error) {
+	tables := schema.GetTables()
+	slices.SortFunc(tables, func(a, b *assets.Table) int { return strings.Compare(a.GetName(), b.GetName()) })
+
+	structNames := make(map[string]string)
+	for _, table := range tables {
+		structNames[table.GetName()] = getGoIdentifier(table.GetName())
+	}
+
+	// Enum types are declared in the file of the first table which uses them
+	enumTypeOwners := make(map[string]string)
+	for _, table := range tables {
+		for _, column := range table.GetColumns() {
+			if enumType := getColumnEnumType(column); enumType != nil {
+				if _, ok := enumTypeOwners[enumType.GetName()]; !ok {
+					enumTypeOwners[enumType.GetName()] = table.GetName()
+				}
+			}
+		}
+	}
+
+	entities := make([]*GeneratedEntity, 0, len(tables))
+
+	for _, table := range tables {
+		content, err := generateEntity(schema, table, structNames, enumTypeOwners, packageName, naming)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to generate entity for table %s", table.GetName())
+		}
+
+		entities = append(
+			entities, &GeneratedEntity{
+				StructName: structNames[table.GetName()],
+				TableName:  table.GetName(),
+				FileName:   strings.ReplaceAll(table.GetName(), ".", "_") + ".go",
+				Content:    content,
+			},
+		)
+	}
+
+	return entities, nil
+}
+
+func generateEntity(
+	schema *assets.Schema,
+	table *assets.Table,
+	structNames map[string]string,
+	enumTypeOwners map[string]string,
+	packageName string,
+	naming NamingStrategy,
+) ([]byte, error) {
+	tableName := table.GetName()
+	shortTableName := table.GetShortestName(table.GetNamespaceName())
+
+	primaryKeys := make([]string, 0)
+	if pk := table.GetPrimaryKey(); pk != nil {
+		primaryKeys = pk.GetColumns()
+	}
+
+	idStrategy := getIdStrategy(schema, table, naming)
+
+	foreignKeys := make(map[string]*assets.ForeignKeyConstraint)
+	compositeForeignKeys := make(map[string]*assets.ForeignKeyConstraint)
+	for _, fk := range table.GetForeignKeys() {
+		_, isKnownTable := structNames[fk.GetForeignTableName()]
+		localColumns := fk.GetLocalColumns()
+
+		isComposite := len(localColumns) > 1 && len(localColumns) == len(fk.GetForeignColumns())
+		if isComposite && isKnownTable && !slices.ContainsFunc(localColumns, func(column string) bool {
+			_, ok := compositeForeignKeys[column]
+			return ok
+		}) {
+			for _, column := range localColumns {
+				compositeForeignKeys[column] = fk
+			}
+			continue
+		}
+
+		if len(localColumns) != 1 || len(fk.GetForeignColumns()) != 1 || !isKnownTable {
+			log.Warnf(
+				"Foreign key %s of table %s must reference single column of the imported table, generated as plain column",
+				fk.GetName(),
+				tableName,
+			)
+			continue
+		}
+
+		foreignKeys[localColumns[0]] = fk
+	}
+
+	indexTags := make(map[string][]string)
+	indexCondTags := make(map[string][]string)
+	indexUsingTags := make(map[string][]string)
+	indexOrderTags := make(map[string][]string)
+	indexOpclassTags := make(map[string][]string)
+	indexExpressionTags := make(map[string][]string)
+	indexIncludeTags := make(map[string][]string)
+	indexPositionTags := make(map[string][]string)
+	uniqTags := make(map[string][]string)
+	uniqCondTags := make(map[string][]string)
+
+	indexes := table.GetIndexes()
+	indexNames := make([]string, 0, len(indexes))
+	for name := range indexes {
+		indexNames = append(indexNames, name)
+	}
+	slices.Sort(indexNames)
+
+	for _, indexName := range indexNames {
+		index := indexes[indexName]
+		if index.IsPrimary() {
+			continue
+		}
+
+		if len(index.GetFlags()) > 0 {
+			log.Warnf("Flags of index %s on table %s are not supported, skipped", index.GetName(), tableName)
+		}
+
+		columns := index.GetColumns()
+
+		nameTags, condTags := indexTags, indexCondTags
+		if index.IsUnique() {
+			nameTags, condTags = uniqTags, uniqCondTags
+
+			if hasAdvancedIndexOptions(index) {
+				log.Warnf("Unique index %s on table %s has expressions or options which are not supported, skipped", index.GetName(), tableName)
+				continue
+			}
+
+			if name := naming.UniqueIndexName(shortTableName, columns); index.GetName() != name {
+				log.Warnf("Unique index %s on table %s will be renamed to %s", index.GetName(), tableName, name)
+			}
+		}
+
+		// Expressions are set by the index_expr tag of the column they use
+		fieldColumns, ok := getIndexFieldColumns(table, index, columns)
+		if !ok {
+			log.Warnf("Expressions of index %s on table %s must use distinct columns of the table, skipped", index.GetName(), tableName)
+			continue
+		}
+
+		// Columns are in the order of the fields unless their positions are set
+		isFieldOrder := slices.IsSortedFunc(fieldColumns, func(a, b string) int {
+			return getColumnPosition(table, a) - getColumnPosition(table, b)
+		})
+
+		for i, column := range columns {
+			fieldColumn := fieldColumns[i]
+
+			nameTags[fieldColumn] = append(nameTags[fieldColumn], index.GetName())
+
+			if !isFieldOrder {
+				indexPositionTags[fieldColumn] = append(indexPositionTags[fieldColumn], fmt.Sprintf("%s:%d", index.GetName(), i+1))
+			}
+
+			if index.IsColumnExpression(column) {
+				indexExpressionTags[fieldColumn] = append(indexExpressionTags[fieldColumn], index.GetName()+":"+column)
+			}
+			if order := index.GetColumnOrder(column); order != "" {
+				indexOrderTags[fieldColumn] = append(indexOrderTags[fieldColumn], index.GetName()+":"+strings.ToLower(order))
+			}
+			if opclass := index.GetColumnOpclass(column); opclass != "" {
+				indexOpclassTags[fieldColumn] = append(indexOpclassTags[fieldColumn], index.GetName()+":"+opclass)
+			}
+		}
+
+		if using := index.GetUsing(); using != "" {
+			indexUsingTags[fieldColumns[0]] = append(indexUsingTags[fieldColumns[0]], index.GetName()+":"+using)
+		}
+
+		for _, column := range index.GetIncludeColumns() {
+			indexIncludeTags[column] = append(indexIncludeTags[column], index.GetName())
+		}
+
+		if index.HasOption("where") {
+			condition := fmt.Sprintf("%s:%s", index.GetName(), index.GetOption("where"))
+			condTags[fieldColumns[0]] = append(condTags[fieldColumns[0]], condition)
+		}
+	}
+
+	fields := make([]*generatedField, 0)
+	usedNames := make(map[string]bool)
+	imports := make(map[string]bool)
+
+	for _, column := range table.GetColumns() {
+		columnName := column.GetName()
+
+		tags := &structtag.Tags{}
+		setTag := func(key, value string) { _ = tags.Set(&structtag.Tag{Key: key, Name: value}) }
+
+		setTag(columnTagName, columnName)
+
+		if slices.Contains(primaryKeys, columnName) {
+			setTag(primaryKeyTagName, "true")
+		}
+
+		if !column.GetNotNull() {
+			setTag(isNullableTagName, "true")
+		}
+
+		fieldName := getGoIdentifier(columnName)
+
+		var goType string
+
+		if fk, ok := foreignKeys[columnName]; ok {
+			goType = "*" + structNames[fk.GetForeignTableName()]
+
+			if refName := getGoIdentifier(strings.TrimSuffix(columnName, "_id")); !usedNames[refName] {
+				fieldName = refName
+			}
+
+			foreignTable := schema.GetTable(fk.GetForeignTableName())
+			foreignColumnName := fk.GetForeignColumns()[0]
+
+			if pk := foreignTable.GetPrimaryKey(); pk == nil || !slices.Equal(pk.GetColumns(), []string{foreignColumnName}) {
+				setTag(referencesTagName, foreignColumnName)
+			}
+
+			// Type of the relation is taken from the referenced column, so it is set only when they differ
+			foreignColumnType := foreignTable.GetColumn(foreignColumnName).GetColumnType()
+			if reflect.TypeOf(column.GetColumnType()) != reflect.TypeOf(foreignColumnType) {
+				switch column.GetColumnType().(type) {
+				case *types.IntegerType:
+					setTag(typeTagName, "integer")
+				case *types.BigintType:
+					setTag(typeTagName, "bigint")
+				default:
+					log.Warnf(
+						"Type %T of column %s on table %s differs from referenced column, type of referenced column is used",
+						column.GetColumnType(),
+						columnName,
+						tableName,
+					)
+				}
+			}
+		} else {
+			var importPath string
+			var ok bool
+
+			goType, importPath, ok = getEntityFieldType(column, column.GetColumnType(), setTag)
+			if !ok {
+				log.Warnf(
+					"Type %T of column %s on table %s is not supported, skipped",
+					column.GetColumnType(),
+					columnName,
+					tableName,
+				)
+				continue
+			}
+
+			if importPath != "" {
+				imports[importPath] = true
+			}
+
+			if !column.GetNotNull() && !strings.HasPrefix(goType, "json.") && !strings.HasPrefix(goType, "[]") {
+				goType = "*" + goType
+			}
+		}
+
+		// Default of the serial id is set by the id strategy
+		isSerialId := idStrategy == enums.IdStrategySerial && slices.Contains(primaryKeys, columnName)
+		if column.GetColumnDefault() != nil && !isSerialId {
+			setTag(defaultValueTagName, *column.GetColumnDefault())
+		}
+
+		if column.GetGenerated() != nil {
+			setTag(generatedTagName, *column.GetGenerated())
+		}
+
+		for _, tag := range []struct {
+			key    string
+			values []string
+			sep    string
+		}{
+			{indexTagName, indexTags[columnName], ","},
+			{indexConditionTagName, indexCondTags[columnName], ";"},
+			{indexUsingTagName, indexUsingTags[columnName], ";"},
+			{indexExpressionTagName, indexExpressionTags[columnName], ";"},
+			{indexOrderTagName, indexOrderTags[columnName], ";"},
+			{indexOpclassTagName, indexOpclassTags[columnName], ";"},
+			{indexIncludeTagName, indexIncludeTags[columnName], ","},
+			{uniqueConstraintTagName, uniqTags[columnName], ","},
+			{uniqueConstraintConditionTagName, uniqCondTags[columnName], ";"},
+			{indexPositionTagName, indexPositionTags[columnName], ";"},
+		} {
+			if len(tag.values) > 0 {
+				setTag(tag.key, strings.Join(tag.values, tag.sep))
+			}
+		}
+
+		fk, isForeignKey := foreignKeys[columnName]
+
+		if compositeFk, ok := compositeForeignKeys[columnName]; ok {
+			value := compositeFk.GetForeignTableName()
+
+			foreignTable := schema.GetTable(compositeFk.GetForeignTableName())
+			if pk := foreignTable.GetPrimaryKey(); pk == nil || !slices.Equal(pk.GetColumns(), compositeFk.GetForeignColumns()) {
+				value += ":" + strings.Join(compositeFk.GetForeignColumns(), ",")
+			}
+
+			setTag(foreignKeyTagName, value)
+
+			fk, isForeignKey = compositeFk, true
+		}
+
+		if isForeignKey {
+			if fk.OnUpdate() != nil {
+				setTag(onUpdateTagName, *fk.OnUpdate())
+			}
+			if fk.OnDelete() != nil {
+				setTag(onDeleteTagName, *fk.OnDelete())
+			}
+		}
+
+		if column.GetAutoincrement() && idStrategy != enums.IdStrategyIdentity {
+			log.Warnf("Autoincrement of column %s on table %s is not supported, skipped", columnName, tableName)
+		}
+
+		for i := 2; usedNames[fieldName]; i++ {
+			fieldName = fmt.Sprintf("%s%d", getGoIdentifier(columnName), i)
+		}
+
+		usedNames[fieldName] = true
+		fields = append(
+			fields,
+			&generatedField{name: fieldName, goType: goType, tags: tags, comment: column.GetComment()},
+		)
+	}
+
+	src := &strings.Builder{}
+
+	_, _ = fmt.Fprintf(src, "package %s\n\n", packageName)
+
+	if len(imports) > 0 {
+		importPaths := make([]string, 0, len(imports))
+		for importPath := range imports {
+			importPaths = append(importPaths, strconv.Quote(importPath))
+		}
+		slices.Sort(importPaths)
+
+		_, _ = fmt.Fprintf(src, "import (\n%s\n)\n\n", strings.Join(importPaths, "\n"))
+	}
+
+	// "// StructName [schema.]table_name" line holds hints and checks, lines after it are the table comment
+	structName := structNames[tableName]
+	_, _ = fmt.Fprintf(src, "// %s %s", structName, tableName)
+	if idStrategy != "" {
+		_, _ = fmt.Fprintf(src, " %s:%s", idStrategyTagName, idStrategy)
+	}
+	for _, checkName := range slices.Sorted(maps.Keys(table.GetCheckConstraints())) {
+		check := table.GetCheckConstraint(checkName)
+		expression := strings.Join(strings.Fields(check.GetExpression()), " ")
+
+		_, _ = fmt.Fprintf(src, " %s:%s:(%s)", checkTagName, check.GetName(), expression)
+	}
+	if comment := table.GetComment(); comment != nil && *comment != "" {
+		src.WriteString("\n" + getGoComment(*comment))
+	} else {
+		src.WriteString("\n")
+	}
+	_, _ = fmt.Fprintf(src, "type %s struct {\n", structName)
+	for _, field := range fields {
+		if field.comment != "" {
+			src.WriteString(getGoComment(field.comment))
+		}
+		_, _ = fmt.Fprintf(src, "%s %s `%s`\n", field.name, field.goType, field.tags.String())
+	}
+	src.WriteString("}\n")
+
+	for _, enumTypeName := range slices.Sorted(maps.Keys(enumTypeOwners)) {
+		if enumTypeOwners[enumTypeName] != tableName {
+			continue
+		}
+
+		if !schema.HasEnumType(enumTypeName) {
+			log.Warnf("Enum type %s of table %s is not found, its declaration is skipped", enumTypeName, tableName)
+			continue
+		}
+
+		src.WriteString(getGoEnumType(schema.GetEnumType(enumTypeName)))
+	}
+
+	content, err := format.Source([]byte(src.String()))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return content, nil
+}
+
+// getIdStrategy - Returns id strategy of the table, which is empty for the default sequence strategy, see
+// introspectSequences.
+func getIdStrategy(schema *assets.Schema, table *assets.Table, naming NamingStrategy) enums.IdStrategy {
+	pk := table.GetPrimaryKey()
+	if pk == nil || len(pk.GetColumns()) != 1 {
+		return ""
+	}
+
+	columnName := pk.GetColumns()[0]
+	column := table.GetColumn(columnName)
+	seqName := getIdSequenceName(naming, table, columnName)
+
+	if column.GetAutoincrement() {
+		return enums.IdStrategyIdentity
+	}
+
+	if columnDefault := column.GetColumnDefault(); columnDefault != nil && *columnDefault == getSerialDefault(seqName) {
+		return enums.IdStrategySerial
+	}
+
+	isForeignKey := slices.ContainsFunc(
+		slices.Collect(maps.Values(table.GetForeignKeys())),
+		func(fk *assets.ForeignKeyConstraint) bool { return slices.Contains(fk.GetLocalColumns(), columnName) },
+	)
+
+	// Sequence would be created for the integer id by default
+	if _, ok := column.GetColumnType().(*types.IntegerType); ok && !isForeignKey && !schema.HasSequence(seqName) {
+		return enums.IdStrategyNone
+	}
+
+	return ""
+}
+
+// getGoComment - Returns the database comment as go line comments.
+func getGoComment(comment string) string {
+	src := &strings.Builder{}
+
+	for _, line := range strings.Split(comment, "\n") {
+		if line = strings.TrimRightFunc(line, unicode.IsSpace); line == "" {
+			src.WriteString("//\n")
+			continue
+		}
+
+		src.WriteString("// " + line + "\n")
+	}
+
+	return src.String()
+}
+
+// hasAdvancedIndexOptions - Returns whether the index has options which are set by the index_* tags only.
+func hasAdvancedIndexOptions(index *assets.Index) bool {
+	if index.GetUsing() != "" || len(index.GetIncludeColumns()) > 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(
+		index.GetColumns(), func(column string) bool {
+			return index.IsColumnExpression(column) || index.GetColumnOrder(column) != "" || index.GetColumnOpclass(column) != ""
+		},
+	)
+}
+
+// getColumnPosition - Returns position of the column in the table, it is the position of the field in the struct.
+func getColumnPosition(table *assets.Table, columnName string) int {
+	return slices.IndexFunc(table.GetColumns(), func(c *assets.Column) bool { return c.GetName() == columnName })
+}
+
+// getIndexFieldColumns - Returns columns of the fields which get the tags of the index columns, expression is
+// tagged on the first column it uses which is not used by other columns of the index.
+func getIndexFieldColumns(table *assets.Table, index *assets.Index, columns []string) ([]string, bool) {
+	fieldColumns := make([]string, len(columns))
+	usedColumns := make(map[string]bool)
+
+	for i, column := range columns {
+		if !index.IsColumnExpression(column) {
+			fieldColumns[i] = column
+			usedColumns[column] = true
+		}
+	}
+
+	for i, column := range columns {
+		if !index.IsColumnExpression(column) {
+			continue
+		}
+
+		for _, tableColumn := range table.GetColumns() {
+			name := tableColumn.GetName()
+			if usedColumns[name] || !regexp.MustCompile(`\b`+regexp.QuoteMeta(name)+`\b`).MatchString(column) {
+				continue
+			}
+
+			fieldColumns[i] = name
+			usedColumns[name] = true
+			break
+		}
+
+		if fieldColumns[i] == "" {
+			return nil, false
+		}
+	}
+
+	return fieldColumns, true
+}
+
+// getColumnEnumType - Returns enum type of the column or of the elements of the array column.
+func getColumnEnumType(column *assets.Column) *types.EnumType {
+	columnType := column.GetColumnType()
+	if arrayType, ok := columnType.(*types.ArrayType); ok {
+		columnType = arrayType.GetElementType()
+	}
+
+	enumType, _ := columnType.(*types.EnumType)
+
+	return enumType
+}
+
+// getEnumGoTypeName - Returns name of the go type of the enum type, schema of the enum type is not included.
+func getEnumGoTypeName(enumTypeName string) string {
+	if _, name, ok := strings.Cut(enumTypeName, "."); ok {
+		enumTypeName = name
+	}
+
+	return getGoIdentifier(enumTypeName)
+}
+
+// getGoEnumType - Returns declaration of the go type with a constant for every value of the enum type.
+func getGoEnumType(enumType *assets.EnumType) string {
+	goTypeName := getEnumGoTypeName(enumType.GetName())
+
+	src := &strings.Builder{}
+	_, _ = fmt.Fprintf(src, "\ntype %s string\n\nconst (\n", goTypeName)
+
+	usedNames := make(map[string]bool)
+	for _, value := range enumType.GetValues() {
+		// Values can contain any characters, only letters and digits are kept in the constant name
+		baseName := goTypeName + strings.Map(
+			func(r rune) rune {
+				if unicode.IsLetter(r) || unicode.IsDigit(r) {
+					return r
+				}
+				return -1
+			},
+			utils.ToPascalCase(value),
+		)
+
+		constName := baseName
+		for i := 2; usedNames[constName]; i++ {
+			constName = fmt.Sprintf("%s%d", baseName, i)
+		}
+		usedNames[constName] = true
+
+		_, _ = fmt.Fprintf(src, "%s %s = %s\n", constName, goTypeName, strconv.Quote(value))
+	}
+
+	src.WriteString(")\n")
+
+	return src.String()
+}
+
+// getEntityFieldType - Returns go type of the field and sets tags which are needed to restore the column type.
+func getEntityFieldType(
+	column *assets.Column,
+	columnType types.AbstractTypeInterface,
+	setTag func(key, value string),
+) (string, string, bool) {
+	switch columnType := columnType.(type) {
+	case *types.ArrayType:
+		switch columnType.GetElementType().(type) {
+		case *types.ArrayType, *types.JsonType:
+			return "", "", false
+		case *types.StringType:
+			// Arrays of strings are text[] by default
+			setTag(typeTagName, "varchar")
+		}
+
+		goType, importPath, ok := getEntityFieldType(column, columnType.GetElementType(), setTag)
+		return "[]" + goType, importPath, ok
+	case *types.IntegerType:
+		return "int", "", true
+	case *types.BigintType:
+		return "int64", "", true
+	case *types.SmallIntType:
+		return "int16", "", true
+	case *types.BlobType:
+		return "[]byte", "", true
+	case *types.StringType:
+		if column.GetFixed() {
+			return "", "", false
+		}
+
+		if column.GetLength() == nil {
+			log.Warnf("Column %s has no length, it will be generated as varchar(255)", column.GetName())
+		} else if *column.GetLength() != 255 {
+			setTag(lengthTagName, strconv.Itoa(*column.GetLength()))
+		}
+		return "string", "", true
+	case *types.TextType:
+		setTag(typeTagName, "text")
+		return "string", "", true
+	case *types.GuidType:
+		setTag(typeTagName, "uuid")
+		return "string", "", true
+	case *types.BooleanType:
+		return "bool", "", true
+	case *types.FloatType:
+		return "float64", "", true
+	case *types.SmallFloatType:
+		return "float32", "", true
+	case *types.DecimalType:
+		setTag(typeTagName, "decimal")
+		if column.GetPrecision() != nil {
+			setTag(precisionTagName, strconv.Itoa(*column.GetPrecision()))
+		}
+		if column.GetScale() != nil {
+			setTag(scaleTagName, strconv.Itoa(*column.GetScale()))
+		}
+		return "float64", "", true
+	case *types.JsonType:
+		if jsonb, ok := column.GetPlatformOption("jsonb").(*bool); ok && jsonb != nil && *jsonb {
+			setTag(typeTagName, "jsonb")
+		} else if jsonb, ok := column.GetPlatformOption("jsonb").(bool); ok && jsonb {
+			setTag(typeTagName, "jsonb")
+		} else {
+			setTag(typeTagName, "json")
+		}
+		return "json.RawMessage", "encoding/json", true
+	case *types.DateTimeType, *types.DateTimeImmutableType:
+		return "time.Time", "time", true
+	case *types.DateIntervalType:
+		return "time.Duration", "time", true
+	case *types.EnumType:
+		goTypeName := getEnumGoTypeName(columnType.GetName())
+
+		setTag(typeTagName, "enum")
+		if utils.ToSnakeCase(goTypeName) != columnType.GetName() {
+			setTag(enumTagName, columnType.GetName())
+		}
+		return goTypeName, "", true
+	}
+
+	return "", "", false
+}
+
+// getGoIdentifier - Converts snake_case name to exported go identifier.
+func getGoIdentifier(name string) string {
+	identifier := utils.ToPascalCase(name)
+
+	if identifier == "" || !unicode.IsLetter([]rune(identifier)[0]) {
+		identifier = "X" + identifier
+	}
+
+	return identifier
+}
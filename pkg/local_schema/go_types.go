@@ -0,0 +1,232 @@
+package local_schema
+
+import (
+	"github.com/KoNekoD/gormite/pkg/enums"
+	"go/ast"
+	gotypes "go/types"
+	"path"
+	"regexp"
+	"strconv"
+)
+
+// goColumnType - Column type which is inferred from the go type of the field, type tag overrides it.
+type goColumnType struct {
+	typeName enums.TypesType
+	// nullable - Type is a nullable wrapper, column is nullable without the nullable tag
+	nullable  bool
+	jsonb     bool
+	precision *int
+	scale     *int
+}
+
+// goColumnTypesMap - Go types which are mapped to columns, keys are type names qualified by the package path.
+// Named types which are not listed are mapped by their underlying type, e.g. `type Status string` is string.
+var goColumnTypesMap = newGoColumnTypesMap()
+
+func newGoColumnTypesMap() map[string]*goColumnType {
+	uint64Precision, uint64Scale := 20, 0
+
+	typesMap := map[string]*goColumnType{
+		"bool":    {typeName: enums.TypeBoolean},
+		"int":     {typeName: enums.TypeInteger},
+		"int8":    {typeName: enums.TypeSmallint},
+		"int16":   {typeName: enums.TypeSmallint},
+		"int32":   {typeName: enums.TypeInteger},
+		"int64":   {typeName: enums.TypeBigint},
+		"uint":    {typeName: enums.TypeBigint},
+		"uint8":   {typeName: enums.TypeSmallint},
+		"uint16":  {typeName: enums.TypeInteger},
+		"uint32":  {typeName: enums.TypeBigint},
+		"uint64":  {typeName: enums.TypeDecimal, precision: &uint64Precision, scale: &uint64Scale},
+		"float32": {typeName: enums.TypeSmallfloat},
+		"float64": {typeName: enums.TypeFloat},
+		"string":  {typeName: enums.TypeString},
+
+		"byte":                   {typeName: enums.TypeSmallint},
+		"rune":                   {typeName: enums.TypeInteger},
+		"[]byte":                 {typeName: enums.TypeBlob},
+		"[]uint8":                {typeName: enums.TypeBlob},
+		"map[string]any":         {typeName: enums.TypeJson, jsonb: true},
+		"map[string]interface{}": {typeName: enums.TypeJson, jsonb: true},
+
+		"encoding/json.RawMessage": {typeName: enums.TypeJson, jsonb: true},
+		"time.Time":                {typeName: enums.TypeDatetimeImmutable},
+		"time.Duration":            {typeName: enums.TypeDateinterval},
+
+		"database/sql.NullBool":    {typeName: enums.TypeBoolean, nullable: true},
+		"database/sql.NullByte":    {typeName: enums.TypeSmallint, nullable: true},
+		"database/sql.NullInt16":   {typeName: enums.TypeSmallint, nullable: true},
+		"database/sql.NullInt32":   {typeName: enums.TypeInteger, nullable: true},
+		"database/sql.NullInt64":   {typeName: enums.TypeBigint, nullable: true},
+		"database/sql.NullFloat64": {typeName: enums.TypeFloat, nullable: true},
+		"database/sql.NullString":  {typeName: enums.TypeString, nullable: true},
+		"database/sql.NullTime":    {typeName: enums.TypeDatetimeImmutable, nullable: true},
+
+		"github.com/shopspring/decimal.Decimal":     {typeName: enums.TypeDecimal},
+		"github.com/shopspring/decimal.NullDecimal": {typeName: enums.TypeDecimal, nullable: true},
+
+		"github.com/google/uuid.UUID":     {typeName: enums.TypeGuid},
+		"github.com/google/uuid.NullUUID": {typeName: enums.TypeGuid, nullable: true},
+	}
+
+	// pgtype types are nullable, they have Valid field
+	pgTypes := map[string]enums.TypesType{
+		"Bool":        enums.TypeBoolean,
+		"Int2":        enums.TypeSmallint,
+		"Int4":        enums.TypeInteger,
+		"Int8":        enums.TypeBigint,
+		"Float4":      enums.TypeSmallfloat,
+		"Float8":      enums.TypeFloat,
+		"Text":        enums.TypeText,
+		"Numeric":     enums.TypeDecimal,
+		"Date":        enums.TypeDateImmutable,
+		"Time":        enums.TypeTimeImmutable,
+		"Timestamp":   enums.TypeDatetimeImmutable,
+		"Timestamptz": enums.TypeDatetimetzImmutable,
+		"Interval":    enums.TypeDateinterval,
+		"UUID":        enums.TypeGuid,
+	}
+
+	for _, pgTypePackage := range []string{"github.com/jackc/pgx/v5/pgtype", "github.com/jackc/pgtype"} {
+		for name, typeName := range pgTypes {
+			typesMap[pgTypePackage+"."+name] = &goColumnType{typeName: typeName, nullable: true}
+		}
+	}
+
+	return typesMap
+}
+
+// sqlNullRegexp - Matches generic sql.Null[T], column type is inferred from T.
+var sqlNullRegexp = regexp.MustCompile(`^database/sql\.Null\[(.+)]$`)
+
+// getGoColumnType - Returns column type of the go type name, see resolveTypeName.
+func getGoColumnType(typeName string) (*goColumnType, bool) {
+	if goType, ok := goColumnTypesMap[typeName]; ok {
+		return goType, true
+	}
+
+	if match := sqlNullRegexp.FindStringSubmatch(typeName); match != nil {
+		if goType, ok := goColumnTypesMap[match[1]]; ok {
+			nullableType := *goType
+			nullableType.nullable = true
+
+			return &nullableType, true
+		}
+	}
+
+	return nil, false
+}
+
+func isGoColumnType(typeName string) bool {
+	_, ok := getGoColumnType(typeName)
+	return ok
+}
+
+// resolveTypeName - Returns name of the field type qualified by the package path, e.g. time.Time or
+// database/sql.NullString. Named types which are not mapped are resolved to their underlying types, e.g. string for
+// `type Status string`. Entities and types without type information keep their own names.
+func (s *store) resolveTypeName(fieldType ast.Expr) string {
+	if ident, ok := fieldType.(*ast.Ident); ok {
+		if _, ok := s.objectsMap[ident.Name]; ok {
+			return ident.Name
+		}
+	}
+
+	if typ := s.typesInfo.TypeOf(fieldType); typ != nil {
+		if typeName := getGoTypeName(typ); typeName != "" {
+			return typeName
+		}
+	}
+
+	return s.getSyntaxTypeName(fieldType)
+}
+
+// getGoTypeName - Returns name of the type checked type, empty string for types which are not resolved.
+func getGoTypeName(typ gotypes.Type) string {
+	// Aliases are mapped by their own names first, e.g. json.RawMessage is an alias of jsontext.Value since go 1.25
+	if _, ok := typ.(*gotypes.Alias); ok {
+		if typeName := gotypes.TypeString(typ, nil); isGoColumnType(typeName) {
+			return typeName
+		}
+	}
+
+	typ = gotypes.Unalias(typ)
+
+	if basic, ok := typ.(*gotypes.Basic); ok && basic.Kind() == gotypes.Invalid {
+		return ""
+	}
+
+	typeName := gotypes.TypeString(typ, nil)
+	if isGoColumnType(typeName) {
+		return typeName
+	}
+
+	switch underlying := typ.Underlying().(type) {
+	case *gotypes.Basic:
+		return underlying.Name()
+	case *gotypes.Struct, *gotypes.Interface:
+		return typeName
+	}
+
+	return gotypes.TypeString(typ.Underlying(), nil)
+}
+
+// getSyntaxTypeName - Returns name of the type from the source, it is used when packages are not type checked,
+// e.g. when the package of the type is not available to the importer.
+func (s *store) getSyntaxTypeName(fieldType ast.Expr) string {
+	switch fType := fieldType.(type) {
+	case *ast.Ident:
+		return fType.Name
+	case *ast.SelectorExpr:
+		if packageIdent, ok := fType.X.(*ast.Ident); ok {
+			return s.getImportPath(packageIdent) + "." + fType.Sel.Name
+		}
+	case *ast.ArrayType:
+		if fType.Len == nil {
+			return "[]" + s.getSyntaxTypeName(fType.Elt)
+		}
+	case *ast.MapType:
+		return "map[" + s.getSyntaxTypeName(fType.Key) + "]" + s.getSyntaxTypeName(fType.Value)
+	case *ast.IndexExpr:
+		return s.getSyntaxTypeName(fType.X) + "[" + s.getSyntaxTypeName(fType.Index) + "]"
+	case *ast.InterfaceType:
+		if len(fType.Methods.List) == 0 {
+			return "any"
+		}
+	}
+
+	return gotypes.ExprString(fieldType)
+}
+
+var majorVersionRegexp = regexp.MustCompile(`^v[0-9]+$`)
+
+// getImportPath - Returns path of the package imported with the name in the file of the identifier.
+func (s *store) getImportPath(packageIdent *ast.Ident) string {
+	fileName := s.fileSet.Position(packageIdent.Pos()).Filename
+
+	for _, importSpec := range s.importsMap[fileName] {
+		importPath, err := strconv.Unquote(importSpec.Path.Value)
+		if err != nil {
+			continue
+		}
+
+		if importSpec.Name != nil {
+			if importSpec.Name.Name == packageIdent.Name {
+				return importPath
+			}
+			continue
+		}
+
+		// Package name is the last element of the path, major version suffix is skipped
+		packageName := path.Base(importPath)
+		if majorVersionRegexp.MatchString(packageName) {
+			packageName = path.Base(path.Dir(importPath))
+		}
+
+		if packageName == packageIdent.Name {
+			return importPath
+		}
+	}
+
+	return packageIdent.Name
+}
@@ -1,11 +1,12 @@
 package local_schema
 
 import (
-	"fmt"
 	"github.com/KoNekoD/gormite/pkg/assets"
 	"github.com/KoNekoD/gormite/pkg/types"
 	"github.com/fatih/structtag"
+	"github.com/pkg/errors"
 	"go/ast"
+	gotypes "go/types"
 	"golang.org/x/exp/maps"
 	"slices"
 	"strings"
@@ -29,34 +30,55 @@ func (s *store) newTable(name string) *assets.Table {
 
 type tableBag struct {
 	store       *store
+	objectName  string
 	table       *assets.Table
 	primaryKeys []string
 
 	uniqColumnsMap    map[string][]string
 	uniqConditionsMap map[string]string
 
-	indexColumnsMap    map[string][]string
-	indexConditionsMap map[string]string
+	indexColumnsMap     map[string][]string
+	indexConditionsMap  map[string]string
+	indexDefinitionsMap map[string]*indexDefinition
+	// indexPositionsMap - Positions of the columns in the indexes and unique indexes by their names
+	indexPositionsMap map[string]map[string]int
+
+	foreignKeyColumnsMap map[string][]string
+	foreignKeyOptionsMap map[string]map[string]any
 }
 
-func newTableBag(store *store, table *assets.Table) *tableBag {
+func newTableBag(store *store, objectName string, table *assets.Table) *tableBag {
 	bag := &tableBag{
-		store:              store,
-		table:              table,
-		primaryKeys:        make([]string, 0),
-		uniqColumnsMap:     make(map[string][]string),
-		uniqConditionsMap:  make(map[string]string),
-		indexColumnsMap:    make(map[string][]string),
-		indexConditionsMap: make(map[string]string),
+		store:               store,
+		objectName:          objectName,
+		table:               table,
+		primaryKeys:         make([]string, 0),
+		uniqColumnsMap:      make(map[string][]string),
+		uniqConditionsMap:   make(map[string]string),
+		indexColumnsMap:     make(map[string][]string),
+		indexConditionsMap:  make(map[string]string),
+		indexDefinitionsMap: make(map[string]*indexDefinition),
+		indexPositionsMap:   make(map[string]map[string]int),
+
+		foreignKeyColumnsMap: make(map[string][]string),
+		foreignKeyOptionsMap: make(map[string]map[string]any),
 	}
 
 	return bag
 }
 
-func (t *tableBag) colIdent(fieldType *ast.Ident, tags *structtag.Tags) {
+func (t *tableBag) colIdent(fieldType *ast.Ident, tags *structtag.Tags) error {
+	return t.colType(fieldType, tags)
+}
+
+// colType - Adds column of the type which is inferred from the field type or set by the type tag.
+func (t *tableBag) colType(fieldType ast.Expr, tags *structtag.Tags) error {
 	objectsKeys := maps.Keys(t.store.objectsMap)
 
-	columnTagsData := t.parseColumnTags(tags, fieldType, objectsKeys)
+	columnTagsData, err := t.parseColumnTags(tags, fieldType, objectsKeys)
+	if err != nil {
+		return err
+	}
 
 	if columnTagsData.ColumnType != nil {
 		t.table.AddColumn(
@@ -66,10 +88,14 @@ func (t *tableBag) colIdent(fieldType *ast.Ident, tags *structtag.Tags) {
 		)
 	} else {
 		if !columnTagsData.IsForeignKey {
-			panic(fmt.Sprintf("unknown type %s", columnTagsData.TypeName))
+			return errors.Errorf(
+				"unknown type %s of column %s, use type tag to set column type",
+				columnTagsData.TypeName,
+				columnTagsData.ColumnName,
+			)
 		}
 
-		// Maybe we need rewrite it to allow use non integer ids...
+		// Type of the referenced column is set when relations are resolved
 		t.table.AddColumn(
 			columnTagsData.ColumnName,
 			types.NewIntegerType(),
@@ -78,32 +104,28 @@ func (t *tableBag) colIdent(fieldType *ast.Ident, tags *structtag.Tags) {
 	}
 
 	applyMetadataMutatorsForNewColumn(columnTagsData, t)
+
+	return nil
 }
 
 func (t *tableBag) colSel(
 	fType *ast.SelectorExpr,
 	tags *structtag.Tags,
 	mustBeNullable bool,
-) {
+) error {
 	objectsKeys := maps.Keys(t.store.objectsMap)
 
 	selPackage := fType.X.(*ast.Ident).Name
 	selType := fType.Sel.Name
 
-	columnTagsData := t.parseColumnTags(tags, fType.Sel, objectsKeys)
-
-	if selPackage == "time" && selType == "Time" {
-		t.table.AddColumn(
-			columnTagsData.ColumnName,
-			types.NewDateTimeImmutableType(),
-			columnTagsData.Options...,
-		)
-		applyMetadataMutatorsForNewColumn(columnTagsData, t)
-		return
+	columnTagsData, err := t.parseColumnTags(tags, fType, objectsKeys)
+	if err != nil {
+		return err
 	}
 
-	if mustBeNullable && columnTagsData.IsNotNull {
-		panic("column " + columnTagsData.ColumnName + " of table " + t.table.GetName() + " cannot be not null")
+	// Pointers to inferred types, e.g. *time.Time, are allowed to be not null
+	if mustBeNullable && columnTagsData.IsNotNull && !columnTagsData.IsTypeInferred {
+		return errors.Errorf("column %s cannot be not null", columnTagsData.ColumnName)
 	}
 
 	if columnTagsData.ColumnType != nil {
@@ -113,98 +135,243 @@ func (t *tableBag) colSel(
 			columnTagsData.Options...,
 		)
 		applyMetadataMutatorsForNewColumn(columnTagsData, t)
-	} else {
-		if found, ok := t.store.structNamesIdentsMap[selType]; ok {
-			t.colIdent(found, tags)
-		} else {
-			panic("unknown type for " + columnTagsData.ColumnName)
-		}
+
+		return nil
+	}
+
+	if found, ok := t.store.structNamesIdentsMap[selType]; ok {
+		return t.colIdent(found, tags)
 	}
+
+	return errors.Errorf(
+		"unknown type %s.%s of column %s, use type tag to set column type",
+		selPackage,
+		selType,
+		columnTagsData.ColumnName,
+	)
 }
 
-func (t *tableBag) colStar(fType *ast.StarExpr, tags *structtag.Tags) {
+func (t *tableBag) colStar(fType *ast.StarExpr, tags *structtag.Tags) error {
 	switch fieldTypeX := fType.X.(type) {
 	case *ast.Ident:
-		t.colIdent(fieldTypeX, tags)
+		return t.colIdent(fieldTypeX, tags)
 	case *ast.SelectorExpr:
-		t.colSel(
+		return t.colSel(
 			fieldTypeX,
 			tags,
 			true,
 		) // TODO: must be nullable fool protection not work, idk why
-	default:
-		panic(fmt.Sprintf("Unknown star %T", fieldTypeX))
+	case *ast.ArrayType, *ast.MapType, *ast.IndexExpr:
+		return t.colType(fieldTypeX, tags)
 	}
+
+	return errors.Errorf("unknown star %T", fType.X)
 }
 
-func (t *tableBag) colArray(fieldType *ast.ArrayType, tags *structtag.Tags) {
+func (t *tableBag) colArray(fieldType *ast.ArrayType, tags *structtag.Tags) error {
 	objectsKeys := maps.Keys(t.store.objectsMap)
 
-	ident, ok := fieldType.Elt.(*ast.Ident)
-	if !ok {
-		panic("Only primitive array types are supported")
+	if isGoColumnType(t.store.resolveTypeName(fieldType)) {
+		return t.colType(fieldType, tags)
 	}
 
-	if ident.Name != "string" {
-		panic("Array type is not supported for type: " + ident.Name)
+	if fieldType.Len != nil {
+		return errors.New("fixed size array types are not supported")
 	}
 
-	columnTagsData := t.parseColumnTags(tags, ident, objectsKeys)
+	// Arrays of strings are not limited by length, so they are text[] unless the type tag is set
+	if typeTag, _ := tags.Get(typeTagName); typeTag == nil && t.store.resolveTypeName(fieldType.Elt) == "string" {
+		_ = tags.Set(&structtag.Tag{Key: typeTagName, Name: "text"})
+	}
+
+	columnTagsData, err := t.parseColumnTags(tags, fieldType.Elt, objectsKeys)
+	if err != nil {
+		return err
+	}
 
-	if "string" == columnTagsData.TypeName {
-		panic("please add type tag to string array for property: " + columnTagsData.ColumnName)
+	if columnTagsData.IsForeignKey {
+		return errors.Errorf(
+			"array of entities is not supported for column %s, use m2m tag instead",
+			columnTagsData.ColumnName,
+		)
 	}
 
+	if columnTagsData.ColumnType == nil {
+		return errors.Errorf(
+			"unknown element type %s of array column %s, use type tag to set element type",
+			columnTagsData.TypeName,
+			columnTagsData.ColumnName,
+		)
+	}
+
+	// json and jsonb tags store the whole slice as a json document instead of native array
+	columnType := columnTagsData.ColumnType
 	if !slices.Contains([]string{"json", "jsonb"}, columnTagsData.TypeName) {
-		panic("Only json/jsonb array types are supported")
+		columnType = types.NewArrayType(columnType)
 	}
 
 	t.table.AddColumn(
 		columnTagsData.ColumnName,
-		columnTagsData.ColumnType,
+		columnType,
 		columnTagsData.Options...,
 	)
+
+	applyMetadataMutatorsForNewColumn(columnTagsData, t)
+
+	return nil
 }
 
 // OneToOne - uniq_cd4f5a305067c3d4
 // OneToMany - virtual, not owner
 // ManyToOne - idx_79bd4a955067c3d4
-// ManyToMany - table1_table2_pkey, idx_f9cb7c79afc2b591, idx_f9cb7c79810212b - create separate table
+// ManyToMany - table1_table2_pkey, idx_f9cb7c79afc2b591, idx_f9cb7c79810212b - separate table, see colManyToMany
 
 func handleMappingObject(objectName string, store *store) (err error) {
 	t := store.newTable(objectName)
 
+	if renamedFrom, ok := store.renamedFromMap[objectName]; ok {
+		// Table is renamed within its schema
+		if namespace := t.GetNamespaceName(); namespace != "" && !strings.Contains(renamedFrom, ".") {
+			renamedFrom = namespace + "." + renamedFrom
+		}
+
+		t.SetRenamedFrom(renamedFrom)
+	}
+
+	if comment, ok := store.tableCommentsMap[objectName]; ok {
+		t.SetComment(&comment)
+	}
+
 	object := store.objectsMap[objectName]
 
 	typeSpec := object.Decl.(*ast.TypeSpec)
 	structType := typeSpec.Type.(*ast.StructType)
 
-	bag := newTableBag(store, t)
+	bag := newTableBag(store, objectName, t)
+
+	if err := bag.addFields(objectName, structType.Fields.List); err != nil {
+		return err
+	}
+
+	for _, check := range store.checksMap[objectName] {
+		if err := bag.addCheckConstraint(check, ""); err != nil {
+			return err
+		}
+	}
+
+	if err := bag.checkIndexDefinitions(); err != nil {
+		return err
+	}
 
-	for _, field := range structType.Fields.List {
-		tag := field.Tag.Value
-		tag = strings.Trim(field.Tag.Value, "`")
+	if err := bag.orderIndexColumns(); err != nil {
+		return err
+	}
+
+	applyMetadataMutatorsAfterColumnsIntrospection(bag)
+
+	return nil
+}
+
+// addFields - Adds columns of the struct fields, embedded structs are flattened into the table like in scanning.
+func (t *tableBag) addFields(ownerName string, fields []*ast.Field) error {
+	for _, field := range fields {
+		tag := ""
+		if field.Tag != nil {
+			tag = strings.Trim(field.Tag.Value, "`")
+		}
+
+		fieldName := ownerName + "." + gotypes.ExprString(field.Type)
+		if len(field.Names) > 0 {
+			fieldName = ownerName + "." + field.Names[0].Name
+		}
 
 		tags, err := structtag.Parse(tag)
 		if err != nil {
-			panic(err)
+			return errors.Wrapf(err, "invalid tags of field %s", fieldName)
+		}
+
+		columnName := ""
+		if colNameTag, _ := tags.Get(columnTagName); colNameTag != nil {
+			columnName = colNameTag.Value()
+		}
+
+		if columnName == "-" {
+			continue
+		}
+
+		if m2mTag, _ := tags.Get(manyToManyTagName); m2mTag != nil {
+			if err := t.colManyToMany(field.Type, tags); err != nil {
+				return errors.Wrapf(err, "failed to map field %s of table %s", fieldName, t.table.GetName())
+			}
+
+			continue
+		}
+
+		if columnName != "" && columnName != "*" && t.table.HasColumn(columnName) {
+			return errors.Errorf("duplicate column %s of field %s", columnName, fieldName)
+		}
+
+		if (len(field.Names) == 0 || columnName == "*") && t.store.isStructType(field.Type) {
+			structType, err := t.store.getStructType(field.Type)
+			if err != nil {
+				return errors.Wrapf(err, "failed to embed field %s", fieldName)
+			}
+
+			if err := t.addFields(fieldName, structType.Fields.List); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if columnName == "" {
+			if len(field.Names) == 0 {
+				return errors.Errorf("embedded type of field %s is not resolved, it must be a struct of importable package", fieldName)
+			}
+
+			return errors.Errorf("%s tag is required for field %s", columnTagName, fieldName)
 		}
 
 		switch fType := field.Type.(type) {
 		case *ast.Ident:
-			bag.colIdent(fType, tags)
+			err = t.colIdent(fType, tags)
 		case *ast.StarExpr:
-			bag.colStar(fType, tags)
+			err = t.colStar(fType, tags)
 		case *ast.SelectorExpr:
-			bag.colSel(fType, tags, false)
+			err = t.colSel(fType, tags, false)
 		case *ast.ArrayType:
-			bag.colArray(fType, tags)
+			err = t.colArray(fType, tags)
+		case *ast.MapType, *ast.IndexExpr:
+			err = t.colType(fType, tags)
 		default:
-			panic(fmt.Sprintf("Unknown fieldType %T", fType))
+			err = errors.Errorf("unknown fieldType %T", fType)
 		}
-	}
 
-	applyMetadataMutatorsAfterColumnsIntrospection(bag)
+		if err != nil {
+			return errors.Wrapf(err, "failed to map field %s of table %s", fieldName, t.table.GetName())
+		}
+
+		if comment := getColumnComment(field); comment != "" {
+			t.table.GetColumn(columnName).SetComment(comment)
+		}
+
+		if checkTag, _ := tags.Get(checkTagName); checkTag != nil {
+			checks, err := parseCheckTag(checkTag.Value())
+			if err != nil {
+				return errors.Wrapf(err, "invalid check of field %s", fieldName)
+			}
+
+			for _, check := range checks {
+				if err := t.addCheckConstraint(check, columnName); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := t.addIndexOptions(columnName, tags); err != nil {
+			return errors.Wrapf(err, "invalid index of field %s", fieldName)
+		}
+	}
 
 	return nil
 }
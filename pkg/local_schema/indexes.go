@@ -0,0 +1,242 @@
+package local_schema
+
+import (
+	"github.com/KoNekoD/gormite/pkg/assets"
+	"github.com/fatih/structtag"
+	"github.com/pkg/errors"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// indexDefinition - Options of the index which are set by the index_* tags of its columns.
+type indexDefinition struct {
+	using   string
+	include []string
+	// expressions - Expressions which replace the columns in the index, e.g. email is lower(email)
+	expressions map[string]string
+	orders      map[string]string
+	opclasses   map[string]string
+}
+
+func (t *tableBag) getIndexDefinition(indexName string) *indexDefinition {
+	if _, ok := t.indexDefinitionsMap[indexName]; !ok {
+		t.indexDefinitionsMap[indexName] = &indexDefinition{
+			include:     make([]string, 0),
+			expressions: make(map[string]string),
+			orders:      make(map[string]string),
+			opclasses:   make(map[string]string),
+		}
+	}
+
+	return t.indexDefinitionsMap[indexName]
+}
+
+// addIndexOptions - Adds options of the column indexes which are set by index_using, index_order, index_opclass,
+// index_expr and index_include tags, e.g. `index:"idx_name" index_order:"idx_name:desc nulls last"`.
+func (t *tableBag) addIndexOptions(columnName string, tags *structtag.Tags) error {
+	indexNames := make([]string, 0)
+	if indexTag, _ := tags.Get(indexTagName); indexTag != nil {
+		for _, indexName := range strings.Split(indexTag.Value(), ",") {
+			indexNames = append(indexNames, strings.TrimSpace(indexName))
+		}
+	}
+
+	for _, tagName := range []string{indexUsingTagName, indexOrderTagName, indexOpclassTagName, indexExpressionTagName} {
+		tag, _ := tags.Get(tagName)
+		if tag == nil {
+			continue
+		}
+
+		for _, option := range strings.Split(tag.Value(), ";") {
+			indexName, value, ok := strings.Cut(option, ":")
+			indexName, value = strings.TrimSpace(indexName), strings.TrimSpace(value)
+
+			if !ok || indexName == "" || value == "" {
+				return errors.Errorf("invalid %s %s, expected index_name:value", tagName, option)
+			}
+
+			if !slices.Contains(indexNames, indexName) {
+				return errors.Errorf("index %s of %s is not set by the index tag of column %s", indexName, tagName, columnName)
+			}
+
+			definition := t.getIndexDefinition(indexName)
+
+			switch tagName {
+			case indexUsingTagName:
+				using := strings.ToLower(value)
+				if definition.using != "" && definition.using != using {
+					return errors.Errorf("conflicting methods %s and %s of index %s", definition.using, using, indexName)
+				}
+
+				definition.using = using
+			case indexOrderTagName:
+				if _, ok := assets.NormalizeIndexOrder(value); !ok {
+					return errors.Errorf("invalid order %s of index %s, expected [asc|desc] [nulls first|last]", value, indexName)
+				}
+
+				definition.orders[columnName] = strings.ToUpper(value)
+			case indexOpclassTagName:
+				definition.opclasses[columnName] = value
+			case indexExpressionTagName:
+				definition.expressions[columnName] = value
+			}
+		}
+	}
+
+	if err := t.addIndexPositions(columnName, tags, indexNames); err != nil {
+		return err
+	}
+
+	if includeTag, _ := tags.Get(indexIncludeTagName); includeTag != nil {
+		for _, indexName := range strings.Split(includeTag.Value(), ",") {
+			indexName = strings.TrimSpace(indexName)
+
+			if slices.Contains(indexNames, indexName) {
+				return errors.Errorf("column %s is both key and included column of index %s", columnName, indexName)
+			}
+
+			definition := t.getIndexDefinition(indexName)
+			definition.include = append(definition.include, columnName)
+		}
+	}
+
+	return nil
+}
+
+// checkIndexDefinitions - Checks that indexes with included columns have key columns.
+func (t *tableBag) checkIndexDefinitions() error {
+	for _, indexName := range slices.Sorted(maps.Keys(t.indexDefinitionsMap)) {
+		if _, ok := t.indexColumnsMap[indexName]; !ok {
+			return errors.Errorf(
+				"index %s of table %s has no key columns, set the index tag",
+				indexName,
+				t.table.GetName(),
+			)
+		}
+	}
+
+	return nil
+}
+
+// addIndexPositions - Adds positions of the column in the indexes and unique indexes which are set by index_position
+// tag, e.g. `index:"idx_name" index_position:"idx_name:2"`.
+func (t *tableBag) addIndexPositions(columnName string, tags *structtag.Tags, indexNames []string) error {
+	positionTag, _ := tags.Get(indexPositionTagName)
+	if positionTag == nil {
+		return nil
+	}
+
+	names := slices.Clone(indexNames)
+	if uniqTag, _ := tags.Get(uniqueConstraintTagName); uniqTag != nil {
+		for _, uniqName := range strings.Split(uniqTag.Value(), ",") {
+			names = append(names, strings.TrimSpace(uniqName))
+		}
+	}
+
+	for _, option := range strings.Split(positionTag.Value(), ";") {
+		indexName, value, ok := strings.Cut(option, ":")
+		indexName, value = strings.TrimSpace(indexName), strings.TrimSpace(value)
+
+		position, err := strconv.Atoi(value)
+		if !ok || indexName == "" || err != nil || position < 1 {
+			return errors.Errorf("invalid %s %s, expected index_name:position", indexPositionTagName, option)
+		}
+
+		if !slices.Contains(names, indexName) {
+			return errors.Errorf(
+				"index %s of %s is not set by the index or uniq tag of column %s",
+				indexName,
+				indexPositionTagName,
+				columnName,
+			)
+		}
+
+		if _, ok := t.indexPositionsMap[indexName]; !ok {
+			t.indexPositionsMap[indexName] = make(map[string]int)
+		}
+
+		t.indexPositionsMap[indexName][columnName] = position
+	}
+
+	return nil
+}
+
+// orderIndexColumns - Sorts columns of the indexes by their positions, columns are in the order of the fields when
+// positions are not set. Positions of the index must be set for all its columns and be 1..n.
+func (t *tableBag) orderIndexColumns() error {
+	for _, columnsMap := range []map[string][]string{t.indexColumnsMap, t.uniqColumnsMap} {
+		for indexName, columns := range columnsMap {
+			positions, ok := t.indexPositionsMap[indexName]
+			if !ok {
+				continue
+			}
+
+			ordered := make([]string, len(columns))
+			for _, column := range columns {
+				position, ok := positions[column]
+				if !ok || position > len(columns) || ordered[position-1] != "" {
+					return errors.Errorf(
+						"positions of index %s of table %s must be set for all %d columns and be unique",
+						indexName,
+						t.table.GetName(),
+						len(columns),
+					)
+				}
+
+				ordered[position-1] = column
+			}
+
+			columnsMap[indexName] = ordered
+		}
+	}
+
+	return nil
+}
+
+// getIndexOptions - Returns columns of the index with expressions in place of the columns they replace and options
+// of the index, see assets.Index.
+func (t *tableBag) getIndexOptions(indexName string, columns []string) ([]string, map[string]any) {
+	options := make(map[string]any)
+
+	definition, ok := t.indexDefinitionsMap[indexName]
+	if !ok {
+		return columns, options
+	}
+
+	indexColumns := make([]string, 0, len(columns))
+	orders := make(map[string]string)
+	opclasses := make(map[string]string)
+
+	for _, column := range columns {
+		indexColumn := column
+		if expression, ok := definition.expressions[column]; ok {
+			indexColumn = expression
+		}
+
+		indexColumns = append(indexColumns, indexColumn)
+
+		if order, ok := definition.orders[column]; ok {
+			orders[indexColumn] = order
+		}
+		if opclass, ok := definition.opclasses[column]; ok {
+			opclasses[indexColumn] = opclass
+		}
+	}
+
+	if definition.using != "" {
+		options["using"] = definition.using
+	}
+	if len(definition.include) > 0 {
+		options["include"] = definition.include
+	}
+	if len(orders) > 0 {
+		options["orders"] = orders
+	}
+	if len(opclasses) > 0 {
+		options["opclasses"] = opclasses
+	}
+
+	return indexColumns, options
+}
@@ -13,8 +13,13 @@ type columnData struct {
 	IsForeignKey bool
 	OnDelete     *string
 	OnUpdate     *string
+	References   *string
+	// ForeignKey - "table[:columns]" of the foreign key which is composed by all fields with the same tag
+	ForeignKey *string
 
 	TypeName string
+	// IsTypeInferred - Column type is inferred from the go type, not set by the type tag
+	IsTypeInferred bool
 
 	IsUnique          bool
 	UniqueName        *string
@@ -28,6 +33,7 @@ type columnData struct {
 
 	Length       int
 	DefaultValue *string
+	RenamedFrom  *string
 
 	ColumnType types.AbstractTypeInterface
 
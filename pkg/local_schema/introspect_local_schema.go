@@ -4,11 +4,13 @@ import (
 	"github.com/KoNekoD/gormite/pkg/assets"
 	"github.com/KoNekoD/gormite/pkg/dtos"
 	"github.com/pkg/errors"
+	"maps"
+	"slices"
 )
 
 func IntrospectLocalSchema(path string) (*assets.Schema, error) {
 	s := newStore(path)
-	s.namespaces = append(s.namespaces, "public")
+	s.namespaces = append(s.namespaces, defaultNamespace)
 
 	var err error
 
@@ -17,6 +19,11 @@ func IntrospectLocalSchema(path string) (*assets.Schema, error) {
 		return nil, errors.WithStack(err)
 	}
 
+	s.naming, err = NewNamingStrategy(s.config.Gormite.Orm.Naming)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
 	if err = s.collectAst(); err != nil {
 		return nil, errors.WithStack(err)
 	}
@@ -25,12 +32,29 @@ func IntrospectLocalSchema(path string) (*assets.Schema, error) {
 		return nil, errors.WithStack(err)
 	}
 
-	s.introspectSequences()
+	if err = s.introspectRelations(); err != nil {
+		return nil, errors.WithStack(err)
+	}
 
-	return assets.NewSchema(
+	if err = s.introspectSequences(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	schema := assets.NewSchema(
 		s.tables,
 		s.sequences,
 		s.schemaConfig,
 		s.namespaces,
-	), nil
+	)
+
+	for _, name := range slices.Sorted(maps.Keys(s.enumTypes)) {
+		definition := s.enumTypes[name]
+		enumType := schema.CreateEnumType(name, definition.values)
+
+		for value, renamedFrom := range definition.renamedFrom {
+			enumType.SetValueRenamedFrom(value, renamedFrom)
+		}
+	}
+
+	return schema, nil
 }
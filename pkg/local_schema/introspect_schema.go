@@ -3,36 +3,75 @@ package local_schema
 import (
 	"github.com/KoNekoD/gormite/pkg/assets"
 	"github.com/KoNekoD/gormite/pkg/dtos"
+	"github.com/KoNekoD/gormite/pkg/enums"
 	"go/ast"
+	"go/token"
+	gotypes "go/types"
 )
 
 type store struct {
 	// Root
 	path         string
 	config       *dtos.ConfigData
+	naming       NamingStrategy
 	tables       []*assets.Table
 	sequences    []*assets.Sequence
+	enumTypes    map[string]*enumTypeDefinition
+	relations    []*relation
 	schemaConfig *dtos.SchemaConfig
 	namespaces   []string
+	fileSet      *token.FileSet
+	typesInfo    *gotypes.Info
 
 	// Mapping key level
 	objectsMap           map[string]*ast.Object
 	namesMap             map[string]string
+	renamedFromMap       map[string]string
+	idStrategiesMap      map[string]enums.IdStrategy
+	checksMap            map[string][]*checkDefinition
+	tableCommentsMap     map[string]string
+	tableDirectivesMap   map[string]*tableDirective
+	tableNameMethodsMap  map[string]string
+	namespacesMap        map[string]string
 	importsMap           map[string][]*ast.ImportSpec
 	structNamesIdentsMap map[string]*ast.Ident
+	embeddedNamesMap     map[string]bool
+	packagesTypeSpecsMap map[string]map[string]*ast.TypeSpec
+	constRenamedFromMap  map[token.Pos]string
 }
 
 func newStore(path string) *store {
+	fileSet := token.NewFileSet()
+
 	return &store{
-		path:                 path,
-		config:               nil,
-		tables:               make([]*assets.Table, 0),
-		sequences:            make([]*assets.Sequence, 0),
-		schemaConfig:         dtos.NewSchemaConfig(),
-		namespaces:           make([]string, 0),
+		path:         path,
+		config:       nil,
+		naming:       &DefaultNamingStrategy{},
+		tables:       make([]*assets.Table, 0),
+		sequences:    make([]*assets.Sequence, 0),
+		enumTypes:    make(map[string]*enumTypeDefinition),
+		relations:    make([]*relation, 0),
+		schemaConfig: dtos.NewSchemaConfig(),
+		namespaces:   make([]string, 0),
+		fileSet:      fileSet,
+		typesInfo: &gotypes.Info{
+			Types: make(map[ast.Expr]gotypes.TypeAndValue),
+			Defs:  make(map[*ast.Ident]gotypes.Object),
+			Uses:  make(map[*ast.Ident]gotypes.Object),
+		},
 		objectsMap:           make(map[string]*ast.Object),
 		namesMap:             make(map[string]string),
+		renamedFromMap:       make(map[string]string),
+		idStrategiesMap:      make(map[string]enums.IdStrategy),
+		checksMap:            make(map[string][]*checkDefinition),
+		tableCommentsMap:     make(map[string]string),
+		tableDirectivesMap:   make(map[string]*tableDirective),
+		tableNameMethodsMap:  make(map[string]string),
+		namespacesMap:        make(map[string]string),
 		importsMap:           make(map[string][]*ast.ImportSpec),
 		structNamesIdentsMap: make(map[string]*ast.Ident),
+		embeddedNamesMap:     make(map[string]bool),
+		packagesTypeSpecsMap: make(map[string]map[string]*ast.TypeSpec),
+		constRenamedFromMap:  make(map[token.Pos]string),
 	}
 }
@@ -3,6 +3,7 @@ package local_schema
 import (
 	"fmt"
 	"github.com/KoNekoD/gormite/pkg/assets"
+	"github.com/KoNekoD/gormite/pkg/enums"
 	"github.com/KoNekoD/gormite/pkg/types"
 	"github.com/pkg/errors"
 	"golang.org/x/exp/maps"
@@ -14,6 +15,10 @@ func (s *store) introspectTables() (err error) {
 	slices.Sort(keys)
 
 	for _, objectName := range keys {
+		if _, isTable := s.namesMap[objectName]; !isTable && s.embeddedNamesMap[objectName] {
+			continue // struct is only embedded into the tables
+		}
+
 		if err = handleMappingObject(objectName, s); err != nil {
 			return errors.WithStack(err)
 		}
@@ -22,28 +27,100 @@ func (s *store) introspectTables() (err error) {
 	return nil
 }
 
-func (s *store) introspectSequences() {
+// introspectSequences - Id is generated by the id strategy of the table for single integer primary key, which is not
+// a foreign key, e.g. primary key of the one-to-one table takes its value from the referenced table.
+func (s *store) introspectSequences() error {
+	defaultIdStrategy := s.config.Gormite.Orm.IdStrategy
+	if defaultIdStrategy == "" {
+		defaultIdStrategy = enums.IdStrategySequence
+	}
+
+	if !slices.Contains(enums.IdStrategies, defaultIdStrategy) {
+		return errors.Errorf("invalid id_strategy %s, allowed: sequence, serial, identity, none", defaultIdStrategy)
+	}
+
+	tableIdStrategies := make(map[string]enums.IdStrategy)
+	for objectName, idStrategy := range s.idStrategiesMap {
+		tableIdStrategies[getName(s, objectName)] = idStrategy
+	}
+
 	for _, table := range s.tables {
+		idStrategy, ok := tableIdStrategies[table.GetName()]
+		if !ok {
+			idStrategy = defaultIdStrategy
+		}
+
 		pk := table.GetPrimaryKey()
 
-		if len(pk.GetColumns()) != 1 {
+		if idStrategy == enums.IdStrategyNone || pk == nil || len(pk.GetColumns()) != 1 {
 			continue
 		}
 
-		typeValid := false
-		for _, s := range pk.GetColumns() {
-			column := table.GetColumn(pk.GetColumn(s).GetName())
-			if _, ok := column.GetColumnType().(*types.IntegerType); ok {
-				typeValid = true
+		columnName := pk.GetColumns()[0]
+		column := table.GetColumn(columnName)
+
+		switch column.GetColumnType().(type) {
+		case *types.IntegerType:
+		case *types.SmallIntType, *types.BigintType:
+			// Sequence is created only for integer id as before, other strategies are set explicitly
+			if idStrategy == enums.IdStrategySequence {
+				continue
 			}
+		default:
+			continue
+		}
+
+		isForeignKey := slices.ContainsFunc(
+			maps.Values(table.GetForeignKeys()),
+			func(fk *assets.ForeignKeyConstraint) bool { return slices.Contains(fk.GetLocalColumns(), columnName) },
+		)
+
+		if isForeignKey {
+			continue
 		}
 
-		if !typeValid {
+		if idStrategy == enums.IdStrategyIdentity {
+			if column.GetColumnDefault() != nil {
+				return errors.Errorf("identity column %s of table %s can't have default", columnName, table.GetName())
+			}
+
+			column.SetAutoincrement()
 			continue
 		}
 
-		seqName := fmt.Sprintf("%s__id__seq", table.GetName())
+		seqName := getIdSequenceName(s.naming, table, columnName)
 
 		s.sequences = append(s.sequences, assets.NewSequence(seqName))
+
+		// Sequence is set to the id column, so it is moved past the existing ids when the column switches to it
+		column.SetPlatformOption("sequence", seqName)
+
+		if idStrategy == enums.IdStrategySerial {
+			if column.GetColumnDefault() != nil {
+				return errors.Errorf("serial column %s of table %s can't have default", columnName, table.GetName())
+			}
+
+			column.SetColumnDefault(getSerialDefault(seqName))
+		}
+	}
+
+	return nil
+}
+
+// getIdSequenceName - Returns name of the sequence, which generates ids of the table, it lives in the schema
+// of the table.
+func getIdSequenceName(naming NamingStrategy, table *assets.Table, columnName string) string {
+	namespace := table.GetNamespaceName()
+	name := naming.SequenceName(table.GetShortestName(namespace), columnName)
+
+	if namespace == "" || namespace == defaultNamespace {
+		return name
 	}
+
+	return namespace + "." + name
+}
+
+// getSerialDefault - Returns default of the serial id, it is the same as the database returns.
+func getSerialDefault(seqName string) string {
+	return fmt.Sprintf("nextval('%s'::regclass)", seqName)
 }
@@ -0,0 +1,150 @@
+package local_schema
+
+import (
+	"github.com/KoNekoD/gormite/pkg/assets"
+	"github.com/KoNekoD/gormite/pkg/types"
+	"github.com/KoNekoD/gormite/pkg/utils"
+	"github.com/KoNekoD/ptrs/pkg/ptrs"
+	"github.com/fatih/structtag"
+	"github.com/pkg/errors"
+	"go/ast"
+	gotypes "go/types"
+	"slices"
+	"strings"
+)
+
+// colManyToMany - Creates join table of the slice of entities, e.g. `Tags []*Tag m2m:"post_tag"`.
+// Join table has composite primary key and foreign keys to primary keys of both tables, it is created once
+// when both sides declare it.
+func (t *tableBag) colManyToMany(fieldType ast.Expr, tags *structtag.Tags) error {
+	m2mTag, _ := tags.Get(manyToManyTagName)
+
+	targetName := ""
+	if arrayType, ok := fieldType.(*ast.ArrayType); ok {
+		elementType := arrayType.Elt
+		if starExpr, ok := elementType.(*ast.StarExpr); ok {
+			elementType = starExpr.X
+		}
+
+		switch fType := elementType.(type) {
+		case *ast.Ident:
+			targetName = fType.Name
+		case *ast.SelectorExpr:
+			targetName = fType.Sel.Name
+		}
+	}
+
+	if _, ok := t.store.objectsMap[targetName]; !ok {
+		return errors.Errorf("%s tag requires slice of entities, got %s", manyToManyTagName, gotypes.ExprString(fieldType))
+	}
+
+	joinTableName := m2mTag.Value()
+	if namespace := t.table.GetNamespaceName(); namespace != "" && !strings.Contains(joinTableName, ".") {
+		joinTableName = namespace + "." + joinTableName
+	}
+
+	columns := []string{utils.ToSnakeCase(t.objectName) + "_id", utils.ToSnakeCase(targetName) + "_id"}
+	if columnsTag, _ := tags.Get(manyToManyColumnsTagName); columnsTag != nil {
+		columns = strings.Split(columnsTag.Value(), ",")
+		if len(columns) != 2 {
+			return errors.Errorf("%s tag requires 2 columns, got %s", manyToManyColumnsTagName, columnsTag.Value())
+		}
+
+		for i := range columns {
+			columns[i] = strings.TrimSpace(columns[i])
+		}
+	}
+
+	if columns[0] == columns[1] {
+		return errors.Errorf("columns of join table %s are the same, set them with %s tag", joinTableName, manyToManyColumnsTagName)
+	}
+
+	options := map[string]any{"onDelete": ptrs.AsPtr("CASCADE")}
+	if onDeleteTag, _ := tags.Get(onDeleteTagName); onDeleteTag != nil {
+		options["onDelete"] = ptrs.AsPtr(onDeleteTag.Value())
+	}
+	if onUpdateTag, _ := tags.Get(onUpdateTagName); onUpdateTag != nil {
+		options["onUpdate"] = ptrs.AsPtr(onUpdateTag.Value())
+	}
+
+	for _, table := range t.store.tables {
+		if table.GetName() == joinTableName {
+			// Join table is declared by the other side, both sides must declare the same one
+			return t.checkManyToManyJoinTable(table, columns, []string{t.objectName, targetName}, options)
+		}
+	}
+
+	joinTable := assets.NewTable(joinTableName, nil, nil, nil, nil, nil)
+
+	for i, objectName := range []string{t.objectName, targetName} {
+		// Type of the referenced primary key is set when relations are resolved
+		joinTable.AddColumn(columns[i], types.NewIntegerType(), assets.WithColumnNotNull())
+
+		t.store.relations = append(
+			t.store.relations,
+			&relation{
+				table:         joinTable,
+				columns:       []string{columns[i]},
+				tableName:     getName(t.store, objectName),
+				references:    make([]string, 0),
+				options:       options,
+				isTypeDerived: true,
+			},
+		)
+	}
+
+	joinTable.SetPrimaryKey(columns, ptrs.AsPtr(t.store.naming.PrimaryKeyName(joinTable.GetShortestName(joinTable.GetNamespaceName()))))
+
+	t.store.tables = append(t.store.tables, joinTable)
+
+	return nil
+}
+
+// checkManyToManyJoinTable - Checks that the join table declared by the other side has the same columns referencing
+// the same tables with the same on_delete and on_update options.
+func (t *tableBag) checkManyToManyJoinTable(
+	joinTable *assets.Table,
+	columns []string,
+	objectNames []string,
+	options map[string]any,
+) error {
+	for _, r := range t.store.relations {
+		if r.table != joinTable {
+			continue
+		}
+
+		i := slices.Index(columns, r.columns[0])
+		if i == -1 || r.tableName != getName(t.store, objectNames[i]) {
+			return errors.Errorf(
+				"join table %s of %s has columns %v, which differ from the other side",
+				joinTable.GetName(),
+				t.objectName,
+				columns,
+			)
+		}
+
+		for _, option := range []string{"onDelete", "onUpdate"} {
+			if value, otherValue := getRelationOption(options, option), getRelationOption(r.options, option); value != otherValue {
+				return errors.Errorf(
+					"join table %s of %s has %s %s, the other side has %s",
+					joinTable.GetName(),
+					t.objectName,
+					option,
+					value,
+					otherValue,
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+// getRelationOption - Returns the option of the relation, absent option is empty.
+func getRelationOption(options map[string]any, name string) string {
+	if value, ok := options[name].(*string); ok && value != nil {
+		return *value
+	}
+
+	return ""
+}
@@ -3,6 +3,8 @@ package local_schema
 import (
 	"fmt"
 	"github.com/KoNekoD/ptrs/pkg/ptrs"
+	"golang.org/x/exp/maps"
+	"slices"
 	"strings"
 )
 
@@ -10,6 +12,22 @@ func applyMetadataMutatorsForNewColumn(
 	columnTagsData *columnData,
 	bag *tableBag,
 ) {
+	if columnTagsData.ForeignKey != nil {
+		foreignKey := *columnTagsData.ForeignKey
+
+		bag.foreignKeyColumnsMap[foreignKey] = append(bag.foreignKeyColumnsMap[foreignKey], columnTagsData.ColumnName)
+
+		if _, ok := bag.foreignKeyOptionsMap[foreignKey]; !ok {
+			bag.foreignKeyOptionsMap[foreignKey] = map[string]any{}
+		}
+		if columnTagsData.OnUpdate != nil {
+			bag.foreignKeyOptionsMap[foreignKey]["onUpdate"] = columnTagsData.OnUpdate
+		}
+		if columnTagsData.OnDelete != nil {
+			bag.foreignKeyOptionsMap[foreignKey]["onDelete"] = columnTagsData.OnDelete
+		}
+	}
+
 	if columnTagsData.IsForeignKey {
 		options := map[string]any{}
 		if columnTagsData.OnUpdate != nil {
@@ -19,15 +37,28 @@ func applyMetadataMutatorsForNewColumn(
 			options["onDelete"] = columnTagsData.OnDelete
 		}
 
-		bag.table.AddForeignKeyConstraint(
-			getName(bag.store, columnTagsData.TypeName),
-			[]string{columnTagsData.ColumnName},
-			[]string{"id"},
-			options,
-			nil,
+		references := make([]string, 0)
+		if columnTagsData.References != nil {
+			references = append(references, *columnTagsData.References)
+		}
+
+		bag.store.relations = append(
+			bag.store.relations,
+			&relation{
+				table:         bag.table,
+				columns:       []string{columnTagsData.ColumnName},
+				tableName:     getName(bag.store, columnTagsData.TypeName),
+				references:    references,
+				options:       options,
+				isTypeDerived: columnTagsData.ColumnType == nil,
+			},
 		)
 	}
 
+	if columnTagsData.RenamedFrom != nil {
+		bag.table.SetColumnRenamedFrom(columnTagsData.ColumnName, *columnTagsData.RenamedFrom)
+	}
+
 	if columnTagsData.IsPrimaryKey {
 		bag.primaryKeys = append(bag.primaryKeys, columnTagsData.ColumnName)
 	}
@@ -49,7 +80,7 @@ func applyMetadataMutatorsForNewColumn(
 	if columnTagsData.IsUniqueCondition {
 		conditions := strings.Split(*columnTagsData.UniqueCondition, ";")
 		for _, condition := range conditions {
-			conditionParts := strings.Split(condition, ":")
+			conditionParts := strings.SplitN(condition, ":", 2)
 			if len(conditionParts) != 2 {
 				panic(fmt.Sprintf("invalid uniq condition %s", condition))
 			}
@@ -75,7 +106,7 @@ func applyMetadataMutatorsForNewColumn(
 	if columnTagsData.IsIndexCondition {
 		conditions := strings.Split(*columnTagsData.IndexCondition, ";")
 		for _, condition := range conditions {
-			conditionParts := strings.Split(condition, ":")
+			conditionParts := strings.SplitN(condition, ":", 2)
 			if len(conditionParts) != 2 {
 				panic(fmt.Sprintf("invalid index condition %s", condition))
 			}
@@ -86,22 +117,21 @@ func applyMetadataMutatorsForNewColumn(
 }
 
 func applyMetadataMutatorsAfterColumnsIntrospection(bag *tableBag) {
+	// Indexes live in the schema of the table, so their names are not qualified
+	tableName := bag.table.GetShortestName(bag.table.GetNamespaceName())
+
 	for indexName, columns := range bag.indexColumnsMap {
-		options := make(map[string]any)
+		columns, options := bag.getIndexOptions(indexName, columns)
 
 		if v, ok := bag.indexConditionsMap[indexName]; ok {
 			options["where"] = v
 		}
 
-		bag.table.AddIndex(columns, &indexName, make([]string, 0), options)
+		bag.table.AddIndex(columns, ptrs.AsPtr(bag.store.naming.IndexName(tableName, indexName)), make([]string, 0), options)
 	}
 
 	for uniqPseudoName, columns := range bag.uniqColumnsMap {
-		uniqIdxName := fmt.Sprintf(
-			"idx__%s__%s__uniq",
-			bag.table.GetName(),
-			strings.Join(columns, "_"),
-		)
+		uniqIdxName := bag.store.naming.UniqueIndexName(tableName, columns)
 
 		options := make(map[string]any)
 
@@ -112,8 +142,34 @@ func applyMetadataMutatorsAfterColumnsIntrospection(bag *tableBag) {
 		bag.table.AddUniqueIndex(columns, &uniqIdxName, options)
 	}
 
+	foreignKeys := maps.Keys(bag.foreignKeyColumnsMap)
+	slices.Sort(foreignKeys)
+
+	for _, foreignKey := range foreignKeys {
+		// "table:column1,column2" - columns are the primary key of the table when omitted
+		foreignTableName, foreignColumns, _ := strings.Cut(foreignKey, ":")
+
+		references := make([]string, 0)
+		if foreignColumns != "" {
+			for _, column := range strings.Split(foreignColumns, ",") {
+				references = append(references, strings.TrimSpace(column))
+			}
+		}
+
+		bag.store.relations = append(
+			bag.store.relations,
+			&relation{
+				table:      bag.table,
+				columns:    bag.foreignKeyColumnsMap[foreignKey],
+				tableName:  strings.TrimSpace(foreignTableName),
+				references: references,
+				options:    bag.foreignKeyOptionsMap[foreignKey],
+			},
+		)
+	}
+
 	bag.table.SetPrimaryKey(
 		bag.primaryKeys,
-		ptrs.AsPtr(fmt.Sprintf("%s_pkey", bag.table.GetName())),
+		ptrs.AsPtr(bag.store.naming.PrimaryKeyName(tableName)),
 	)
 }
@@ -0,0 +1,141 @@
+package local_schema
+
+import (
+	"fmt"
+	"github.com/KoNekoD/gormite/pkg/dtos"
+	"github.com/KoNekoD/gormite/pkg/utils"
+	"github.com/pkg/errors"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// NamingStrategy - Names of the tables which are not set by the struct comment and generated names of the primary
+// keys, unique indexes, foreign keys and id sequences. Table names are passed without schema.
+type NamingStrategy interface {
+	// TableName - Returns table name of the struct which has no "// StructName table_name" comment
+	TableName(structName string) string
+	PrimaryKeyName(tableName string) string
+	UniqueIndexName(tableName string, columns []string) string
+	// IndexName - Returns name of the index which is set by the index tag
+	IndexName(tableName string, indexName string) string
+	// ForeignKeyName - Returns name of the foreign key, empty name is generated from hash, e.g. FK_D95AB405A76ED395
+	ForeignKeyName(tableName string, columns []string) string
+	// ForeignKeyIndexName - Returns name of the index of the foreign key columns, which is added when they are not
+	// indexed yet, empty name is generated from hash, e.g. IDX_3B65BB0CA76ED395
+	ForeignKeyIndexName(tableName string, columns []string) string
+	SequenceName(tableName string, columnName string) string
+}
+
+const (
+	// DefaultNamingStrategyName - users_pkey, idx__users__email__uniq, FK_D95AB405A76ED395 and users__id__seq
+	DefaultNamingStrategyName = "default"
+
+	// PrefixedNamingStrategyName - pk_users, uq_users_email, ix_<index>, fk_users_role_id, ix_users_role_id
+	// and users_id_seq
+	PrefixedNamingStrategyName = "prefixed"
+)
+
+var namingStrategies = map[string]func(config dtos.ConfigDataNaming) NamingStrategy{
+	DefaultNamingStrategyName: func(config dtos.ConfigDataNaming) NamingStrategy {
+		return &DefaultNamingStrategy{Plural: config.Plural, TablePrefix: config.TablePrefix}
+	},
+	PrefixedNamingStrategyName: func(config dtos.ConfigDataNaming) NamingStrategy {
+		return &PrefixedNamingStrategy{DefaultNamingStrategy{Plural: config.Plural, TablePrefix: config.TablePrefix}}
+	},
+}
+
+// RegisterNamingStrategy - Registers the strategy which is selected by naming.strategy of gormite.yaml.
+func RegisterNamingStrategy(name string, factory func(config dtos.ConfigDataNaming) NamingStrategy) {
+	namingStrategies[name] = factory
+}
+
+// NewNamingStrategy - Returns the strategy selected by the config, default one is used when it is not set.
+func NewNamingStrategy(config dtos.ConfigDataNaming) (NamingStrategy, error) {
+	name := config.Strategy
+	if name == "" {
+		name = DefaultNamingStrategyName
+	}
+
+	factory, ok := namingStrategies[name]
+	if !ok {
+		allowed := strings.Join(slices.Sorted(maps.Keys(namingStrategies)), ", ")
+
+		return nil, errors.Errorf("unknown naming strategy %s, allowed: %s", name, allowed)
+	}
+
+	return factory(config), nil
+}
+
+// DefaultNamingStrategy - Names which gormite always used, table name is snake_case of the struct name.
+type DefaultNamingStrategy struct {
+	Plural      bool
+	TablePrefix string
+}
+
+func (s *DefaultNamingStrategy) TableName(structName string) string {
+	name := utils.ToSnakeCase(structName)
+	if s.Plural {
+		name = utils.Pluralize(name)
+	}
+
+	return s.TablePrefix + name
+}
+
+func (s *DefaultNamingStrategy) PrimaryKeyName(tableName string) string {
+	return fmt.Sprintf("%s_pkey", tableName)
+}
+
+func (s *DefaultNamingStrategy) UniqueIndexName(tableName string, columns []string) string {
+	return fmt.Sprintf("idx__%s__%s__uniq", tableName, strings.Join(columns, "_"))
+}
+
+func (s *DefaultNamingStrategy) IndexName(tableName string, indexName string) string {
+	return indexName
+}
+
+func (s *DefaultNamingStrategy) ForeignKeyName(tableName string, columns []string) string {
+	return ""
+}
+
+func (s *DefaultNamingStrategy) ForeignKeyIndexName(tableName string, columns []string) string {
+	return ""
+}
+
+// SequenceName - Id sequence is named by the table only, whatever the name of the id column is.
+func (s *DefaultNamingStrategy) SequenceName(tableName string, columnName string) string {
+	return fmt.Sprintf("%s__id__seq", tableName)
+}
+
+// PrefixedNamingStrategy - Names are prefixed with the kind of the object, sequences are named like serial ones.
+type PrefixedNamingStrategy struct {
+	DefaultNamingStrategy
+}
+
+func (s *PrefixedNamingStrategy) PrimaryKeyName(tableName string) string {
+	return fmt.Sprintf("pk_%s", tableName)
+}
+
+func (s *PrefixedNamingStrategy) UniqueIndexName(tableName string, columns []string) string {
+	return fmt.Sprintf("uq_%s_%s", tableName, strings.Join(columns, "_"))
+}
+
+func (s *PrefixedNamingStrategy) IndexName(tableName string, indexName string) string {
+	if strings.HasPrefix(indexName, "ix_") {
+		return indexName
+	}
+
+	return "ix_" + indexName
+}
+
+func (s *PrefixedNamingStrategy) ForeignKeyName(tableName string, columns []string) string {
+	return fmt.Sprintf("fk_%s_%s", tableName, strings.Join(columns, "_"))
+}
+
+func (s *PrefixedNamingStrategy) ForeignKeyIndexName(tableName string, columns []string) string {
+	return fmt.Sprintf("ix_%s_%s", tableName, strings.Join(columns, "_"))
+}
+
+func (s *PrefixedNamingStrategy) SequenceName(tableName string, columnName string) string {
+	return fmt.Sprintf("%s_%s_seq", tableName, columnName)
+}
@@ -0,0 +1,119 @@
+package local_schema
+
+import (
+	"github.com/KoNekoD/gormite/pkg/assets"
+	"github.com/pkg/errors"
+)
+
+// relation - Foreign key which is added after all tables are introspected, so the referenced table is known.
+type relation struct {
+	table     *assets.Table
+	columns   []string
+	tableName string
+	// references - Referenced columns, primary key of the referenced table when empty
+	references []string
+	options    map[string]any
+	// isTypeDerived - Type of the columns is copied from the referenced columns, otherwise it is set by the fields
+	isTypeDerived bool
+
+	// foreignTable and foreignColumns - Referenced table and columns, they are set by resolveRelation
+	foreignTable   *assets.Table
+	foreignColumns []string
+}
+
+func (s *store) introspectRelations() error {
+	for _, r := range s.relations {
+		if err := s.resolveRelation(r); err != nil {
+			return err
+		}
+	}
+
+	// Referenced column may be type derived too, e.g. one-to-one chain of primary keys, so types are copied once per
+	// relation, then the longest chain is resolved whatever the order of the relations is
+	for range s.relations {
+		for _, r := range s.relations {
+			if !r.isTypeDerived {
+				continue
+			}
+
+			for i, foreignColumnName := range r.foreignColumns {
+				copyColumnType(r.foreignTable.GetColumn(foreignColumnName), r.table.GetColumn(r.columns[i]))
+			}
+		}
+	}
+
+	for _, r := range s.relations {
+		// Foreign keys and indexes live in the schema of the table, so their names are not qualified
+		tableName := r.table.GetShortestName(r.table.GetNamespaceName())
+
+		var name *string
+		if value := s.naming.ForeignKeyName(tableName, r.columns); value != "" {
+			name = &value
+		}
+
+		// Named index is added before the foreign key, so the implicit one is not generated
+		if indexName := s.naming.ForeignKeyIndexName(tableName, r.columns); indexName != "" && !r.table.ColumnsAreIndexed(r.columns) {
+			r.table.AddIndex(r.columns, &indexName, make([]string, 0), make(map[string]any))
+		}
+
+		r.table.AddForeignKeyConstraint(r.tableName, r.columns, r.foreignColumns, r.options, name)
+	}
+
+	return nil
+}
+
+// resolveRelation - Sets the referenced table and columns of the relation and checks that they exist.
+func (s *store) resolveRelation(r *relation) error {
+	for _, table := range s.tables {
+		if table.GetName() == r.tableName {
+			r.foreignTable = table
+		}
+	}
+
+	if r.foreignTable == nil {
+		return errors.Errorf("table %s referenced by %s not found", r.tableName, r.table.GetName())
+	}
+
+	r.foreignColumns = r.references
+	if len(r.foreignColumns) == 0 {
+		if pk := r.foreignTable.GetPrimaryKey(); pk != nil {
+			r.foreignColumns = pk.GetColumns()
+		}
+	}
+
+	if len(r.foreignColumns) != len(r.columns) {
+		return errors.Errorf(
+			"columns %v of table %s can't reference columns %v of table %s",
+			r.columns,
+			r.table.GetName(),
+			r.foreignColumns,
+			r.tableName,
+		)
+	}
+
+	for _, foreignColumnName := range r.foreignColumns {
+		if !r.foreignTable.HasColumn(foreignColumnName) {
+			return errors.Errorf("column %s referenced by %s not found in %s", foreignColumnName, r.table.GetName(), r.tableName)
+		}
+	}
+
+	return nil
+}
+
+// copyColumnType - Sets type of the referenced column to the referencing one, e.g. uuid or varchar(32) natural keys.
+func copyColumnType(from *assets.Column, to *assets.Column) {
+	to.SetType(from.GetColumnType())
+
+	if from.GetLength() != nil {
+		to.SetLength(*from.GetLength())
+	}
+	if from.GetFixed() {
+		to.SetFixed()
+	}
+	if from.GetPrecision() != nil {
+		to.SetPrecision(*from.GetPrecision())
+	}
+	if from.GetScale() != nil {
+		to.SetScale(*from.GetScale())
+	}
+}
@@ -0,0 +1,193 @@
+package local_schema
+
+import (
+	"github.com/pkg/errors"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// tableDirectivePrefix - Prefix of the "// gormite:table=users schema=billing comment=\"Users\"" directive line
+const tableDirectivePrefix = "gormite:"
+
+// tableDirective - Table metadata which is set by the gormite directive of the struct comment.
+type tableDirective struct {
+	table   string
+	schema  string
+	comment string
+}
+
+// cutCommentDirective - Returns the comment without gormite directive lines and the directive parsed from them.
+// Directive is read from the raw comment lines, since "//gormite:" lines are dropped by ast.CommentGroup.Text.
+func cutCommentDirective(comment *ast.CommentGroup, commentStr string) (string, *tableDirective, error) {
+	var directive *tableDirective
+
+	for _, line := range comment.List {
+		value, ok := strings.CutPrefix(strings.TrimSpace(strings.TrimPrefix(line.Text, "//")), tableDirectivePrefix)
+		if !ok {
+			continue
+		}
+
+		if directive != nil {
+			return "", nil, errors.New("only one gormite directive is allowed in the comment")
+		}
+
+		var err error
+		if directive, err = parseTableDirective(value); err != nil {
+			return "", nil, errors.Wrapf(err, "invalid directive %s", line.Text)
+		}
+	}
+
+	if directive == nil {
+		return commentStr, nil, nil
+	}
+
+	lines := make([]string, 0)
+	for _, line := range strings.Split(commentStr, "\n") {
+		if !isTableDirectiveLine(line) {
+			lines = append(lines, line)
+		}
+	}
+
+	return strings.Join(lines, "\n"), directive, nil
+}
+
+func isTableDirectiveLine(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), tableDirectivePrefix)
+}
+
+// parseTableDirective - Parses key=value pairs of the directive, values with spaces are double-quoted.
+func parseTableDirective(value string) (*tableDirective, error) {
+	directive := &tableDirective{}
+
+	for value = strings.TrimSpace(value); value != ""; value = strings.TrimSpace(value) {
+		key, rest, ok := strings.Cut(value, "=")
+		if !ok || key == "" || strings.ContainsFunc(key, unicode.IsSpace) {
+			return nil, errors.Errorf("expected key=value, got %s", value)
+		}
+
+		var pairValue string
+		if strings.HasPrefix(rest, `"`) {
+			quoted, err := strconv.QuotedPrefix(rest)
+			if err != nil {
+				return nil, errors.Errorf("unterminated value of %s", key)
+			}
+
+			pairValue, _ = strconv.Unquote(quoted)
+			value = rest[len(quoted):]
+		} else {
+			pairValue, value, _ = strings.Cut(rest, " ")
+		}
+
+		switch key {
+		case "table":
+			directive.table = pairValue
+		case "schema":
+			directive.schema = pairValue
+		case "comment":
+			directive.comment = pairValue
+		default:
+			return nil, errors.Errorf("unknown key %s, allowed: table, schema, comment", key)
+		}
+	}
+
+	return directive, nil
+}
+
+// collectTableNameMethods - Collects table names returned by "TableName() string" methods of the structs, the method
+// must return a constant, since it is not executed.
+func (s *store) collectTableNameMethods(files []*ast.File) error {
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Recv == nil || funcDecl.Name.Name != "TableName" || funcDecl.Body == nil {
+				continue
+			}
+
+			receiverType := funcDecl.Recv.List[0].Type
+			if starExpr, ok := receiverType.(*ast.StarExpr); ok {
+				receiverType = starExpr.X
+			}
+
+			ident, ok := receiverType.(*ast.Ident)
+			if !ok {
+				continue
+			}
+
+			if _, ok := s.objectsMap[ident.Name]; !ok {
+				continue // method of non struct type
+			}
+
+			value, ok := s.getTableNameMethodValue(funcDecl)
+			if !ok {
+				return errors.Errorf("TableName method of %s must return a constant string", ident.Name)
+			}
+
+			s.tableNameMethodsMap[ident.Name] = value
+		}
+	}
+
+	return nil
+}
+
+// getTableNameMethodValue - Returns the constant string which is returned by the only statement of the method.
+func (s *store) getTableNameMethodValue(funcDecl *ast.FuncDecl) (string, bool) {
+	if len(funcDecl.Body.List) != 1 {
+		return "", false
+	}
+
+	returnStmt, ok := funcDecl.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(returnStmt.Results) != 1 {
+		return "", false
+	}
+
+	result := returnStmt.Results[0]
+
+	if typeAndValue, ok := s.typesInfo.Types[result]; ok && typeAndValue.Value != nil {
+		if typeAndValue.Value.Kind() != constant.String {
+			return "", false
+		}
+
+		return constant.StringVal(typeAndValue.Value), true
+	}
+
+	// Literal is used when types of the package are not resolved
+	if basicLit, ok := result.(*ast.BasicLit); ok && basicLit.Kind == token.STRING {
+		value, err := strconv.Unquote(basicLit.Value)
+
+		return value, err == nil
+	}
+
+	return "", false
+}
+
+// applyTableMetadata - Sets table names, schemas and comments of TableName methods and gormite directives, they win
+// over the "// StructName table_name" comments.
+func (s *store) applyTableMetadata() error {
+	for structName, tableName := range s.tableNameMethodsMap {
+		s.namesMap[structName] = tableName
+	}
+
+	for structName, directive := range s.tableDirectivesMap {
+		if directive.table != "" {
+			if _, ok := s.tableNameMethodsMap[structName]; ok {
+				return errors.Errorf("table name of %s is set by both TableName method and gormite directive", structName)
+			}
+
+			s.namesMap[structName] = directive.table
+		}
+
+		if directive.schema != "" {
+			s.namespacesMap[structName] = directive.schema
+		}
+
+		if directive.comment != "" {
+			s.tableCommentsMap[structName] = directive.comment
+		}
+	}
+
+	return nil
+}
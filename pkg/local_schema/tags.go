@@ -1,11 +1,12 @@
 package local_schema
 
 import (
-	"fmt"
 	"github.com/KoNekoD/gormite/pkg/assets"
+	"github.com/KoNekoD/gormite/pkg/enums"
 	"github.com/KoNekoD/gormite/pkg/types"
 	"github.com/KoNekoD/ptrs/pkg/ptrs"
 	"github.com/fatih/structtag"
+	"github.com/pkg/errors"
 	"go/ast"
 	"slices"
 	"strconv"
@@ -22,18 +23,33 @@ const (
 	uniqueConstraintConditionTagName = "uniq_cond"
 	indexTagName                     = "index"
 	indexConditionTagName            = "index_cond"
+	indexUsingTagName                = "index_using"
+	indexOrderTagName                = "index_order"
+	indexOpclassTagName              = "index_opclass"
+	indexExpressionTagName           = "index_expr"
+	indexIncludeTagName              = "index_include"
+	indexPositionTagName             = "index_position"
 	defaultValueTagName              = "default"
 	typeTagName                      = "type"
 	precisionTagName                 = "precision"
 	scaleTagName                     = "scale"
+	renamedFromTagName               = "renamed_from"
+	referencesTagName                = "references"
+	foreignKeyTagName                = "fk"
+	manyToManyTagName                = "m2m"
+	manyToManyColumnsTagName         = "m2m_columns"
+	checkTagName                     = "check"
+	enumTagName                      = "enum"
+	generatedTagName                 = "generated"
+	idStrategyTagName                = "id_strategy"
 )
 
 func (t *tableBag) parseColumnTags(
 	tags *structtag.Tags,
-	fieldType *ast.Ident,
+	fieldType ast.Expr,
 	objectsKeys []string,
-) *columnData {
-	typeName := fieldType.Name
+) (*columnData, error) {
+	typeName := t.store.resolveTypeName(fieldType)
 
 	colNameTag, _ := tags.Get(columnTagName)
 	columnName := colNameTag.Value()
@@ -50,12 +66,21 @@ func (t *tableBag) parseColumnTags(
 		onDelete = ptrs.AsPtr(onDeleteTag.Value())
 	}
 
+	var references *string
+	if referencesTag, _ := tags.Get(referencesTagName); referencesTag != nil {
+		references = ptrs.AsPtr(referencesTag.Value())
+	}
+
+	var foreignKey *string
+	if foreignKeyTag, _ := tags.Get(foreignKeyTagName); foreignKeyTag != nil {
+		foreignKey = ptrs.AsPtr(foreignKeyTag.Value())
+	}
+
 	pk, _ := tags.Get(primaryKeyTagName)
 	isPrimaryKey := pk != nil
 
 	nullableTag, _ := tags.Get(isNullableTagName)
 	isNullable := nullableTag != nil
-	isNotNull := !isNullable
 
 	lengthTag, _ := tags.Get(lengthTagName)
 	length := 255
@@ -96,6 +121,24 @@ func (t *tableBag) parseColumnTags(
 		defaultValue = ptrs.AsPtr(defaultTag.Value())
 	}
 
+	var renamedFrom *string
+	if renamedFromTag, _ := tags.Get(renamedFromTagName); renamedFromTag != nil {
+		renamedFrom = ptrs.AsPtr(renamedFromTag.Value())
+	}
+
+	var generated *string
+	if generatedTag, _ := tags.Get(generatedTagName); generatedTag != nil {
+		if generatedTag.Value() == "" {
+			return nil, errors.Errorf("expression of generated column %s is empty", columnName)
+		}
+
+		if defaultValue != nil {
+			return nil, errors.Errorf("generated column %s can't have default value", columnName)
+		}
+
+		generated = ptrs.AsPtr(generatedTag.Value())
+	}
+
 	var columnType types.AbstractTypeInterface
 	options := make([]assets.ColumnOption, 0)
 
@@ -121,61 +164,73 @@ func (t *tableBag) parseColumnTags(
 			columnType = types.NewIntegerType()
 		case "bigint":
 			columnType = types.NewBigintType()
+		case "uuid":
+			columnType = types.GetType(enums.TypeGuid)
 		case "decimal":
 			columnType = types.NewDecimalType()
-			precisionTag, _ := tags.Get(precisionTagName)
-			if precisionTag != nil {
-				precisionTagValue, _ := strconv.Atoi(precisionTag.Value())
-				options = append(
-					options,
-					func(c *assets.Column) { c.SetPrecision(precisionTagValue) },
-				)
-			}
-			scaleTag, _ := tags.Get(scaleTagName)
-			if scaleTag != nil {
-				scaleTagValue, _ := strconv.Atoi(scaleTag.Value())
-				options = append(
-					options,
-					func(c *assets.Column) { c.SetScale(scaleTagValue) },
-				)
-			}
-
 		case "float":
 			columnType = types.NewFloatType()
 		case "smallfloat":
 			columnType = types.NewSmallFloatType()
+		case "enum":
+			enumTypeName, err := t.store.addEnumType(fieldType, tags)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid enum column %s", columnName)
+			}
+			columnType = types.NewEnumType(enumTypeName)
+			typeName = "enum"
 		default:
-			panic(
-				fmt.Sprintf(
-					"unknown tag type %s for type %s on table %s",
-					typeTagValue,
-					typeName,
-					t.table.GetName(),
-				),
+			return nil, errors.Errorf("unknown tag type %s for type %s", typeTagValue, typeName)
+		}
+	}
+	isTypeInferred := false
+	if goType, ok := getGoColumnType(typeName); ok && columnType == nil {
+		columnType = types.GetType(goType.typeName)
+		isTypeInferred = true
+		isNullable = isNullable || goType.nullable
+
+		if goType.jsonb {
+			options = append(
+				options,
+				func(c *assets.Column) { c.SetPlatformOption("jsonb", true) },
 			)
 		}
+		if goType.precision != nil {
+			options = append(options, assets.WithColumnPrecision(goType.precision))
+		}
+		if goType.scale != nil {
+			options = append(options, assets.WithColumnScale(goType.scale))
+		}
 	}
-	if columnType == nil {
-		switch typeName {
-		case "int":
-			columnType = types.NewIntegerType()
-		case "string":
-			columnType = types.NewStringType()
-		case "bool":
-			columnType = types.NewBooleanType()
-		case "float32":
-			columnType = types.NewSmallFloatType()
-		case "float64":
-			columnType = types.NewFloatType()
+
+	if _, ok := columnType.(*types.DecimalType); ok {
+		if precisionTag, _ := tags.Get(precisionTagName); precisionTag != nil {
+			precisionTagValue, _ := strconv.Atoi(precisionTag.Value())
+			options = append(
+				options,
+				func(c *assets.Column) { c.SetPrecision(precisionTagValue) },
+			)
+		}
+		if scaleTag, _ := tags.Get(scaleTagName); scaleTag != nil {
+			scaleTagValue, _ := strconv.Atoi(scaleTag.Value())
+			options = append(
+				options,
+				func(c *assets.Column) { c.SetScale(scaleTagValue) },
+			)
 		}
 	}
 
+	isNotNull := !isNullable
+
 	if isNotNull {
 		options = append(options, assets.WithColumnNotNull())
 	}
 	if defaultValue != nil {
 		options = append(options, assets.WithColumnDefault(*defaultValue))
 	}
+	if generated != nil {
+		options = append(options, assets.WithColumnGenerated(*generated))
+	}
 	isColumnTypeVarchar := false
 	if columnType != nil {
 		_, isColumnTypeVarchar = columnType.(*types.StringType)
@@ -190,8 +245,11 @@ func (t *tableBag) parseColumnTags(
 		IsForeignKey:      isForeignKey,
 		OnUpdate:          onUpdate,
 		OnDelete:          onDelete,
+		References:        references,
+		ForeignKey:        foreignKey,
 		IsNotNull:         isNotNull,
 		TypeName:          typeName,
+		IsTypeInferred:    isTypeInferred,
 		IsUnique:          isUnique,
 		UniqueName:        uniqueName,
 		IsUniqueCondition: isUniqueCondition,
@@ -202,7 +260,8 @@ func (t *tableBag) parseColumnTags(
 		IndexCondition:    indexCondition,
 		Length:            length,
 		DefaultValue:      defaultValue,
+		RenamedFrom:       renamedFrom,
 		ColumnType:        columnType,
 		Options:           options,
-	}
+	}, nil
 }
@@ -5,9 +5,31 @@ import (
 	"strings"
 )
 
+// defaultNamespace - Schema of the tables which are not qualified
+const defaultNamespace = "public"
+
+// getName - Returns table name of the struct, qualified with schema when it is not the default one.
+// Schema from "// StructName schema.table_name" comment wins over schema of the mapping, table name of the struct
+// without comment is set by the naming strategy.
 func getName(store *store, name string) string {
-	if value, ok := store.namesMap[name]; ok {
+	namespace := store.namespacesMap[name]
+
+	value, hasName := store.namesMap[name]
+	if !hasName {
+		name = store.naming.TableName(name)
+	} else {
 		name = strings.TrimSpace(value)
+
+		if namespaceName, tableName, ok := strings.Cut(name, "."); ok {
+			namespace, name = namespaceName, tableName
+		}
+
+		name = utils.ToSnakeCase(name)
 	}
-	return utils.ToSnakeCase(name)
+
+	if namespace == "" || namespace == defaultNamespace {
+		return name
+	}
+
+	return namespace + "." + name
 }
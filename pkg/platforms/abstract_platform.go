@@ -10,8 +10,9 @@ import (
 	"github.com/KoNekoD/gormite/pkg/types"
 	"github.com/KoNekoD/gormite/pkg/utils"
 	"github.com/KoNekoD/smt/pkg/smt"
-	"github.com/elliotchance/pie/v2"
+	"github.com/gookit/goutil/arrutil"
 	"github.com/gookit/goutil/strutil"
+	"maps"
 	"regexp"
 	"slices"
 	"strconv"
@@ -515,6 +516,7 @@ func (parent *AbstractPlatform) BuildCreateTableSQL(
 	primary := make([]string, 0)
 	var primaryIndex *assets.Index
 	foreignKeys := make([]*assets.ForeignKeyConstraint, 0)
+	checkConstraints := make([]*assets.CheckConstraint, 0)
 
 	for _, index := range table.GetIndexes() {
 		if !index.IsPrimary() {
@@ -539,6 +541,10 @@ func (parent *AbstractPlatform) BuildCreateTableSQL(
 		}
 	}
 
+	for _, name := range slices.Sorted(maps.Keys(table.GetCheckConstraints())) {
+		checkConstraints = append(checkConstraints, table.GetCheckConstraint(name))
+	}
+
 	tableName := table.GetQuotedName(a)
 	options := table.GetOptions()
 
@@ -547,6 +553,7 @@ func (parent *AbstractPlatform) BuildCreateTableSQL(
 	options[`primary_index`] = primaryIndex
 	options[`uniqueConstraints`] = uniqueConstraints
 	options[`foreignKeys`] = foreignKeys
+	options[`checkConstraints`] = checkConstraints
 
 	columns := make([]map[string]any, 0)
 	for _, column := range table.GetColumns() {
@@ -678,7 +685,7 @@ func (parent *AbstractPlatform) GetCreateTableInnerSQL(
 
 	if v, ok := options[`primary`]; ok {
 		columnListSql += `, PRIMARY KEY(` + strings.Join(
-			pie.Unique(v.([]string)),
+			arrutil.Unique(v.([]string)),
 			`, `,
 		) + `)`
 	}
@@ -690,6 +697,12 @@ func (parent *AbstractPlatform) GetCreateTableInnerSQL(
 		query += `, ` + check
 	}
 
+	if v, ok := options[`checkConstraints`]; ok {
+		for _, definition := range v.([]*assets.CheckConstraint) {
+			query += `, ` + a.GetCheckConstraintDeclarationSQL(definition)
+		}
+	}
+
 	query += `)`
 
 	sql := []string{query}
@@ -727,11 +740,33 @@ func (parent *AbstractPlatform) GetAlterSchemaSQL(diff *diff_dtos.SchemaDiff) []
 		}
 	}
 
+	if a.SupportsEnumTypes() {
+		for _, enumType := range diff.GetCreatedEnumTypes() {
+			sql = append(sql, a.GetCreateEnumTypeSQL(enumType))
+		}
+
+		for _, enumTypeDiff := range diff.GetAlteredEnumTypes() {
+			sql = append(sql, a.GetAlterEnumTypeSQL(enumTypeDiff)...)
+		}
+	}
+
 	if a.SupportsSequences() {
 		for _, sequence := range diff.GetAlteredSequences() {
 			sql = append(sql, a.GetAlterSequenceSQL(sequence))
 		}
 
+		renamedSequences := diff.GetRenamedSequences()
+		for _, oldSequenceName := range slices.Sorted(maps.Keys(renamedSequences)) {
+			sequence := renamedSequences[oldSequenceName]
+			sql = append(
+				sql,
+				a.GetRenameSequenceSQL(
+					assets.NewIdentifier(oldSequenceName).GetQuotedName(a),
+					assets.NewIdentifier(sequence.GetShortestName(sequence.GetNamespaceName())).GetQuotedName(a),
+				),
+			)
+		}
+
 		for _, sequence := range diff.GetDroppedSequences() {
 			sql = append(sql, a.GetDropSequenceSQL(sequence.GetQuotedName(a)))
 		}
@@ -748,14 +783,43 @@ func (parent *AbstractPlatform) GetAlterSchemaSQL(diff *diff_dtos.SchemaDiff) []
 		sql = append(sql, a.GetAlterTableSQL(tableDiff)...)
 	}
 
+	// Enum types are dropped when columns of them are dropped or altered
+	if a.SupportsEnumTypes() {
+		for _, enumType := range diff.GetDroppedEnumTypes() {
+			sql = append(sql, a.GetDropEnumTypeSQL(enumType.GetQuotedName(a)))
+		}
+	}
+
 	return sql
 }
+func (parent *AbstractPlatform) GetCreateEnumTypeSQL(enumType *assets.EnumType) string {
+	panic("Not supported")
+}
+func (parent *AbstractPlatform) GetAlterEnumTypeSQL(diff *diff_dtos.EnumTypeDiff) []string {
+	panic("Not supported")
+}
+func (parent *AbstractPlatform) GetDropEnumTypeSQL(name string) string {
+	a := parent.child
+	if !a.SupportsEnumTypes() {
+		panic("Not supported")
+	}
+
+	return `DROP TYPE ` + name
+}
 func (parent *AbstractPlatform) GetCreateSequenceSQL(sequence *assets.Sequence) string {
 	panic("Not supported")
 }
 func (parent *AbstractPlatform) GetAlterSequenceSQL(sequence *assets.Sequence) string {
 	panic("Not supported")
 }
+func (parent *AbstractPlatform) GetRenameSequenceSQL(oldName string, newName string) string {
+	a := parent.child
+	if !a.SupportsSequences() {
+		panic("Not supported")
+	}
+
+	return fmt.Sprintf(`ALTER SEQUENCE %s RENAME TO %s`, oldName, newName)
+}
 func (parent *AbstractPlatform) GetDropSequenceSQL(name string) string {
 	a := parent.child
 	if !a.SupportsSequences() {
@@ -837,6 +901,17 @@ func (parent *AbstractPlatform) GetCreateUniqueConstraintSQL(
 		`, `,
 	) + `)`
 }
+func (parent *AbstractPlatform) GetCreateCheckConstraintSQL(
+	constraint *assets.CheckConstraint,
+	tableName string,
+) string {
+	a := parent.child
+	return `ALTER TABLE ` + tableName + ` ADD ` + a.GetCheckConstraintDeclarationSQL(constraint)
+}
+func (parent *AbstractPlatform) GetCheckConstraintDeclarationSQL(constraint *assets.CheckConstraint) string {
+	a := parent.child
+	return `CONSTRAINT ` + constraint.GetQuotedName(a) + ` CHECK (` + constraint.GetExpression() + `)`
+}
 func (parent *AbstractPlatform) GetDropSchemaSQL(schemaName string) string {
 	a := parent.child
 	if !a.SupportsSchemas() {
@@ -881,6 +956,10 @@ func (parent *AbstractPlatform) GetPreAlterTableIndexForeignKeySQL(diff *diff_dt
 
 	sql := make([]string, 0)
 
+	for _, check := range diff.GetDroppedCheckConstraints() {
+		sql = append(sql, a.GetDropConstraintSQL(check.GetQuotedName(a), tableNameSQL))
+	}
+
 	for _, foreignKey := range diff.GetDroppedForeignKeys() {
 		sql = append(
 			sql,
@@ -917,6 +996,11 @@ func (parent *AbstractPlatform) GetPostAlterTableIndexForeignKeySQL(diff *diff_d
 
 	tableNameSQL := diff.GetOldTable().GetQuotedName(a)
 
+	// Table is already renamed when indexes and foreign keys are created
+	if newName := diff.GetNewName(); newName != nil {
+		tableNameSQL = assets.NewIdentifier(*newName).GetQuotedName(a)
+	}
+
 	for _, foreignKey := range diff.GetAddedForeignKeys() {
 		sql = append(sql, a.GetCreateForeignKeySQL(foreignKey, tableNameSQL))
 	}
@@ -945,6 +1029,10 @@ func (parent *AbstractPlatform) GetPostAlterTableIndexForeignKeySQL(diff *diff_d
 		)
 	}
 
+	for _, check := range diff.GetAddedCheckConstraints() {
+		sql = append(sql, a.GetCreateCheckConstraintSQL(check, tableNameSQL))
+	}
+
 	return sql
 }
 func (parent *AbstractPlatform) GetRenameIndexSQL(
@@ -996,6 +1084,11 @@ func (parent *AbstractPlatform) GetColumnDeclarationSQL(name string, column map[
 	} else {
 		defaultValue := a.GetDefaultValueDeclarationSQL(column)
 
+		// Generated column has no default, its value is computed by the expression
+		if v, ok := column[`generated`]; ok && v.(*string) != nil {
+			defaultValue = a.GetGeneratedColumnDeclarationSQL(*v.(*string))
+		}
+
 		charset := ""
 		if v, ok := column[`charset`]; ok {
 			charset = ` ` + a.GetColumnCharsetDeclarationSQL(v.(string))
@@ -1025,6 +1118,17 @@ func (parent *AbstractPlatform) GetColumnDeclarationSQL(name string, column map[
 
 	return name + ` ` + declaration
 }
+
+// GetGeneratedColumnDeclarationSQL - Returns declaration of the stored generated column which is computed by the
+// expression.
+func (parent *AbstractPlatform) GetGeneratedColumnDeclarationSQL(expression string) string {
+	a := parent.child
+	if !a.SupportsGeneratedColumns() {
+		panic("Not supported")
+	}
+
+	return ` GENERATED ALWAYS AS (` + expression + `) STORED`
+}
 func (parent *AbstractPlatform) GetDecimalTypeDeclarationSQL(column map[string]any) string {
 	precision, hasPrecision := column[`precision`]
 	scale, hasScale := column[`scale`]
@@ -1311,6 +1415,25 @@ func (parent *AbstractPlatform) GetDateTimeTzTypeDeclarationSQL(column map[strin
 	return a.GetDateTimeTypeDeclarationSQL(column)
 }
 
+// GetDateIntervalTypeDeclarationSQL - Platforms without interval type store intervals as strings.
+func (parent *AbstractPlatform) GetDateIntervalTypeDeclarationSQL(column map[string]any) string {
+	a := parent.child
+	column["length"] = 255
+
+	return a.GetStringTypeDeclarationSQL(column)
+}
+
+func (parent *AbstractPlatform) GetArrayTypeDeclarationSQL(
+	column map[string]any,
+	elementType types.AbstractTypeInterface,
+) string {
+	panic("Not supported")
+}
+
+func (parent *AbstractPlatform) GetEnumTypeDeclarationSQL(column map[string]any, name string) string {
+	panic("Not supported")
+}
+
 func (parent *AbstractPlatform) GetFloatTypeDeclarationSQL(column map[string]any) string {
 	return `DOUBLE PRECISION`
 }
@@ -1320,6 +1443,12 @@ func (parent *AbstractPlatform) GetSmallFloatTypeDeclarationSQL(column map[strin
 func (parent *AbstractPlatform) SupportsSequences() bool {
 	return false
 }
+func (parent *AbstractPlatform) SupportsEnumTypes() bool {
+	return false
+}
+func (parent *AbstractPlatform) SupportsGeneratedColumns() bool {
+	return false
+}
 func (parent *AbstractPlatform) SupportsIdentityColumns() bool {
 	return false
 }
@@ -1482,6 +1611,10 @@ func (parent *AbstractPlatform) ColumnsEqual(
 	delete(column1Array, "columnDefinition")
 	delete(column2Array, "columnDefinition")
 
+	// Generated expressions are compared in the normalized form since the database reformats them
+	column1Array["generated"] = column1.GetNormalizedGenerated()
+	column2Array["generated"] = column2.GetNormalizedGenerated()
+
 	if a.GetColumnDeclarationSQL(
 		``,
 		column1Array,
@@ -85,5 +85,6 @@ type AbstractPlatformInterface interface {
 	GetPreAlterTableIndexForeignKeySQL(diff *diff_dtos.TableDiff) []string
 	GetPostAlterTableIndexForeignKeySQL(diff *diff_dtos.TableDiff) []string
 	GetAlterTableSQL(diff *diff_dtos.TableDiff) []string
+	GetAlterEnumTypeSQL(diff *diff_dtos.EnumTypeDiff) []string
 	GetAlterSchemaSQL(diff *diff_dtos.SchemaDiff) []string
 }
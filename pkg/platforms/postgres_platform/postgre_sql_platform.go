@@ -9,7 +9,9 @@ import (
 	"github.com/KoNekoD/gormite/pkg/platforms"
 	"github.com/KoNekoD/gormite/pkg/schema_managers"
 	"github.com/KoNekoD/gormite/pkg/schema_managers/postgres_schema_manager"
-	"github.com/elliotchance/pie/v2"
+	"github.com/KoNekoD/gormite/pkg/types"
+	"github.com/gookit/goutil/arrutil"
+	"maps"
 	"slices"
 	"strconv"
 	"strings"
@@ -80,6 +82,12 @@ func (p *PostgreSQLPlatform) GetCurrentDatabaseExpression() string {
 func (p *PostgreSQLPlatform) SupportsSequences() bool {
 	return true
 }
+func (p *PostgreSQLPlatform) SupportsEnumTypes() bool {
+	return true
+}
+func (p *PostgreSQLPlatform) SupportsGeneratedColumns() bool {
+	return true
+}
 func (p *PostgreSQLPlatform) SupportsSchemas() bool {
 	return true
 }
@@ -105,7 +113,18 @@ func (p *PostgreSQLPlatform) GetListSequencesSQL(database string) string {
                        minimum_value AS min_value,
                        increment AS increment_by
                 FROM   information_schema.sequences
-                WHERE  sequence_catalog = ` + p.QuoteStringLiteral(database) + " AND    sequence_schema NOT LIKE 'pg\\_%' AND    sequence_schema != 'information_schema'"
+                WHERE  sequence_catalog = ` + p.QuoteStringLiteral(database) + " AND    sequence_schema NOT LIKE 'pg\\_%' AND    sequence_schema != 'information_schema'" +
+		// Sequences of the identity columns are parts of the columns
+		` AND NOT EXISTS (
+                    SELECT 1
+                    FROM   pg_depend d
+                    JOIN   pg_class c ON c.oid = d.objid
+                    JOIN   pg_namespace n ON n.oid = c.relnamespace
+                    WHERE  d.classid = 'pg_class'::regclass
+                    AND    d.deptype = 'i'
+                    AND    c.relname = sequence_name
+                    AND    n.nspname = sequence_schema
+                )`
 }
 
 func (p *PostgreSQLPlatform) GetListViewsSQL(database string) string {
@@ -193,6 +212,27 @@ func (p *PostgreSQLPlatform) GetAlterTableSQL(diff *diff_dtos.TableDiff) []strin
 			)
 		}
 
+		if columnDiff.HasGeneratedChanged() {
+			if newColumn.GetGenerated() == nil {
+				// Values computed by the old expression are kept in the plain column, DROP EXPRESSION needs postgres 13
+				sql = append(sql, `ALTER TABLE `+tableNameSQL+` ALTER `+newColumnName+` DROP EXPRESSION`)
+			} else {
+				// Expression of the generated column can't be altered, so the column is recreated, its indexes, foreign
+				// keys and check constraints are recreated by the comparator
+				sql = append(
+					sql,
+					`ALTER TABLE `+tableNameSQL+` DROP `+newColumnName,
+					`ALTER TABLE `+tableNameSQL+` ADD `+p.GetColumnDeclarationSQL(newColumnName, newColumn.ToArray()),
+				)
+
+				if comment := newColumn.GetComment(); comment != `` {
+					commentsSQL = append(commentsSQL, p.GetCommentOnColumnSQL(tableNameSQL, newColumnName, comment))
+				}
+
+				continue
+			}
+		}
+
 		if columnDiff.HasTypeChanged() ||
 			columnDiff.HasPrecisionChanged() ||
 			columnDiff.HasScaleChanged() ||
@@ -212,6 +252,12 @@ func (p *PostgreSQLPlatform) GetAlterTableSQL(diff *diff_dtos.TableDiff) []strin
 			sql = append(sql, `ALTER TABLE `+tableNameSQL+` `+query)
 		}
 
+		// Default can't be set while the column is identity
+		isIdentityDropped := columnDiff.HasAutoIncrementChanged() && !newColumn.GetAutoincrement()
+		if isIdentityDropped {
+			sql = append(sql, `ALTER TABLE `+tableNameSQL+` ALTER `+newColumnName+` DROP IDENTITY`)
+		}
+
 		if columnDiff.HasDefaultChanged() {
 			defaultClause := ""
 			if newColumn.GetColumnDefault() == nil {
@@ -235,17 +281,24 @@ func (p *PostgreSQLPlatform) GetAlterTableSQL(diff *diff_dtos.TableDiff) []strin
 			sql = append(sql, `ALTER TABLE `+tableNameSQL+` `+query)
 		}
 
-		if columnDiff.HasAutoIncrementChanged() {
-			query := ""
-			if newColumn.GetAutoincrement() {
-				query = `ADD GENERATED BY DEFAULT AS IDENTITY`
-			} else {
-				query = `DROP IDENTITY`
-			}
+		if columnDiff.HasAutoIncrementChanged() && newColumn.GetAutoincrement() {
+			sql = append(
+				sql,
+				`ALTER TABLE `+tableNameSQL+` ALTER `+newColumnName+` ADD GENERATED BY DEFAULT AS IDENTITY`,
+				p.getSetSequenceValueSQL(
+					`pg_get_serial_sequence(`+p.QuoteStringLiteral(tableNameSQL)+`, `+p.QuoteStringLiteral(newColumn.GetName())+`)`,
+					tableNameSQL,
+					newColumnName,
+				),
+			)
+		}
 
+		// Sequence of the id strategy starts generating ids when the column switches to it
+		isDefaultSet := columnDiff.HasDefaultChanged() && newColumn.GetColumnDefault() != nil
+		if sequence, ok := newColumn.GetPlatformOption("sequence").(string); ok && (isIdentityDropped || isDefaultSet) {
 			sql = append(
 				sql,
-				`ALTER TABLE `+tableNameSQL+` ALTER `+newColumnName+` `+query,
+				p.getSetSequenceValueSQL(p.QuoteStringLiteral(sequence), tableNameSQL, newColumnName),
 			)
 		}
 
@@ -262,12 +315,30 @@ func (p *PostgreSQLPlatform) GetAlterTableSQL(diff *diff_dtos.TableDiff) []strin
 		)
 	}
 
+	if newComment := diff.GetNewComment(); newComment != nil {
+		commentsSQL = append(commentsSQL, p.GetCommentOnTableSQL(tableNameSQL, *newComment))
+	}
+
 	sql = append(p.GetPreAlterTableIndexForeignKeySQL(diff), sql...)
 	sql = append(sql, commentsSQL...)
+
+	if newName := diff.GetNewName(); newName != nil {
+		// RENAME TO accepts only the name, table stays in its schema
+		newIdentifier := assets.NewIdentifier(*newName)
+		newNameSQL := assets.NewIdentifier(newIdentifier.GetShortestName(newIdentifier.GetNamespaceName())).GetQuotedName(p)
+
+		sql = append(sql, p.GetRenameTableSQL(tableNameSQL, newNameSQL))
+	}
+
 	sql = append(sql, p.GetPostAlterTableIndexForeignKeySQL(diff)...)
 
 	return sql
 }
+
+// getSetSequenceValueSQL - Moves the sequence past the existing ids, so the generated ids don't collide with them.
+func (p *PostgreSQLPlatform) getSetSequenceValueSQL(sequenceSQL string, tableNameSQL string, columnName string) string {
+	return `SELECT setval(` + sequenceSQL + `, COALESCE(MAX(` + columnName + `), 0) + 1, false) FROM ` + tableNameSQL
+}
 func (p *PostgreSQLPlatform) GetRenameIndexSQL(
 	oldIndexName string,
 	index *assets.Index,
@@ -287,6 +358,104 @@ func (p *PostgreSQLPlatform) GetCreateSequenceSQL(sequence *assets.Sequence) str
 		` START ` + strconv.Itoa(sequence.GetInitialValue()) +
 		p.GetSequenceCacheSQL(sequence)
 }
+func (p *PostgreSQLPlatform) GetCreateEnumTypeSQL(enumType *assets.EnumType) string {
+	values := arrutil.Map(
+		enumType.GetValues(),
+		func(value string) (string, bool) { return p.QuoteStringLiteral(value), true },
+	)
+
+	return `CREATE TYPE ` + enumType.GetQuotedName(p) + ` AS ENUM (` + strings.Join(values, `, `) + `)`
+}
+
+// GetAlterEnumTypeSQL - Values are renamed and added in place, so the order of the values is kept. Postgres can't
+// drop values of the enum type, the type is recreated for that.
+func (p *PostgreSQLPlatform) GetAlterEnumTypeSQL(diff *diff_dtos.EnumTypeDiff) []string {
+	enumType := diff.GetNewEnumType()
+	enumTypeName := enumType.GetQuotedName(p)
+	sql := make([]string, 0)
+
+	renamedValues := diff.GetRenamedValues()
+	for _, oldValue := range slices.Sorted(maps.Keys(renamedValues)) {
+		sql = append(
+			sql,
+			`ALTER TYPE `+enumTypeName+` RENAME VALUE `+p.QuoteStringLiteral(oldValue)+
+				` TO `+p.QuoteStringLiteral(renamedValues[oldValue]),
+		)
+	}
+
+	if diff.RequiresRecreation() {
+		return append(sql, p.getRecreateEnumTypeSQL(diff)...)
+	}
+
+	values := enumType.GetValues()
+	addedValues := diff.GetAddedValues()
+	for _, value := range addedValues {
+		i := slices.Index(values, value)
+
+		// Values added after all existing ones are appended, others are placed next to their neighbours
+		position := ``
+		if next := slices.IndexFunc(values[i+1:], func(v string) bool { return !slices.Contains(addedValues, v) }); next != -1 {
+			if i > 0 {
+				position = ` AFTER ` + p.QuoteStringLiteral(values[i-1])
+			} else {
+				position = ` BEFORE ` + p.QuoteStringLiteral(values[i+1+next])
+			}
+		}
+
+		sql = append(sql, `ALTER TYPE `+enumTypeName+` ADD VALUE `+p.QuoteStringLiteral(value)+position)
+	}
+
+	return sql
+}
+
+// getRecreateEnumTypeSQL - Renames the old type, creates the new one and converts columns to it through text, so
+// conversion fails instead of losing rows which still have dropped values. Defaults are dropped during conversion,
+// since they can't be cast between the types.
+func (p *PostgreSQLPlatform) getRecreateEnumTypeSQL(diff *diff_dtos.EnumTypeDiff) []string {
+	enumType := diff.GetNewEnumType()
+	enumTypeName := enumType.GetQuotedName(p)
+
+	// RENAME TO accepts only the name, type stays in its schema
+	oldEnumType := assets.NewEnumType(enumType.GetName()+`__old`, nil)
+	oldEnumTypeName := assets.NewIdentifier(oldEnumType.GetShortestName(oldEnumType.GetNamespaceName())).GetQuotedName(p)
+
+	sql := []string{
+		`ALTER TYPE ` + enumTypeName + ` RENAME TO ` + oldEnumTypeName,
+		p.GetCreateEnumTypeSQL(enumType),
+	}
+
+	for _, enumTypeColumn := range diff.GetColumns() {
+		tableNameSQL := enumTypeColumn.GetTable().GetQuotedName(p)
+		column := enumTypeColumn.GetColumn()
+		columnName := column.GetQuotedName(p)
+		columnType := column.GetColumnType()
+
+		textType := `text`
+		if _, ok := columnType.(*types.ArrayType); ok {
+			textType = `text[]`
+		}
+
+		if column.GetColumnDefault() != nil {
+			sql = append(sql, `ALTER TABLE `+tableNameSQL+` ALTER `+columnName+` DROP DEFAULT`)
+		}
+
+		declaration := columnType.GetSQLDeclaration(column.ToArray(), p)
+		sql = append(
+			sql,
+			`ALTER TABLE `+tableNameSQL+` ALTER `+columnName+` TYPE `+declaration+
+				` USING `+columnName+`::`+textType+`::`+declaration,
+		)
+
+		if column.GetColumnDefault() != nil {
+			sql = append(
+				sql,
+				`ALTER TABLE `+tableNameSQL+` ALTER `+columnName+` SET`+p.GetDefaultValueDeclarationSQL(column.ToArray()),
+			)
+		}
+	}
+
+	return append(sql, `DROP TYPE `+oldEnumType.GetQuotedName(p))
+}
 func (p *PostgreSQLPlatform) GetAlterSequenceSQL(sequence *assets.Sequence) string {
 	return `ALTER SEQUENCE ` + sequence.GetQuotedName(p) +
 		` INCREMENT BY ` + strconv.Itoa(sequence.GetAllocationSize()) +
@@ -317,6 +486,12 @@ func (p *PostgreSQLPlatform) GetDropIndexSQL(name string, table string) string {
 		return p.GetDropConstraintSQL(constraintName, table)
 	}
 
+	// Index lives in the schema of its table
+	if strings.Contains(table, `.`) {
+		schema := strings.Split(table, ".")[0]
+		name = schema + `.` + name
+	}
+
 	return p.AbstractPlatform.GetDropIndexSQL(name, table)
 }
 func (p *PostgreSQLPlatform) _getCreateTableSQL(
@@ -327,10 +502,16 @@ func (p *PostgreSQLPlatform) _getCreateTableSQL(
 	queryFields := p.GetColumnDeclarationListSQL(columns)
 
 	if v, ok := options[`primary`]; ok {
-		keyColumns := pie.Unique(v.([]string))
+		keyColumns := arrutil.Unique(v.([]string))
 		queryFields += `, PRIMARY KEY(` + strings.Join(keyColumns, `, `) + `)`
 	}
 
+	if v, ok := options[`checkConstraints`]; ok {
+		for _, check := range v.([]*assets.CheckConstraint) {
+			queryFields += `, ` + p.GetCheckConstraintDeclarationSQL(check)
+		}
+	}
+
 	unloggedV, unloggedOk := options[`unlogged`]
 	unlogged := ""
 	if unloggedOk && unloggedV == true {
@@ -485,6 +666,18 @@ func (p *PostgreSQLPlatform) GetDateTimeTypeDeclarationSQL(column map[string]any
 func (p *PostgreSQLPlatform) GetDateTimeTzTypeDeclarationSQL(column map[string]any) string {
 	return `TIMESTAMP(0) WITH TIME ZONE`
 }
+func (p *PostgreSQLPlatform) GetDateIntervalTypeDeclarationSQL(column map[string]any) string {
+	return `INTERVAL`
+}
+func (p *PostgreSQLPlatform) GetArrayTypeDeclarationSQL(
+	column map[string]any,
+	elementType types.AbstractTypeInterface,
+) string {
+	return elementType.GetSQLDeclaration(column, p) + `[]`
+}
+func (p *PostgreSQLPlatform) GetEnumTypeDeclarationSQL(column map[string]any, name string) string {
+	return assets.NewIdentifier(name).GetQuotedName(p)
+}
 func (p *PostgreSQLPlatform) GetDateTypeDeclarationSQL(column map[string]any) string {
 	return `DATE`
 }
@@ -569,7 +762,7 @@ func (p *PostgreSQLPlatform) InitializeDoctrineTypeMappings() {
 		`int4`:             enums.TypeInteger,
 		`int8`:             enums.TypeBigint,
 		`integer`:          enums.TypeInteger,
-		`interval`:         enums.TypeString,
+		`interval`:         enums.TypeDateinterval,
 		`json`:             enums.TypeJson,
 		`jsonb`:            enums.TypeJson,
 		`money`:            enums.TypeDecimal,
@@ -588,7 +781,6 @@ func (p *PostgreSQLPlatform) InitializeDoctrineTypeMappings() {
 		`uuid`:             enums.TypeGuid,
 		`varchar`:          enums.TypeString,
 		`year`:             enums.TypeDateMutable,
-		`_varchar`:         enums.TypeString,
 	}
 }
 func (p *PostgreSQLPlatform) CreateReservedKeywordsList() keywords.KeywordListInterface {
@@ -627,8 +819,63 @@ func (p *PostgreSQLPlatform) GetIndexDeclarationSQL(index *assets.Index) string
 		panic(`Incomplete definition. "columns" required.`)
 	}
 
-	return `CONSTRAINT ` + index.GetQuotedName(p) + ` ` + p.GetCreateIndexSQLFlags(index) + `(` + strings.Join(
-		index.GetQuotedColumns(p),
-		`, `,
-	) + `)` + p.GetPartialIndexSQL(index)
+	return `CONSTRAINT ` + index.GetQuotedName(p) + ` ` + p.GetCreateIndexSQLFlags(index) + p.getIndexUsingSQL(index) +
+		`(` + p.getIndexColumnsSQL(index) + `)` + p.getIndexIncludeSQL(index) + p.GetPartialIndexSQL(index)
+}
+
+func (p *PostgreSQLPlatform) GetCreateIndexSQL(index *assets.Index, table string) string {
+	if index.IsPrimary() {
+		return p.AbstractPlatform.GetCreateIndexSQL(index, table)
+	}
+
+	name := index.GetQuotedName(p)
+
+	if len(index.GetColumns()) == 0 {
+		panic(fmt.Errorf(`incomplete or invalid index definition %s on table %s`, name, table))
+	}
+
+	return `CREATE ` + p.GetCreateIndexSQLFlags(index) + `INDEX ` + name + ` ON ` + table + ` ` +
+		p.getIndexUsingSQL(index) + `(` + p.getIndexColumnsSQL(index) + `)` + p.getIndexIncludeSQL(index) +
+		p.GetPartialIndexSQL(index)
+}
+
+// getIndexUsingSQL - Returns "USING method " clause, it is omitted for the default btree method.
+func (p *PostgreSQLPlatform) getIndexUsingSQL(index *assets.Index) string {
+	if using := index.GetUsing(); using != `` {
+		return `USING ` + using + ` `
+	}
+
+	return ``
+}
+
+// getIndexColumnsSQL - Returns columns and expressions of the index with their operator classes and orderings.
+func (p *PostgreSQLPlatform) getIndexColumnsSQL(index *assets.Index) string {
+	columns := index.GetColumns()
+	quotedColumns := index.GetQuotedColumns(p)
+
+	for i, column := range columns {
+		if opclass := index.GetColumnOpclass(column); opclass != `` {
+			quotedColumns[i] += ` ` + opclass
+		}
+
+		if order := index.GetColumnOrder(column); order != `` {
+			quotedColumns[i] += ` ` + order
+		}
+	}
+
+	return strings.Join(quotedColumns, `, `)
+}
+
+func (p *PostgreSQLPlatform) getIndexIncludeSQL(index *assets.Index) string {
+	include := index.GetIncludeColumns()
+	if len(include) == 0 {
+		return ``
+	}
+
+	quotedColumns := make([]string, 0, len(include))
+	for _, column := range include {
+		quotedColumns = append(quotedColumns, assets.NewIdentifier(column).GetQuotedName(p))
+	}
+
+	return ` INCLUDE (` + strings.Join(quotedColumns, `, `) + `)`
 }
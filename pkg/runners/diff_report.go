@@ -0,0 +1,169 @@
+package runners
+
+import (
+	"github.com/KoNekoD/gormite/pkg/assets"
+	"github.com/KoNekoD/gormite/pkg/diff_dtos"
+	"slices"
+	"strings"
+)
+
+const (
+	TableActionCreate = "create"
+	TableActionAlter  = "alter"
+	TableActionDrop   = "drop"
+)
+
+// DiffReport - Machine-readable result of the diff and validate scenarios.
+type DiffReport struct {
+	InSync    bool                  `json:"in_sync"`
+	Up        []string              `json:"up"`
+	Down      []string              `json:"down"`
+	Tables    []*TableChangeSummary `json:"tables"`
+	Sequences []string              `json:"sequences,omitempty"`
+	Changes   []*diff_dtos.Change   `json:"changes"`
+	Files     []string              `json:"files,omitempty"`
+}
+
+// TableChangeSummary - Names of the changed table assets.
+type TableChangeSummary struct {
+	Name               string            `json:"name"`
+	Action             string            `json:"action"`
+	RenamedTo          string            `json:"renamed_to,omitempty"`
+	AddedColumns       []string          `json:"added_columns,omitempty"`
+	ChangedColumns     []string          `json:"changed_columns,omitempty"`
+	RenamedColumns     map[string]string `json:"renamed_columns,omitempty"`
+	DroppedColumns     []string          `json:"dropped_columns,omitempty"`
+	AddedIndexes       []string          `json:"added_indexes,omitempty"`
+	ChangedIndexes     []string          `json:"changed_indexes,omitempty"`
+	RenamedIndexes     map[string]string `json:"renamed_indexes,omitempty"`
+	DroppedIndexes     []string          `json:"dropped_indexes,omitempty"`
+	AddedForeignKeys   []string          `json:"added_foreign_keys,omitempty"`
+	ChangedForeignKeys []string          `json:"changed_foreign_keys,omitempty"`
+	DroppedForeignKeys []string          `json:"dropped_foreign_keys,omitempty"`
+}
+
+func NewDiffReport(diff *diff_dtos.SchemaDiff, up []string, down []string) *DiffReport {
+	report := &DiffReport{
+		InSync:  diff.IsEmpty(),
+		Up:      up,
+		Down:    down,
+		Tables:  make([]*TableChangeSummary, 0),
+		Changes: diff.GetChanges(),
+	}
+
+	for _, table := range diff.GetCreatedTables() {
+		summary := &TableChangeSummary{Name: table.GetName(), Action: TableActionCreate}
+		for _, column := range table.GetColumns() {
+			summary.AddedColumns = append(summary.AddedColumns, column.GetName())
+		}
+
+		report.Tables = append(report.Tables, summary)
+	}
+
+	for _, tableDiff := range diff.GetAlteredTables() {
+		report.Tables = append(report.Tables, newTableChangeSummary(tableDiff))
+	}
+
+	for _, table := range diff.GetDroppedTables() {
+		report.Tables = append(report.Tables, &TableChangeSummary{Name: table.GetName(), Action: TableActionDrop})
+	}
+
+	slices.SortFunc(
+		report.Tables, func(a, b *TableChangeSummary) int { return strings.Compare(a.Name, b.Name) },
+	)
+
+	for _, sequences := range [][]*assets.Sequence{
+		diff.GetCreatedSequences(),
+		diff.GetAlteredSequences(),
+		diff.GetDroppedSequences(),
+	} {
+		for _, sequence := range sequences {
+			report.Sequences = append(report.Sequences, sequence.GetName())
+		}
+	}
+
+	for _, sequence := range diff.GetRenamedSequences() {
+		report.Sequences = append(report.Sequences, sequence.GetName())
+	}
+	slices.Sort(report.Sequences)
+
+	return report
+}
+
+func newTableChangeSummary(tableDiff *diff_dtos.TableDiff) *TableChangeSummary {
+	summary := &TableChangeSummary{Name: tableDiff.GetOldTable().GetName(), Action: TableActionAlter}
+
+	if newName := tableDiff.GetNewName(); newName != nil {
+		summary.RenamedTo = *newName
+	}
+
+	for _, column := range tableDiff.GetAddedColumns() {
+		summary.AddedColumns = append(summary.AddedColumns, column.GetName())
+	}
+
+	for _, columnDiff := range tableDiff.GetChangedColumns() {
+		oldName, newName := columnDiff.GetOldColumn().GetName(), columnDiff.GetNewColumn().GetName()
+
+		if columnDiff.HasNameChanged() {
+			if summary.RenamedColumns == nil {
+				summary.RenamedColumns = make(map[string]string)
+			}
+			summary.RenamedColumns[oldName] = newName
+		}
+
+		if !columnDiff.HasNameChanged() || columnDiff.CountChangedProperties() > 1 {
+			summary.ChangedColumns = append(summary.ChangedColumns, newName)
+		}
+	}
+
+	for _, column := range tableDiff.GetDroppedColumns() {
+		summary.DroppedColumns = append(summary.DroppedColumns, column.GetName())
+	}
+
+	for _, index := range tableDiff.GetAddedIndexes() {
+		summary.AddedIndexes = append(summary.AddedIndexes, index.GetName())
+	}
+
+	for _, index := range tableDiff.GetModifiedIndexes() {
+		summary.ChangedIndexes = append(summary.ChangedIndexes, index.GetName())
+	}
+
+	for oldName, index := range tableDiff.GetRenamedIndexes() {
+		if summary.RenamedIndexes == nil {
+			summary.RenamedIndexes = make(map[string]string)
+		}
+		summary.RenamedIndexes[oldName] = index.GetName()
+	}
+
+	for _, index := range tableDiff.GetDroppedIndexes() {
+		summary.DroppedIndexes = append(summary.DroppedIndexes, index.GetName())
+	}
+
+	for _, fk := range tableDiff.GetAddedForeignKeys() {
+		summary.AddedForeignKeys = append(summary.AddedForeignKeys, fk.GetName())
+	}
+
+	for _, fk := range tableDiff.GetModifiedForeignKeys() {
+		summary.ChangedForeignKeys = append(summary.ChangedForeignKeys, fk.GetName())
+	}
+
+	for _, fk := range tableDiff.GetDroppedForeignKeys() {
+		summary.DroppedForeignKeys = append(summary.DroppedForeignKeys, fk.GetName())
+	}
+
+	for _, names := range [][]string{
+		summary.AddedColumns,
+		summary.ChangedColumns,
+		summary.DroppedColumns,
+		summary.AddedIndexes,
+		summary.ChangedIndexes,
+		summary.DroppedIndexes,
+		summary.AddedForeignKeys,
+		summary.ChangedForeignKeys,
+		summary.DroppedForeignKeys,
+	} {
+		slices.Sort(names)
+	}
+
+	return summary
+}
@@ -2,16 +2,26 @@ package runners
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"github.com/KoNekoD/gormite/pkg/assets"
 	"github.com/KoNekoD/gormite/pkg/diff_calc"
+	"github.com/KoNekoD/gormite/pkg/diff_dtos"
+	"github.com/KoNekoD/gormite/pkg/dtos"
+	"github.com/KoNekoD/gormite/pkg/enums"
 	"github.com/KoNekoD/gormite/pkg/gormite_databases"
 	"github.com/KoNekoD/gormite/pkg/local_schema"
 	"github.com/KoNekoD/gormite/pkg/platforms"
 	"github.com/KoNekoD/gormite/pkg/platforms/postgres_platform"
 	"github.com/KoNekoD/gormite/pkg/schema_managers/postgres_schema_manager"
+	"github.com/KoNekoD/gormite/pkg/schema_snapshots"
 	"github.com/charmbracelet/log"
 	"github.com/pkg/errors"
+	"golang.org/x/exp/maps"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -26,6 +36,17 @@ const GooseMigrationTemplate = `-- +goose Up
 -- +goose StatementEnd
 `
 
+// GooseNoTransactionMigrationTemplate - Statements are not wrapped, so goose applies them one by one without
+// transaction.
+const GooseNoTransactionMigrationTemplate = `-- +goose NO TRANSACTION
+
+-- +goose Up
+%s
+
+-- +goose Down
+%s
+`
+
 type MigrationToolType string
 
 const (
@@ -36,88 +57,319 @@ const (
 const (
 	ScenarioTypeDiff     = "diff"
 	ScenarioTypeValidate = "validate"
+	ScenarioTypeMigrate  = "migrate"
+	ScenarioTypeImport   = "import"
 )
 
+const (
+	OutputFormatText = "text"
+	OutputFormatJson = "json"
+)
+
+// OutputStdout - Output value for printing migrations instead of writing files.
+const OutputStdout = "-"
+
+// DefaultFileNameTemplate - Migration file name without extension, {version} is replaced with the current timestamp.
+const DefaultFileNameTemplate = "{version}_gen"
+
+// migrationVersionLayout - Timestamp layout of {version} in the migration file name.
+const migrationVersionLayout = "20060102150405"
+
+// ValidateFileNameTemplate - Checks that migration files named by the template are read by ReadMigrations,
+// e.g. "gen_{version}" is rejected, since the version must start the name.
+func ValidateFileNameTemplate(template string) error {
+	name := strings.ReplaceAll(template, "{version}", migrationVersionLayout)
+
+	if filepath.Base(name) != name || !gooseFileRegex.MatchString(name+".sql") {
+		return errors.Errorf(
+			"invalid migration file name template %s, expected {version}_<name> like %s",
+			template,
+			DefaultFileNameTemplate,
+		)
+	}
+
+	return nil
+}
+
 type DiffRunnerOptions struct {
-	Tool       string
-	Dsn        string
-	ConfigPath string
-	Scenario   string
+	Tool             string
+	Dsn              string
+	Env              string
+	ConfigPath       string
+	Scenario         string
+	FromSnapshot     string
+	Snapshot         string
+	Output           string
+	Format           string
+	FileNameTemplate string
+	AllowDestructive bool
 }
 
 type DiffRunner struct{ opts DiffRunnerOptions }
 
 func NewDiffRunner(opts DiffRunnerOptions) *DiffRunner {
+	if opts.Output == "" {
+		opts.Output = "migrations"
+	}
+	if opts.Format == "" {
+		opts.Format = OutputFormatText
+	}
+	if opts.FileNameTemplate == "" {
+		opts.FileNameTemplate = DefaultFileNameTemplate
+	}
+
 	return &DiffRunner{opts: opts}
 }
 
 func (r *DiffRunner) Run(ctx context.Context) error {
-	db := gormite_databases.NewPostgresDatabase(ctx, r.opts.Dsn)
+	if err := ValidateFileNameTemplate(r.opts.FileNameTemplate); err != nil {
+		return err
+	}
 
-	platform := postgres_platform.NewPostgreSQLPlatform()
+	config, err := dtos.NewConfigData(r.opts.ConfigPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to read config")
+	}
 
-	manager := postgres_schema_manager.NewPostgreSQLSchemaManager(platforms.NewConnection(db, platform), platform)
+	// Database is not needed when diff is made against snapshot
+	if r.opts.FromSnapshot == "" {
+		if r.opts.Dsn, err = resolveDsn(r.opts.Dsn, r.opts.Env, config); err != nil {
+			return err
+		}
+	}
+
+	platform := postgres_platform.NewPostgreSQLPlatform()
 
-	oldSchema := manager.IntrospectSchema()
+	manager, oldSchema, err := r.introspectOldSchema(ctx, platform)
+	if err != nil {
+		return errors.Wrap(err, "failed to introspect old schema")
+	}
 
 	newSchema, err := local_schema.IntrospectLocalSchema(r.opts.ConfigPath)
 	if err != nil {
 		return errors.Wrap(err, "failed to introspect local schema")
 	}
 
-	c := diff_calc.NewComparator(platform)
+	// Assets excluded by schema_filter are not managed by gormite on both sides
+	oldSchema.FilterAssets(config.Gormite.SchemaFilter.IsIncluded)
+	newSchema.FilterAssets(config.Gormite.SchemaFilter.IsIncluded)
+
+	naming, err := local_schema.NewNamingStrategy(config.Gormite.Orm.Naming)
+	if err != nil {
+		return errors.Wrap(err, "failed to create naming strategy")
+	}
+
+	// Sequences of renamed tables are renamed by the same strategy which names them on introspection
+	c := diff_calc.NewComparator(platform).SetSequenceNaming(naming)
 
 	diff := c.CompareSchemas(oldSchema, newSchema)
 	diffDown := c.CompareSchemas(newSchema, oldSchema)
 
+	report := NewDiffReport(diff, manager.AlterSchemaSqlList(diff), manager.AlterSchemaSqlList(diffDown))
+
 	switch r.opts.Scenario {
 	case ScenarioTypeDiff:
 		if diff.IsEmpty() {
+			if err := r.printReport(report); err != nil {
+				return err
+			}
+
 			return errors.New("No changes detected")
 		}
 
-		up := manager.AlterSchema(diff)
-		down := manager.AlterSchema(diffDown)
-
-		switch r.opts.Tool {
-		case string(MigrationToolTypeMigrate):
-			key := time.Now().Format("20060102150405")
-			file := fmt.Sprintf("migrations/%s_gen.up.sql", key)
-			fileDown := fmt.Sprintf("migrations/%s_gen.down.sql", key)
-			if err := os.WriteFile(file, []byte(up), 0644); err != nil {
-				return errors.Wrap(err, "Cannot write up sql file")
-			}
-			if err := os.WriteFile(fileDown, []byte(down), 0644); err != nil {
-				return errors.Wrap(err, "Cannot write down sql file")
+		if err := r.checkChanges(report.Changes); err != nil {
+			if err := r.printReport(report); err != nil {
+				return err
 			}
-		case string(MigrationToolTypeGoose):
-			key := time.Now().Format("20060102150405")
-			file := fmt.Sprintf("migrations/%s_gen.sql", key)
-			if err := os.WriteFile(
-				file,
-				[]byte(fmt.Sprintf(GooseMigrationTemplate, up, down)),
-				0644,
-			); err != nil {
-				return errors.Wrap(err, "Cannot write migration file")
+
+			return err
+		}
+
+		// Added enum values can't be used in the transaction which adds them
+		noTransaction := diff.HasAddedEnumValues() || diffDown.HasAddedEnumValues()
+
+		files, err := r.writeMigrations(manager.AlterSchema(diff), manager.AlterSchema(diffDown), noTransaction)
+		if err != nil {
+			return err
+		}
+		report.Files = files
+
+		// Snapshot is refreshed only when migrations are persisted
+		if snapshotPath := r.getSnapshotPath(); snapshotPath != "" && r.opts.Output != OutputStdout {
+			if err := schema_snapshots.WriteFile(snapshotPath, newSchema); err != nil {
+				return errors.Wrap(err, "Cannot write schema snapshot")
 			}
 		}
+
+		if err := r.printReport(report); err != nil {
+			return err
+		}
 	case ScenarioTypeValidate:
-		if diff.IsEmpty() {
+		if r.opts.Format == OutputFormatJson {
+			if err := r.printReport(report); err != nil {
+				return err
+			}
+		} else if diff.IsEmpty() {
 			log.Info("The database schema is in sync with the mapping files.")
-			return nil
+		} else {
+			log.Error("The database schema is not in sync with the current mapping file.")
+
+			log.Infof("%d schema diff(s) detected:", len(report.Up))
+			for _, sql := range report.Up {
+				log.Infof("    %s", sql)
+			}
+		}
+
+		if !diff.IsEmpty() {
+			return errors.New("The database schema is not in sync with the current mapping file.")
 		}
+	}
+
+	return nil
+}
+
+// checkChanges - Refuses destructive changes unless they are explicitly allowed, lossy ones are only reported.
+func (r *DiffRunner) checkChanges(changes []*diff_dtos.Change) error {
+	for _, change := range diff_dtos.FilterChanges(changes, enums.ChangeSafetyLossy) {
+		log.Warnf("Lossy change: %s", change)
+	}
+
+	destructive := diff_dtos.FilterChanges(changes, enums.ChangeSafetyDestructive)
+	if len(destructive) == 0 {
+		return nil
+	}
+
+	if r.opts.AllowDestructive {
+		for _, change := range destructive {
+			log.Warnf("Destructive change: %s", change)
+		}
+
+		return nil
+	}
+
+	lines := make([]string, 0, len(destructive))
+	for _, change := range destructive {
+		lines = append(lines, "    "+change.String())
+	}
+
+	return errors.Errorf(
+		"Destructive changes detected, pass --allow-destructive to generate them anyway:\n%s",
+		strings.Join(lines, "\n"),
+	)
+}
+
+// writeMigrations - Writes migration files of the selected tool, or prints them when output is stdout.
+// Returns paths of the written files.
+func (r *DiffRunner) writeMigrations(up, down string, noTransaction bool) ([]string, error) {
+	contents := make(map[string]string)
+
+	switch r.opts.Tool {
+	case string(MigrationToolTypeMigrate):
+		if noTransaction {
+			up = gooseNoTransactionAnnotation + "\n" + up
+			down = gooseNoTransactionAnnotation + "\n" + down
+		}
+
+		contents[".up.sql"] = up
+		contents[".down.sql"] = down
+	case string(MigrationToolTypeGoose):
+		template := GooseMigrationTemplate
+		if noTransaction {
+			template = GooseNoTransactionMigrationTemplate
+		}
+
+		contents[".sql"] = fmt.Sprintf(template, up, down)
+	default:
+		return nil, errors.Errorf("unknown migration tool %s", r.opts.Tool)
+	}
+
+	if r.opts.Output == OutputStdout {
+		// Json report already contains sql, so nothing is printed separately
+		if r.opts.Format == OutputFormatText {
+			if r.opts.Tool == string(MigrationToolTypeMigrate) {
+				fmt.Printf("%s\n\n-- DOWN\n\n%s\n", up, down)
+			} else {
+				fmt.Print(contents[".sql"])
+			}
+		}
+
+		return nil, nil
+	}
+
+	if err := os.MkdirAll(r.opts.Output, 0755); err != nil {
+		return nil, errors.Wrap(err, "Cannot create output dir")
+	}
+
+	name := strings.ReplaceAll(r.opts.FileNameTemplate, "{version}", time.Now().Format(migrationVersionLayout))
 
-		sqlList := manager.AlterSchemaSqlList(diff)
+	extensions := maps.Keys(contents)
+	slices.Sort(extensions)
 
-		log.Error("The database schema is not in sync with the current mapping file.")
+	files := make([]string, 0, len(extensions))
 
-		log.Infof("%d schema diff(s) detected:", len(sqlList))
-		for _, sql := range sqlList {
-			log.Infof("    %s", sql)
+	for _, extension := range extensions {
+		file := filepath.Join(r.opts.Output, name+extension)
+
+		if err := os.WriteFile(file, []byte(contents[extension]), 0644); err != nil {
+			return nil, errors.Wrap(err, "Cannot write migration file")
 		}
 
-		return errors.New("The database schema is not in sync with the current mapping file.")
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+func (r *DiffRunner) printReport(report *DiffReport) error {
+	if r.opts.Format != OutputFormatJson {
+		return nil
 	}
 
+	content, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	fmt.Println(string(content))
+
 	return nil
 }
+
+// introspectOldSchema - Reads the old side of the diff from the snapshot file when it is set, otherwise from database.
+func (r *DiffRunner) introspectOldSchema(
+	ctx context.Context,
+	platform *postgres_platform.PostgreSQLPlatform,
+) (*postgres_schema_manager.PostgreSQLSchemaManager, *assets.Schema, error) {
+	if r.opts.FromSnapshot != "" {
+		manager := postgres_schema_manager.NewPostgreSQLSchemaManager(platforms.NewConnection(nil, platform), platform)
+
+		oldSchema, err := schema_snapshots.ReadFile(r.opts.FromSnapshot)
+		if errors.Is(err, os.ErrNotExist) {
+			// No snapshot yet, everything will be created from scratch in the default namespace
+			return manager, assets.NewSchema(nil, nil, nil, []string{"public"}), nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return manager, oldSchema, nil
+	}
+
+	if r.opts.Dsn == "" {
+		return nil, nil, errors.New("either dsn or snapshot is required")
+	}
+
+	db := gormite_databases.NewPostgresDatabase(ctx, r.opts.Dsn)
+
+	manager := postgres_schema_manager.NewPostgreSQLSchemaManager(platforms.NewConnection(db, platform), platform)
+
+	return manager, manager.IntrospectSchema(), nil
+}
+
+func (r *DiffRunner) getSnapshotPath() string {
+	if r.opts.FromSnapshot != "" {
+		return r.opts.FromSnapshot
+	}
+
+	return r.opts.Snapshot
+}
@@ -0,0 +1,24 @@
+package runners
+
+import (
+	"github.com/KoNekoD/gormite/pkg/dtos"
+	"github.com/pkg/errors"
+)
+
+// resolveDsn - Returns dsn passed with the flag, otherwise the one from config for the environment profile.
+func resolveDsn(dsn string, env string, config *dtos.ConfigData) (string, error) {
+	if dsn != "" {
+		return dsn, nil
+	}
+
+	dsn, err := config.GetDsn(env)
+	if err != nil {
+		return "", err
+	}
+
+	if dsn == "" {
+		return "", errors.New("dsn is required, pass --dsn or set gormite.database.dsn in config")
+	}
+
+	return dsn, nil
+}
@@ -0,0 +1,157 @@
+package runners
+
+import (
+	"context"
+	"github.com/KoNekoD/gormite/pkg/dtos"
+	"github.com/KoNekoD/gormite/pkg/gormite_databases"
+	"github.com/KoNekoD/gormite/pkg/local_schema"
+	"github.com/KoNekoD/gormite/pkg/platforms"
+	"github.com/KoNekoD/gormite/pkg/platforms/postgres_platform"
+	"github.com/KoNekoD/gormite/pkg/schema_managers/postgres_schema_manager"
+	"github.com/charmbracelet/log"
+	"github.com/pkg/errors"
+	"go/parser"
+	"go/token"
+	"golang.org/x/exp/maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+type ImportRunnerOptions struct {
+	Dsn        string
+	Env        string
+	ConfigPath string
+	Mapping    string
+}
+
+type ImportRunner struct{ opts ImportRunnerOptions }
+
+func NewImportRunner(opts ImportRunnerOptions) *ImportRunner {
+	return &ImportRunner{opts: opts}
+}
+
+func (r *ImportRunner) Run(ctx context.Context) error {
+	config, err := dtos.NewConfigData(r.opts.ConfigPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to read config")
+	}
+
+	dsn, err := resolveDsn(r.opts.Dsn, r.opts.Env, config)
+	if err != nil {
+		return err
+	}
+
+	dir, err := r.getMappingDir(config)
+	if err != nil {
+		return err
+	}
+
+	packageName, existingObjects, err := r.inspectMappingDir(dir)
+	if err != nil {
+		return errors.Wrap(err, "failed to inspect mapping dir")
+	}
+
+	db := gormite_databases.NewPostgresDatabase(ctx, dsn)
+	defer db.Destruct()
+
+	platform := postgres_platform.NewPostgreSQLPlatform()
+
+	manager := postgres_schema_manager.NewPostgreSQLSchemaManager(platforms.NewConnection(db, platform), platform)
+
+	schema := manager.IntrospectSchema().FilterAssets(config.Gormite.SchemaFilter.IsIncluded)
+
+	naming, err := local_schema.NewNamingStrategy(config.Gormite.Orm.Naming)
+	if err != nil {
+		return errors.Wrap(err, "failed to create naming strategy")
+	}
+
+	entities, err := local_schema.GenerateEntities(schema, packageName, naming)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate entities")
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.WithStack(err)
+	}
+
+	count := 0
+
+	for _, entity := range entities {
+		path := filepath.Join(dir, entity.FileName)
+
+		if slices.Contains(existingObjects, entity.StructName) {
+			log.Warnf("Entity %s already exists, table %s skipped", entity.StructName, entity.TableName)
+			continue
+		}
+
+		if _, err := os.Stat(path); err == nil {
+			log.Warnf("File %s already exists, table %s skipped", path, entity.TableName)
+			continue
+		}
+
+		if err := os.WriteFile(path, entity.Content, 0644); err != nil {
+			return errors.Wrap(err, "Cannot write entity file")
+		}
+
+		log.Infof("Generated %s", path)
+		count++
+	}
+
+	if count == 0 {
+		log.Info("No entities generated.")
+	}
+
+	return nil
+}
+
+// getMappingDir - Returns dir of the mapping where entities will be generated,
+// mapping can be omitted when config has only one.
+func (r *ImportRunner) getMappingDir(config *dtos.ConfigData) (string, error) {
+	mappings := config.Gormite.Orm.Mapping
+
+	mappingKey := r.opts.Mapping
+	if mappingKey == "" {
+		if len(mappings) != 1 {
+			keys := maps.Keys(mappings)
+			slices.Sort(keys)
+
+			return "", errors.Errorf("mapping is required, allowed: %s", strings.Join(keys, ", "))
+		}
+
+		mappingKey = maps.Keys(mappings)[0]
+	}
+
+	mapping, ok := mappings[mappingKey]
+	if !ok {
+		return "", errors.Errorf("unknown mapping %s", mappingKey)
+	}
+
+	return mapping.Dir, nil
+}
+
+// inspectMappingDir - Returns package name and declared objects of the existing entities.
+func (r *ImportRunner) inspectMappingDir(dir string) (string, []string, error) {
+	packageName := strings.ReplaceAll(filepath.Base(dir), "-", "_")
+	objects := make([]string, 0)
+
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return packageName, objects, nil
+	}
+
+	parsed, err := parser.ParseDir(token.NewFileSet(), dir, nil, 0)
+	if err != nil {
+		return "", nil, errors.WithStack(err)
+	}
+
+	for name, parsedPackage := range parsed {
+		packageName = name
+
+		for _, file := range parsedPackage.Files {
+			objects = append(objects, maps.Keys(file.Scope.Objects)...)
+		}
+	}
+
+	return packageName, objects, nil
+}
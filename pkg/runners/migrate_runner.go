@@ -0,0 +1,274 @@
+package runners
+
+import (
+	"context"
+	"fmt"
+	"github.com/KoNekoD/gormite/pkg/dtos"
+	"github.com/KoNekoD/gormite/pkg/gormite_databases"
+	gdh "github.com/KoNekoD/gormite/pkg/gormite_databases_helpers"
+	"github.com/charmbracelet/log"
+	"github.com/pkg/errors"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// MigrationsTableName - Table where applied migration versions are recorded.
+const MigrationsTableName = "gormite_migrations"
+
+const (
+	MigrateActionUp     = "up"
+	MigrateActionDown   = "down"
+	MigrateActionStatus = "status"
+)
+
+type MigrateRunnerOptions struct {
+	Dsn        string
+	Env        string
+	ConfigPath string
+	Dir        string
+	Action     string
+	Steps      int
+}
+
+// MigrateDatabase - Database where migrations are applied, transactions are needed to apply each migration
+// together with its version record.
+type MigrateDatabase interface {
+	gdh.Database
+	WrapInTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type MigrateRunner struct{ opts MigrateRunnerOptions }
+
+func NewMigrateRunner(opts MigrateRunnerOptions) *MigrateRunner {
+	return &MigrateRunner{opts: opts}
+}
+
+// ParseMigrateSteps - Parses steps count of "migrate down [n]", it must be a positive number.
+func ParseMigrateSteps(value string) (int, error) {
+	steps, err := strconv.Atoi(value)
+	if err != nil || steps <= 0 {
+		return 0, errors.Errorf("invalid steps count %s, expected positive number", value)
+	}
+
+	return steps, nil
+}
+
+// IsMigrateStepsArg - Checks whether the argument of "migrate down" is the steps count and not a flag,
+// numbers are steps even when negative, e.g. "-1", so they are rejected by ParseMigrateSteps.
+func IsMigrateStepsArg(value string) bool {
+	if !strings.HasPrefix(value, "-") {
+		return true
+	}
+
+	_, err := strconv.ParseFloat(value, 64)
+	return err == nil
+}
+
+func (r *MigrateRunner) Run(ctx context.Context) error {
+	dsn, err := r.getDsn()
+	if err != nil {
+		return err
+	}
+
+	db := gormite_databases.NewPostgresDatabase(ctx, dsn)
+	defer db.Destruct()
+
+	return r.RunWithDatabase(ctx, db)
+}
+
+// RunWithDatabase - Runs the action against already opened database.
+func (r *MigrateRunner) RunWithDatabase(ctx context.Context, db MigrateDatabase) error {
+	migrations, err := ReadMigrations(r.opts.Dir)
+	if err != nil {
+		return errors.Wrap(err, "failed to read migrations")
+	}
+
+	if err := r.createMigrationsTable(ctx, db); err != nil {
+		return errors.Wrap(err, "failed to create migrations table")
+	}
+
+	applied, err := r.getAppliedVersions(ctx, db)
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch applied migrations")
+	}
+
+	switch r.opts.Action {
+	case MigrateActionUp:
+		return r.up(ctx, db, migrations, applied)
+	case MigrateActionDown:
+		return r.down(ctx, db, migrations, applied)
+	case MigrateActionStatus:
+		r.status(migrations, applied)
+		return nil
+	}
+
+	return errors.Errorf("unknown migrate action %s", r.opts.Action)
+}
+
+// getDsn - Config is read only when dsn is not passed, so migrations can be applied without it.
+func (r *MigrateRunner) getDsn() (string, error) {
+	if r.opts.Dsn != "" {
+		return r.opts.Dsn, nil
+	}
+
+	config, err := dtos.NewConfigData(r.opts.ConfigPath)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read config")
+	}
+
+	return resolveDsn(r.opts.Dsn, r.opts.Env, config)
+}
+
+func (r *MigrateRunner) up(
+	ctx context.Context,
+	db MigrateDatabase,
+	migrations []*Migration,
+	applied []string,
+) error {
+	count := 0
+
+	for _, m := range migrations {
+		if slices.Contains(applied, m.Version) {
+			continue
+		}
+
+		err := r.applyMigration(
+			ctx, db, m, m.Up, m.UpStatements,
+			fmt.Sprintf("INSERT INTO %s (version) VALUES ($1)", MigrationsTableName),
+		)
+		if err != nil {
+			return errors.Wrapf(err, "failed to apply migration %s_%s", m.Version, m.Name)
+		}
+
+		log.Infof("Applied %s_%s", m.Version, m.Name)
+		count++
+	}
+
+	if count == 0 {
+		log.Info("No pending migrations.")
+	}
+
+	return nil
+}
+
+func (r *MigrateRunner) down(
+	ctx context.Context,
+	db MigrateDatabase,
+	migrations []*Migration,
+	applied []string,
+) error {
+	steps := r.opts.Steps
+	if steps <= 0 {
+		steps = 1
+	}
+
+	if len(applied) == 0 {
+		log.Info("No applied migrations.")
+		return nil
+	}
+
+	for i := len(applied) - 1; i >= 0 && steps > 0; i, steps = i-1, steps-1 {
+		version := applied[i]
+
+		idx := slices.IndexFunc(migrations, func(m *Migration) bool { return m.Version == version })
+		if idx == -1 {
+			return errors.Errorf("migration file for applied version %s not found", version)
+		}
+
+		m := migrations[idx]
+
+		err := r.applyMigration(
+			ctx, db, m, m.Down, m.DownStatements,
+			fmt.Sprintf("DELETE FROM %s WHERE version = $1", MigrationsTableName),
+		)
+		if err != nil {
+			return errors.Wrapf(err, "failed to revert migration %s_%s", m.Version, m.Name)
+		}
+
+		log.Infof("Reverted %s_%s", m.Version, m.Name)
+	}
+
+	return nil
+}
+
+// applyMigration - Executes sql of the migration together with the version record in the transaction. Migration
+// without transaction is executed statement by statement, so failed one stays partially applied and is not recorded.
+func (r *MigrateRunner) applyMigration(
+	ctx context.Context,
+	db MigrateDatabase,
+	m *Migration,
+	sql string,
+	statements []string,
+	versionSQL string,
+) error {
+	if !m.NoTransaction {
+		return db.WrapInTransaction(
+			ctx, func(ctx context.Context) error {
+				if err := execMigrationSQL(ctx, db, sql); err != nil {
+					return err
+				}
+
+				return gdh.Exec(ctx, db, versionSQL, m.Version)
+			},
+		)
+	}
+
+	for i, statement := range statements {
+		if err := gdh.Exec(ctx, db, statement); err != nil {
+			return errors.Wrapf(err, "statement %d of migration without transaction failed, it is partially applied", i+1)
+		}
+	}
+
+	return gdh.Exec(ctx, db, versionSQL, m.Version)
+}
+
+func (r *MigrateRunner) status(migrations []*Migration, applied []string) {
+	for _, m := range migrations {
+		state := "pending"
+		if slices.Contains(applied, m.Version) {
+			state = "applied"
+		}
+
+		log.Infof("    %-8s %s_%s", state, m.Version, m.Name)
+	}
+
+	for _, version := range applied {
+		if slices.ContainsFunc(migrations, func(m *Migration) bool { return m.Version == version }) {
+			continue
+		}
+
+		log.Warnf("    %-8s %s", "missing", version)
+	}
+}
+
+func (r *MigrateRunner) createMigrationsTable(ctx context.Context, db MigrateDatabase) error {
+	return gdh.Exec(
+		ctx,
+		db,
+		fmt.Sprintf(
+			`CREATE TABLE IF NOT EXISTS %s (version VARCHAR(255) NOT NULL, applied_at TIMESTAMP(0) WITHOUT TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL, PRIMARY KEY(version))`,
+			MigrationsTableName,
+		),
+	)
+}
+
+func (r *MigrateRunner) getAppliedVersions(ctx context.Context, db MigrateDatabase) ([]string, error) {
+	versions, err := gdh.SelectExecLitSlice[string](ctx, db, fmt.Sprintf("SELECT version FROM %s", MigrationsTableName))
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(versions, compareVersions)
+
+	return versions, nil
+}
+
+// execMigrationSQL - Executes migration sql, skipping files which contain only comments.
+func execMigrationSQL(ctx context.Context, db MigrateDatabase, sql string) error {
+	if !hasStatements(sql) {
+		return nil
+	}
+
+	return gdh.Exec(ctx, db, sql)
+}
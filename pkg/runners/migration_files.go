@@ -0,0 +1,217 @@
+package runners
+
+import (
+	"github.com/pkg/errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	migrateFileRegex = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+	gooseFileRegex   = regexp.MustCompile(`^(\d+)_(.+)\.sql$`)
+)
+
+const (
+	gooseUpAnnotation             = "-- +goose Up"
+	gooseDownAnnotation           = "-- +goose Down"
+	gooseStatementBeginAnnotation = "-- +goose StatementBegin"
+	gooseStatementEndAnnotation   = "-- +goose StatementEnd"
+	gooseNoTransactionAnnotation  = "-- +goose NO TRANSACTION"
+)
+
+// Migration - Single versioned migration read from the migrations directory.
+type Migration struct {
+	Version string
+	Name    string
+	Up      string
+	Down    string
+	// NoTransaction - Migration is applied statement by statement without transaction, it is set by the
+	// "-- +goose NO TRANSACTION" annotation, which is read from migrate files too
+	NoTransaction  bool
+	UpStatements   []string
+	DownStatements []string
+}
+
+// ReadMigrations - Reads migrate (`*.up.sql`/`*.down.sql`) and goose (`*.sql`) files
+// from dir and returns them ordered by version.
+func ReadMigrations(dir string) ([]*Migration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	migrations := make(map[string]*Migration)
+
+	getMigration := func(version, name string) *Migration {
+		if _, ok := migrations[version]; !ok {
+			migrations[version] = &Migration{Version: version, Name: name}
+		}
+		return migrations[version]
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		fileName := entry.Name()
+
+		content, err := os.ReadFile(filepath.Join(dir, fileName))
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		if match := migrateFileRegex.FindStringSubmatch(fileName); len(match) == 4 {
+			m := getMigration(match[1], match[2])
+
+			if match[3] == "up" {
+				m.Up = string(content)
+				m.UpStatements = splitStatements(strings.Split(m.Up, "\n"))
+			} else {
+				m.Down = string(content)
+				m.DownStatements = splitStatements(strings.Split(m.Down, "\n"))
+			}
+
+			m.NoTransaction = m.NoTransaction || hasNoTransactionAnnotation(string(content))
+
+			continue
+		}
+
+		if match := gooseFileRegex.FindStringSubmatch(fileName); len(match) == 3 {
+			if _, ok := migrations[match[1]]; ok {
+				return nil, errors.Errorf("duplicate migration version %s", match[1])
+			}
+
+			m := getMigration(match[1], match[2])
+			parseGooseMigration(m, string(content))
+		}
+	}
+
+	result := make([]*Migration, 0, len(migrations))
+	for _, m := range migrations {
+		result = append(result, m)
+	}
+
+	slices.SortFunc(result, func(a, b *Migration) int { return compareVersions(a.Version, b.Version) })
+
+	return result, nil
+}
+
+// parseGooseMigration - Splits goose migration content into up and down sql and their statements.
+func parseGooseMigration(m *Migration, content string) {
+	up := make([]string, 0)
+	down := make([]string, 0)
+
+	var current *[]string
+
+	for _, line := range strings.Split(content, "\n") {
+		switch strings.TrimSpace(line) {
+		case gooseUpAnnotation:
+			current = &up
+			continue
+		case gooseDownAnnotation:
+			current = &down
+			continue
+		case gooseNoTransactionAnnotation:
+			m.NoTransaction = true
+			continue
+		}
+
+		if current != nil {
+			*current = append(*current, line)
+		}
+	}
+
+	m.Up, m.UpStatements = joinGooseSection(up)
+	m.Down, m.DownStatements = joinGooseSection(down)
+}
+
+// joinGooseSection - Returns sql of the section without statement annotations and its statements.
+func joinGooseSection(lines []string) (string, []string) {
+	sql := slices.DeleteFunc(
+		slices.Clone(lines), func(line string) bool {
+			line = strings.TrimSpace(line)
+			return line == gooseStatementBeginAnnotation || line == gooseStatementEndAnnotation
+		},
+	)
+
+	return strings.TrimSpace(strings.Join(sql, "\n")), splitStatements(lines)
+}
+
+// splitStatements - Splits sql into statements like goose does, a statement ends with the line ending with ";" or
+// with "-- +goose StatementEnd" when it is between StatementBegin and StatementEnd. Comments only statements are
+// skipped.
+func splitStatements(lines []string) []string {
+	statements := make([]string, 0)
+	statement := make([]string, 0)
+	inBlock := false
+
+	flush := func() {
+		if sql := strings.TrimSpace(strings.Join(statement, "\n")); hasStatements(sql) {
+			statements = append(statements, sql)
+		}
+		statement = statement[:0]
+	}
+
+	for _, line := range lines {
+		switch strings.TrimSpace(line) {
+		case gooseStatementBeginAnnotation:
+			flush()
+			inBlock = true
+			continue
+		case gooseStatementEndAnnotation:
+			flush()
+			inBlock = false
+			continue
+		case gooseNoTransactionAnnotation:
+			continue
+		}
+
+		statement = append(statement, line)
+
+		if !inBlock && strings.HasSuffix(strings.TrimSpace(line), ";") {
+			flush()
+		}
+	}
+
+	flush()
+
+	return statements
+}
+
+// hasStatements - Returns whether sql has lines other than comments.
+func hasStatements(sql string) bool {
+	for _, line := range strings.Split(sql, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "--") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasNoTransactionAnnotation(content string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) == gooseNoTransactionAnnotation {
+			return true
+		}
+	}
+
+	return false
+}
+
+// compareVersions - Compares numeric versions of different length without overflow.
+func compareVersions(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+
+	if len(a) != len(b) {
+		return len(a) - len(b)
+	}
+
+	return strings.Compare(a, b)
+}
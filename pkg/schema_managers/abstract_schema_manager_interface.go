@@ -9,6 +9,7 @@ type AbstractSchemaManagerInterface interface {
 	IntrospectSchema() *assets.Schema
 	ListTables() []*assets.Table
 	ListSequences() []*assets.Sequence
+	ListEnumTypes() []*assets.EnumType
 	CreateSchemaConfig() *dtos.SchemaConfig
 	ListSchemaNames() []string
 
@@ -17,6 +18,7 @@ type AbstractSchemaManagerInterface interface {
 	GetPortableTableColumnDefinition(tableColumn *dtos.SelectTableColumnsDto) *assets.Column
 	GetPortableViewDefinition(view map[string]any) *assets.View
 	GetPortableTableForeignKeyDefinition(tableForeignKey *dtos.SelectForeignKeyColumnsDto) *assets.ForeignKeyConstraint
+	GetPortableTableCheckConstraintDefinition(tableCheck *dtos.SelectCheckConstraintsDto) *assets.CheckConstraint
 
 	GetPortableTableDefinition(table dtos.GetPortableTableDefinitionInputDto) string
 
@@ -33,6 +35,10 @@ type AbstractSchemaManagerInterface interface {
 		databaseName string,
 		tableName *string,
 	) []*dtos.SelectForeignKeyColumnsDto
+	SelectCheckConstraints(
+		databaseName string,
+		tableName *string,
+	) []*dtos.SelectCheckConstraintsDto
 
 	FetchTableOptionsByTable(
 		databaseName string,
@@ -58,6 +58,10 @@ func (m *AbstractSchemaManager) ListSequences() []*assets.Sequence {
 	)
 }
 
+func (m *AbstractSchemaManager) ListEnumTypes() []*assets.EnumType {
+	panic("Not supported")
+}
+
 func (m *AbstractSchemaManager) ListTableColumns(table string) map[string]*assets.Column {
 	database := m.getDatabase()
 
@@ -113,33 +117,36 @@ func (m *AbstractSchemaManager) ListTables() []*assets.Table {
 	tableColumnsByTable := m.fetchTableColumnsByTable(database)
 	indexColumnsByTable := m.fetchIndexColumnsByTable(database)
 	foreignKeyColumnsByTable := m.fetchForeignKeyColumnsByTable(database)
+	checkConstraintsByTable := m.fetchCheckConstraintsByTable(database)
 	tableOptionsByTable := m.Child.FetchTableOptionsByTable(database, nil)
 
 	tables := make([]*assets.Table, 0)
 	for tableName, tableColumns := range tableColumnsByTable {
 		options := tableOptionsByTable[tableName]
 
-		tables = append(
-			tables, assets.NewTable(
-				tableName,
-				maps.Values(
-					m.GetPortableTableColumnList(
-						tableName,
-						database,
-						tableColumns,
-					),
+		table := assets.NewTable(
+			tableName,
+			maps.Values(
+				m.GetPortableTableColumnList(
+					tableName,
+					database,
+					tableColumns,
 				),
-				maps.Values(
-					m.Child.GetPortableTableIndexesList(
-						indexColumnsByTable[tableName],
-						tableName,
-					),
+			),
+			maps.Values(
+				m.Child.GetPortableTableIndexesList(
+					indexColumnsByTable[tableName],
+					tableName,
 				),
-				make([]*assets.UniqueConstraint, 0),
-				m.getPortableTableForeignKeysList(foreignKeyColumnsByTable[tableName]),
-				options.ToArray(),
 			),
+			make([]*assets.UniqueConstraint, 0),
+			m.getPortableTableForeignKeysList(foreignKeyColumnsByTable[tableName]),
+			options.ToArray(),
 		)
+
+		m.addPortableTableCheckConstraints(table, checkConstraintsByTable[tableName])
+
+		tables = append(tables, table)
 	}
 
 	return tables
@@ -169,6 +176,12 @@ func (m *AbstractSchemaManager) fetchForeignKeyColumnsByTable(databaseName strin
 	return fetchAllAssociativeGrouped(m, data)
 }
 
+func (m *AbstractSchemaManager) fetchCheckConstraintsByTable(databaseName string) map[string][]*dtos.SelectCheckConstraintsDto {
+	data := m.Child.SelectCheckConstraints(databaseName, nil)
+
+	return fetchAllAssociativeGrouped(m, data)
+}
+
 func (m *AbstractSchemaManager) IntrospectTable(name string) *assets.Table {
 	columns := m.ListTableColumns(name)
 
@@ -176,7 +189,7 @@ func (m *AbstractSchemaManager) IntrospectTable(name string) *assets.Table {
 		panic("table " + name + " not found")
 	}
 
-	return assets.NewTable(
+	table := assets.NewTable(
 		name,
 		maps.Values(columns),
 		maps.Values(m.ListTableIndexes(name)),
@@ -184,6 +197,10 @@ func (m *AbstractSchemaManager) IntrospectTable(name string) *assets.Table {
 		m.ListTableForeignKeys(name),
 		m.getTableOptions(name).ToArray(),
 	)
+
+	m.addPortableTableCheckConstraints(table, m.Child.SelectCheckConstraints(m.getDatabase(), &name))
+
+	return table
 }
 
 func (m *AbstractSchemaManager) ListViews() []*assets.View {
@@ -215,8 +232,12 @@ func (m *AbstractSchemaManager) getTableOptions(table string) *dtos.FetchTableOp
 }
 
 type getPortableTableIndexesListOptionsSubDto struct {
-	lengths []*int
-	where   string
+	lengths   []*int
+	where     string
+	using     string
+	include   []string
+	orders    map[string]string
+	opclasses map[string]string
 }
 
 func (g *getPortableTableIndexesListOptionsSubDto) asMap() map[string]any {
@@ -230,6 +251,22 @@ func (g *getPortableTableIndexesListOptionsSubDto) asMap() map[string]any {
 		res["where"] = g.where
 	}
 
+	if g.using != "" && g.using != "btree" {
+		res["using"] = g.using
+	}
+
+	if len(g.include) > 0 {
+		res["include"] = g.include
+	}
+
+	if len(g.orders) > 0 {
+		res["orders"] = g.orders
+	}
+
+	if len(g.opclasses) > 0 {
+		res["opclasses"] = g.opclasses
+	}
+
 	return res
 }
 
@@ -276,6 +313,9 @@ func (m *AbstractSchemaManager) GetPortableTableIndexesList(
 			if tableIndex.Where != nil {
 				options.where = *tableIndex.Where
 			}
+			options.using = tableIndex.Using
+			options.orders = make(map[string]string)
+			options.opclasses = make(map[string]string)
 
 			result[keyName] = &getPortableTableIndexesListDto{
 				name:    indexName,
@@ -287,7 +327,19 @@ func (m *AbstractSchemaManager) GetPortableTableIndexesList(
 			}
 		}
 
+		if tableIndex.Included {
+			result[keyName].options.include = append(result[keyName].options.include, tableIndex.ColumnName)
+			continue
+		}
+
 		result[keyName].addColumn(tableIndex.ColumnName)
+
+		if tableIndex.Order != "" {
+			result[keyName].options.orders[tableIndex.ColumnName] = tableIndex.Order
+		}
+		if tableIndex.Opclass != "" {
+			result[keyName].options.opclasses[tableIndex.ColumnName] = tableIndex.Opclass
+		}
 		//result[keyName].addOptionsLength(nil) // tableIndex.Length
 	}
 
@@ -317,6 +369,17 @@ func (m *AbstractSchemaManager) getPortableTableForeignKeysList(tableForeignKeys
 	return list
 }
 
+func (m *AbstractSchemaManager) addPortableTableCheckConstraints(
+	table *assets.Table,
+	tableChecks []*dtos.SelectCheckConstraintsDto,
+) {
+	for _, value := range tableChecks {
+		check := m.Child.GetPortableTableCheckConstraintDefinition(value)
+
+		table.AddCheckConstraint(check.GetName(), check.GetExpression())
+	}
+}
+
 func (m *AbstractSchemaManager) IntrospectSchema() *assets.Schema {
 	s := m.Child
 
@@ -332,25 +395,36 @@ func (m *AbstractSchemaManager) IntrospectSchema() *assets.Schema {
 		sequences = s.ListSequences()
 	}
 
-	tables := s.ListTables()
+	enumTypes := make([]*assets.EnumType, 0)
 
-	// Remove schema_migrations
-	for i, table := range tables {
-		if slices.Contains(
-			[]string{"schema_migrations", "goose_db_version"},
-			table.GetName(),
-		) {
-			tables = slices.Delete(tables, i, i+1)
-			break
-		}
+	if m.Platform.SupportsEnumTypes() {
+		enumTypes = s.ListEnumTypes()
 	}
 
-	return assets.NewSchema(
+	tables := s.ListTables()
+
+	// Remove migration tools tracking tables
+	tables = slices.DeleteFunc(
+		tables, func(table *assets.Table) bool {
+			return slices.Contains(
+				[]string{"schema_migrations", "goose_db_version", "gormite_migrations"},
+				table.GetName(),
+			)
+		},
+	)
+
+	schema := assets.NewSchema(
 		tables,
 		sequences,
 		s.CreateSchemaConfig(),
 		schemaNames,
 	)
+
+	for _, enumType := range enumTypes {
+		schema.CreateEnumType(enumType.GetName(), enumType.GetValues())
+	}
+
+	return schema
 }
 
 func (m *AbstractSchemaManager) CreateSchemaConfig() *dtos.SchemaConfig {
@@ -1,6 +1,7 @@
 package postgres_schema_manager
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/KoNekoD/gormite/pkg/assets"
 	"github.com/KoNekoD/gormite/pkg/dtos"
@@ -9,6 +10,7 @@ import (
 	"github.com/KoNekoD/gormite/pkg/types"
 	"github.com/KoNekoD/ptrs/pkg/ptrs"
 	"github.com/KoNekoD/smt/pkg/smt"
+	"github.com/pkg/errors"
 	"regexp"
 	"slices"
 	"strconv"
@@ -59,6 +61,49 @@ AND    schema_name != 'information_schema'
 	)
 }
 
+// ListEnumTypes - Returns enum types with their values in the sort order, enum types of the current schema are not
+// qualified.
+func (m *PostgreSQLSchemaManager) ListEnumTypes() []*assets.EnumType {
+	rows := platforms.Fetch(
+		m.Connection,
+		`
+SELECT n.nspname AS schema_name, t.typname AS type_name, e.enumlabel AS label
+FROM   pg_catalog.pg_type t
+JOIN   pg_catalog.pg_enum e ON e.enumtypid = t.oid
+JOIN   pg_catalog.pg_namespace n ON n.oid = t.typnamespace
+WHERE  n.nspname NOT LIKE 'pg\_%'
+AND    n.nspname != 'information_schema'
+ORDER BY n.nspname, t.typname, e.enumsortorder
+`,
+		make([]dtos.SelectEnumTypeValuesDto, 0),
+	)
+
+	currentSchema := m.getCurrentSchema()
+
+	enumTypes := make([]*assets.EnumType, 0)
+	names := make([]string, 0)
+	values := make(map[string][]string)
+
+	for _, row := range rows {
+		name := row.TypeName
+		if currentSchema == nil || row.SchemaName != *currentSchema {
+			name = row.SchemaName + "." + row.TypeName
+		}
+
+		if _, ok := values[name]; !ok {
+			names = append(names, name)
+		}
+
+		values[name] = append(values[name], row.Label)
+	}
+
+	for _, name := range names {
+		enumTypes = append(enumTypes, assets.NewEnumType(name, values[name]))
+	}
+
+	return enumTypes
+}
+
 func (m *PostgreSQLSchemaManager) CreateSchemaConfig() *dtos.SchemaConfig {
 	config := m.AbstractSchemaManager.CreateSchemaConfig()
 
@@ -140,13 +185,26 @@ func (m *PostgreSQLSchemaManager) GetPortableTableForeignKeyDefinition(tableFore
 	panic(fmt.Errorf("invalid foreign key definition"))
 }
 
+// GetPortableTableCheckConstraintDefinition - Definition is returned as CHECK (expression) [NOT VALID], only the
+// expression is kept.
+func (m *PostgreSQLSchemaManager) GetPortableTableCheckConstraintDefinition(tableCheck *dtos.SelectCheckConstraintsDto) *assets.CheckConstraint {
+	checkRegex := regexp.MustCompile(`(?s)^CHECK \((.+)\)( NOT VALID)?$`)
+
+	match := checkRegex.FindStringSubmatch(tableCheck.Condef)
+	if len(match) == 0 {
+		panic(fmt.Errorf("invalid check constraint definition %s", tableCheck.Condef))
+	}
+
+	return assets.NewCheckConstraint(tableCheck.Conname, match[1])
+}
+
 func (m *PostgreSQLSchemaManager) GetPortableDatabaseDefinition(row map[string]any) string {
 	return row["datname"].(string)
 }
 
 func (m *PostgreSQLSchemaManager) GetPortableSequenceDefinition(sequence *dtos.ListSequencesDto) *assets.Sequence {
 	sequenceName := ""
-	if sequence.Schemaname != "public" {
+	if currentSchema := m.getCurrentSchema(); currentSchema == nil || sequence.Schemaname != *currentSchema {
 		sequenceName = sequence.Schemaname + "." + sequence.Relname
 	} else {
 		sequenceName = sequence.Relname
@@ -162,7 +220,7 @@ func (m *PostgreSQLSchemaManager) GetPortableSequenceDefinition(sequence *dtos.L
 func (m *PostgreSQLSchemaManager) GetPortableTableColumnDefinition(tableColumn *dtos.SelectTableColumnsDto) *assets.Column {
 	var length *int
 
-	if slices.Contains([]string{"varchar", "bpchar"}, tableColumn.Type) {
+	if slices.Contains([]string{"varchar", "bpchar", "_varchar", "_bpchar"}, tableColumn.Type) {
 		matches := regexp.MustCompile(`\((\d*)\)`).FindStringSubmatch(tableColumn.CompleteType)
 		if len(matches) == 2 {
 			lenInt, _ := strconv.Atoi(matches[1])
@@ -170,7 +228,14 @@ func (m *PostgreSQLSchemaManager) GetPortableTableColumnDefinition(tableColumn *
 		}
 	}
 
-	autoincrement := tableColumn.Attidentity == 'd'
+	// Identity is compared as it is set by the id strategy, GENERATED ALWAYS is not distinguished
+	autoincrement := tableColumn.Attidentity == 'd' || tableColumn.Attidentity == 'a'
+
+	// Expression of the generated column is returned as its default
+	var generated *string
+	if tableColumn.IsGenerated {
+		generated, tableColumn.Default = tableColumn.Default, nil
+	}
 
 	matches := make([]string, 0)
 
@@ -206,7 +271,27 @@ func (m *PostgreSQLSchemaManager) GetPortableTableColumnDefinition(tableColumn *
 		tableColumn.CompleteType = *tableColumn.DomainCompleteType
 	}
 
-	typeMapping := m.Platform.GetDoctrineTypeMapping(dbType)
+	// Array types are named by their element types with underscore prefix, e.g. _int8 is bigint[]
+	isArray := strings.HasPrefix(dbType, "_") && m.Platform.HasDoctrineTypeMappingFor(dbType[1:])
+	if isArray {
+		dbType = dbType[1:]
+	}
+
+	var columnType types.AbstractTypeInterface
+	if tableColumn.IsEnum {
+		// Enum types are named by format_type, it qualifies types which are not in the search path
+		enumTypeName, isEnumArray := strings.CutSuffix(tableColumn.CompleteType, "[]")
+
+		columnType = types.NewEnumType(enumTypeName)
+		if isEnumArray {
+			columnType = types.NewArrayType(columnType)
+		}
+	} else {
+		columnType = types.GetType(m.Platform.GetDoctrineTypeMapping(dbType))
+		if isArray {
+			columnType = types.NewArrayType(columnType)
+		}
+	}
 
 	switch dbType {
 	case "smallint", "int2", "int", "int4", "integer", "bigint", "int8":
@@ -220,7 +305,7 @@ func (m *PostgreSQLSchemaManager) GetPortableTableColumnDefinition(tableColumn *
 			tableColumn.Default = ptrs.AsPtr(`false`)
 		}
 		length = nil
-	case "json", "text", "_varchar", "varchar":
+	case "json", "text", "varchar":
 		tableColumn.Default = m.parseDefaultExpression(tableColumn.Default)
 	case "char", "bpchar":
 		fixed = true
@@ -241,7 +326,8 @@ func (m *PostgreSQLSchemaManager) GetPortableTableColumnDefinition(tableColumn *
 		jsonb = ptrs.AsPtr(true)
 	}
 
-	if tableColumn.Default != nil {
+	// nextval('table__id__seq'::regclass) of the serial id is kept as is
+	if tableColumn.Default != nil && !strings.HasPrefix(*tableColumn.Default, "nextval(") {
 		re := regexp.MustCompile(`'([^']+)'::`)
 		if matches = re.FindStringSubmatch(*tableColumn.Default); len(matches) == 2 {
 			tableColumn.Default = ptrs.AsPtr(matches[1])
@@ -274,6 +360,10 @@ func (m *PostgreSQLSchemaManager) GetPortableTableColumnDefinition(tableColumn *
 		options = append(options, assets.WithColumnAutoIncrement())
 	}
 
+	if generated != nil {
+		options = append(options, assets.WithColumnGenerated(*generated))
+	}
+
 	if tableColumn.Comment != nil {
 		options = append(
 			options,
@@ -281,11 +371,7 @@ func (m *PostgreSQLSchemaManager) GetPortableTableColumnDefinition(tableColumn *
 		)
 	}
 
-	column := assets.NewColumn(
-		tableColumn.Field,
-		types.GetType(typeMapping),
-		options...,
-	)
+	column := assets.NewColumn(tableColumn.Field, columnType, options...)
 
 	if tableColumn.Collation != nil {
 		column.SetPlatformOption("collation", *tableColumn.Collation)
@@ -305,6 +391,22 @@ func (m *PostgreSQLSchemaManager) GetPortableViewDefinition(view map[string]any)
 	)
 }
 
+// indexColumnDto - Element of SelectIndexColumnsDto.IndexColumns.
+type indexColumnDto struct {
+	// Definition - Column name or expression of the index column
+	Definition string `json:"definition"`
+	// Option - Bits of pg_index.indoption, they are set for key columns only
+	Option *int `json:"option"`
+	// Opclass - Operator class of the column, it is set when the class is not the default one
+	Opclass  *string `json:"opclass"`
+	Included bool    `json:"included"`
+}
+
+const (
+	indexOptionDesc       = 1
+	indexOptionNullsFirst = 2
+)
+
 func (m *PostgreSQLSchemaManager) GetPortableTableIndexesList(
 	tableIndexes []*dtos.SelectIndexColumnsDto,
 	tableName string,
@@ -312,35 +414,33 @@ func (m *PostgreSQLSchemaManager) GetPortableTableIndexesList(
 	buffer := make([]*dtos.PortableTableIndexesDto, 0)
 
 	for _, row := range tableIndexes {
-		colNumbers := strings.Split(row.Indkey, " ")
+		indexColumns := make([]*indexColumnDto, 0)
+		if row.IndexColumns != nil {
+			if err := json.Unmarshal([]byte(*row.IndexColumns), &indexColumns); err != nil {
+				panic(errors.Wrapf(err, "failed to decode columns of index %s", row.RelName))
+			}
+		}
 
-		columnNameSql := fmt.Sprintf(
-			"SELECT attnum, attname FROM pg_attribute WHERE attrelid=%s AND attnum IN (%s) ORDER BY attnum ASC",
-			*row.Indrelid,
-			strings.Join(colNumbers, " ,"),
-		)
+		for _, indexColumn := range indexColumns {
+			index := &dtos.PortableTableIndexesDto{
+				KeyName:    row.RelName,
+				ColumnName: strings.TrimSpace(indexColumn.Definition),
+				NonUnique:  !row.IndisUnique,
+				Primary:    row.IndisPrimary,
+				Where:      row.Where,
+				Using:      row.Using,
+				Included:   indexColumn.Included,
+			}
 
-		indexColumns := platforms.Fetch(
-			m.Connection,
-			columnNameSql,
-			make([]dtos.GetColNameDto, 0),
-		)
-		for _, colNum := range colNumbers {
-			for _, colRow := range indexColumns {
-				if colNum != colRow.Attnum {
-					continue
-				}
-
-				buffer = append(
-					buffer, &dtos.PortableTableIndexesDto{
-						KeyName:    row.RelName,
-						ColumnName: strings.TrimSpace(colRow.Attname),
-						NonUnique:  !row.IndisUnique,
-						Primary:    row.IndisPrimary,
-						Where:      row.Where,
-					},
-				)
+			if indexColumn.Opclass != nil {
+				index.Opclass = *indexColumn.Opclass
+			}
+
+			if indexColumn.Option != nil {
+				index.Order = getIndexColumnOrder(*indexColumn.Option)
 			}
+
+			buffer = append(buffer, index)
 		}
 	}
 
@@ -350,6 +450,27 @@ func (m *PostgreSQLSchemaManager) GetPortableTableIndexesList(
 	)
 }
 
+// getIndexColumnOrder - Returns ordering of the index column by pg_index.indoption bits, see assets.NormalizeIndexOrder.
+func getIndexColumnOrder(option int) string {
+	desc := option&indexOptionDesc != 0
+	nullsFirst := option&indexOptionNullsFirst != 0
+
+	order := "ASC"
+	if desc {
+		order = "DESC"
+	}
+
+	if nullsFirst {
+		order += " NULLS FIRST"
+	} else {
+		order += " NULLS LAST"
+	}
+
+	order, _ = assets.NormalizeIndexOrder(order)
+
+	return order
+}
+
 func (m *PostgreSQLSchemaManager) GetPortableTableDefinition(table dtos.GetPortableTableDefinitionInputDto) string {
 	currentSchema := m.getCurrentSchema()
 
@@ -375,6 +496,9 @@ func (m *PostgreSQLSchemaManager) SelectTableColumns(
 	           a.attnum,
 	           quote_ident(a.attname) AS field,
 	           t.typname AS type,
+	           COALESCE(t.typtype = 'e' OR (
+	               SELECT et.typtype = 'e' FROM pg_catalog.pg_type et WHERE et.oid = t.typelem AND t.typcategory = 'A'
+	           ), false) AS is_enum,
 	           format_type(a.atttypid, a.atttypmod) AS complete_type,
 	           (SELECT tc.collcollate FROM pg_catalog.pg_collation tc WHERE tc.oid = a.attcollation) AS collation,
 	           (SELECT t1.typname FROM pg_catalog.pg_type t1 WHERE t1.oid = t.typbasetype) AS domain_type,
@@ -382,6 +506,7 @@ func (m *PostgreSQLSchemaManager) SelectTableColumns(
 	             pg_catalog.pg_type t2 WHERE t2.typtype = 'd' AND t2.oid = a.atttypid) AS domain_complete_type,
 	           a.attnotnull AS isnotnull,
 	           a.attidentity,
+	           a.attgenerated = 's' AS is_generated,
 	           (SELECT 't'
 	            FROM pg_index
 	            WHERE c.oid = pg_index.indrelid
@@ -438,11 +563,28 @@ func (m *PostgreSQLSchemaManager) SelectIndexColumns(
 		i.indisprimary,
 		i.indkey,
 		i.indrelid,
-		pg_get_expr(indpred, indrelid) AS "where"
+		pg_get_expr(indpred, indrelid) AS "where",
+		am.amname AS "using",
+		(
+			SELECT json_agg(
+				json_build_object(
+					'definition', pg_get_indexdef(i.indexrelid, k, true),
+					'option', i.indoption[k - 1],
+					'opclass', (
+						SELECT opc.opcname
+						FROM pg_opclass opc
+						WHERE opc.oid = i.indclass[k - 1] AND NOT opc.opcdefault
+					),
+					'included', k > i.indnkeyatts
+				) ORDER BY k
+			)::text
+			FROM generate_series(1, i.indnatts) AS k
+		) AS index_columns
 	FROM pg_index i
 	JOIN pg_class AS tc ON tc.oid = i.indrelid
 	JOIN pg_namespace tn ON tn.oid = tc.relnamespace
 	JOIN pg_class AS ic ON ic.oid = i.indexrelid
+	JOIN pg_am AS am ON am.oid = ic.relam
 	WHERE ic.oid IN (
 		SELECT indexrelid
 	FROM pg_index i, pg_class c, pg_namespace n
@@ -526,12 +668,53 @@ func (m *PostgreSQLSchemaManager) SelectForeignKeyColumns(
 	)
 }
 
+func (m *PostgreSQLSchemaManager) SelectCheckConstraints(
+	databaseName string,
+	tableName *string,
+) []*dtos.SelectCheckConstraintsDto {
+	sql := "SELECT"
+
+	if tableName == nil {
+		sql += " tc.relname AS table_name, tn.nspname AS schema_name,"
+	}
+
+	sql += `
+	quote_ident(r.conname) as conname,
+		pg_get_constraintdef(r.oid, true) as condef
+	FROM pg_constraint r
+	JOIN pg_class AS tc ON tc.oid = r.conrelid
+	JOIN pg_namespace tn ON tn.oid = tc.relnamespace
+	WHERE r.conrelid IN
+	(
+		SELECT c.oid
+	FROM pg_class c, pg_namespace n
+	`
+
+	conditions := make([]string, 0)
+	conditions = append(conditions, "n.oid = c.relnamespace")
+	conditions = append(conditions, m.buildQueryConditions(tableName)...)
+
+	sql += " WHERE " + strings.Join(
+		conditions,
+		" AND ",
+	) + ") AND r.contype = 'c'"
+
+	return smt.MapSlice(
+		platforms.Fetch(
+			m.Connection,
+			sql,
+			make([]dtos.SelectCheckConstraintsDto, 0),
+		), ptrs.AsPtr,
+	)
+}
+
 func (m *PostgreSQLSchemaManager) FetchTableOptionsByTable(
 	databaseName string,
 	tableName *string,
 ) map[string]*dtos.FetchTableOptionsByTableDto {
 	sql := `
 	SELECT c.relname,
+		n.nspname AS schema_name,
 		CASE c.relpersistence WHEN 'u' THEN true ELSE false END as unlogged,
 		obj_description(c.oid, 'pg_class') AS comment
 	FROM pg_class c
@@ -554,10 +737,12 @@ func (m *PostgreSQLSchemaManager) FetchTableOptionsByTable(
 			make([]dtos.FetchTableOptionsByTableDto, 0),
 		),
 		func(t dtos.FetchTableOptionsByTableDto) *dtos.FetchTableOptionsByTableDto {
-			if result[t.Relname] != nil {
-				panic(fmt.Sprintf("duplicate table name: %s", t.Relname))
+			// Keys are the same as in ListTables, tables outside the current schema are qualified
+			tableName := m.GetPortableTableDefinition(&t)
+			if result[tableName] != nil {
+				panic(fmt.Sprintf("duplicate table name: %s", tableName))
 			}
-			result[t.Relname] = &t
+			result[tableName] = &t
 			return &t
 		},
 	)
@@ -0,0 +1,503 @@
+package schema_snapshots
+
+import (
+	"fmt"
+	"github.com/KoNekoD/gormite/pkg/assets"
+	"github.com/KoNekoD/gormite/pkg/dtos"
+	"github.com/KoNekoD/gormite/pkg/enums"
+	"github.com/KoNekoD/gormite/pkg/types"
+	"github.com/pkg/errors"
+	"math"
+	"slices"
+	"strings"
+)
+
+// FormatVersion - Current version of the snapshot format, bumped on every incompatible change.
+const FormatVersion = 1
+
+// SchemaSnapshot - Serializable representation of assets.Schema.
+type SchemaSnapshot struct {
+	Version    int                 `json:"version" yaml:"version"`
+	Name       *string             `json:"name,omitempty" yaml:"name,omitempty"`
+	Namespaces []string            `json:"namespaces" yaml:"namespaces"`
+	Tables     []*TableSnapshot    `json:"tables" yaml:"tables"`
+	Sequences  []*SequenceSnapshot `json:"sequences" yaml:"sequences"`
+	EnumTypes  []*EnumTypeSnapshot `json:"enum_types,omitempty" yaml:"enum_types,omitempty"`
+	Views      []*ViewSnapshot     `json:"views" yaml:"views"`
+}
+
+type TableSnapshot struct {
+	Name              string                      `json:"name" yaml:"name"`
+	Comment           *string                     `json:"comment,omitempty" yaml:"comment,omitempty"`
+	Options           map[string]any              `json:"options,omitempty" yaml:"options,omitempty"`
+	Columns           []*ColumnSnapshot           `json:"columns" yaml:"columns"`
+	Indexes           []*IndexSnapshot            `json:"indexes" yaml:"indexes"`
+	UniqueConstraints []*UniqueConstraintSnapshot `json:"unique_constraints,omitempty" yaml:"unique_constraints,omitempty"`
+	ForeignKeys       []*ForeignKeySnapshot       `json:"foreign_keys" yaml:"foreign_keys"`
+	CheckConstraints  []*CheckConstraintSnapshot  `json:"check_constraints,omitempty" yaml:"check_constraints,omitempty"`
+}
+
+type ColumnSnapshot struct {
+	Name             string          `json:"name" yaml:"name"`
+	Type             enums.TypesType `json:"type" yaml:"type"`
+	Length           *int            `json:"length,omitempty" yaml:"length,omitempty"`
+	Precision        *int            `json:"precision,omitempty" yaml:"precision,omitempty"`
+	Scale            *int            `json:"scale,omitempty" yaml:"scale,omitempty"`
+	Unsigned         bool            `json:"unsigned,omitempty" yaml:"unsigned,omitempty"`
+	Fixed            bool            `json:"fixed,omitempty" yaml:"fixed,omitempty"`
+	NotNull          bool            `json:"notnull,omitempty" yaml:"notnull,omitempty"`
+	Default          *string         `json:"default,omitempty" yaml:"default,omitempty"`
+	Autoincrement    bool            `json:"autoincrement,omitempty" yaml:"autoincrement,omitempty"`
+	Comment          string          `json:"comment,omitempty" yaml:"comment,omitempty"`
+	ColumnDefinition *string         `json:"column_definition,omitempty" yaml:"column_definition,omitempty"`
+	Generated        *string         `json:"generated,omitempty" yaml:"generated,omitempty"`
+	PlatformOptions  map[string]any  `json:"platform_options,omitempty" yaml:"platform_options,omitempty"`
+}
+
+type IndexSnapshot struct {
+	Name    string         `json:"name" yaml:"name"`
+	Columns []string       `json:"columns" yaml:"columns"`
+	Unique  bool           `json:"unique,omitempty" yaml:"unique,omitempty"`
+	Primary bool           `json:"primary,omitempty" yaml:"primary,omitempty"`
+	Flags   []string       `json:"flags,omitempty" yaml:"flags,omitempty"`
+	Options map[string]any `json:"options,omitempty" yaml:"options,omitempty"`
+}
+
+type UniqueConstraintSnapshot struct {
+	Name    string            `json:"name" yaml:"name"`
+	Columns []string          `json:"columns" yaml:"columns"`
+	Flags   []string          `json:"flags,omitempty" yaml:"flags,omitempty"`
+	Options map[string]string `json:"options,omitempty" yaml:"options,omitempty"`
+}
+
+type ForeignKeySnapshot struct {
+	Name           string   `json:"name" yaml:"name"`
+	LocalColumns   []string `json:"local_columns" yaml:"local_columns"`
+	ForeignTable   string   `json:"foreign_table" yaml:"foreign_table"`
+	ForeignColumns []string `json:"foreign_columns" yaml:"foreign_columns"`
+	OnUpdate       *string  `json:"on_update,omitempty" yaml:"on_update,omitempty"`
+	OnDelete       *string  `json:"on_delete,omitempty" yaml:"on_delete,omitempty"`
+}
+
+type CheckConstraintSnapshot struct {
+	Name       string `json:"name" yaml:"name"`
+	Expression string `json:"expression" yaml:"expression"`
+}
+
+type SequenceSnapshot struct {
+	Name           string `json:"name" yaml:"name"`
+	AllocationSize int    `json:"allocation_size" yaml:"allocation_size"`
+	InitialValue   int    `json:"initial_value" yaml:"initial_value"`
+	Cache          *int   `json:"cache,omitempty" yaml:"cache,omitempty"`
+}
+
+type EnumTypeSnapshot struct {
+	Name   string   `json:"name" yaml:"name"`
+	Values []string `json:"values" yaml:"values"`
+}
+
+type ViewSnapshot struct {
+	Name string `json:"name" yaml:"name"`
+	SQL  string `json:"sql" yaml:"sql"`
+}
+
+// NewSchemaSnapshot - Creates snapshot of the schema, all collections are sorted to keep output stable.
+func NewSchemaSnapshot(schema *assets.Schema) (*SchemaSnapshot, error) {
+	s := &SchemaSnapshot{
+		Version:    FormatVersion,
+		Namespaces: make([]string, 0),
+		Tables:     make([]*TableSnapshot, 0),
+		Sequences:  make([]*SequenceSnapshot, 0),
+		Views:      make([]*ViewSnapshot, 0),
+	}
+
+	if schema.GetName() != "" {
+		name := schema.GetName()
+		s.Name = &name
+	}
+
+	s.Namespaces = append(s.Namespaces, schema.GetNamespaces()...)
+	slices.Sort(s.Namespaces)
+
+	for _, table := range schema.GetTables() {
+		tableSnapshot, err := newTableSnapshot(table)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		s.Tables = append(s.Tables, tableSnapshot)
+	}
+	slices.SortFunc(s.Tables, func(a, b *TableSnapshot) int { return strings.Compare(a.Name, b.Name) })
+
+	for _, sequence := range schema.GetSequences() {
+		s.Sequences = append(
+			s.Sequences, &SequenceSnapshot{
+				Name:           sequence.GetName(),
+				AllocationSize: sequence.GetAllocationSize(),
+				InitialValue:   sequence.GetInitialValue(),
+				Cache:          sequence.GetCache(),
+			},
+		)
+	}
+	slices.SortFunc(s.Sequences, func(a, b *SequenceSnapshot) int { return strings.Compare(a.Name, b.Name) })
+
+	for _, enumType := range schema.GetEnumTypes() {
+		s.EnumTypes = append(s.EnumTypes, &EnumTypeSnapshot{Name: enumType.GetName(), Values: enumType.GetValues()})
+	}
+	slices.SortFunc(s.EnumTypes, func(a, b *EnumTypeSnapshot) int { return strings.Compare(a.Name, b.Name) })
+
+	for _, view := range schema.GetViews() {
+		s.Views = append(s.Views, &ViewSnapshot{Name: view.GetName(), SQL: view.GetSQL()})
+	}
+	slices.SortFunc(s.Views, func(a, b *ViewSnapshot) int { return strings.Compare(a.Name, b.Name) })
+
+	return s, nil
+}
+
+func newTableSnapshot(table *assets.Table) (*TableSnapshot, error) {
+	t := &TableSnapshot{
+		Name:        table.GetName(),
+		Columns:     make([]*ColumnSnapshot, 0),
+		Indexes:     make([]*IndexSnapshot, 0),
+		ForeignKeys: make([]*ForeignKeySnapshot, 0),
+	}
+
+	for name, value := range table.GetOptions() {
+		// Comment is stored as pointer, so it has own field
+		if name == "comment" {
+			if comment, ok := value.(*string); ok && comment != nil {
+				t.Comment = comment
+			}
+			continue
+		}
+
+		if t.Options == nil {
+			t.Options = make(map[string]any)
+		}
+		t.Options[name] = value
+	}
+
+	for _, column := range table.GetColumns() {
+		typeName, err := getTypeName(column.GetColumnType())
+		if err != nil {
+			return nil, errors.Wrapf(err, "column %s of table %s", column.GetName(), table.GetName())
+		}
+
+		t.Columns = append(
+			t.Columns, &ColumnSnapshot{
+				Name:             column.GetName(),
+				Type:             typeName,
+				Length:           column.GetLength(),
+				Precision:        column.GetPrecision(),
+				Scale:            column.GetScale(),
+				Unsigned:         column.GetUnsigned(),
+				Fixed:            column.GetFixed(),
+				NotNull:          column.GetNotNull(),
+				Default:          column.GetColumnDefault(),
+				Autoincrement:    column.GetAutoincrement(),
+				Comment:          column.GetComment(),
+				ColumnDefinition: column.GetColumnDefinition(),
+				Generated:        column.GetGenerated(),
+				PlatformOptions:  column.GetPlatformOptions(),
+			},
+		)
+	}
+
+	for _, index := range table.GetIndexes() {
+		columns := index.GetColumns()
+		flags := index.GetFlags()
+		slices.Sort(flags)
+
+		t.Indexes = append(
+			t.Indexes, &IndexSnapshot{
+				Name:    index.GetName(),
+				Columns: columns,
+				Unique:  index.IsUnique(),
+				Primary: index.IsPrimary(),
+				Flags:   flags,
+				Options: index.GetOptions(),
+			},
+		)
+	}
+	slices.SortFunc(t.Indexes, func(a, b *IndexSnapshot) int { return strings.Compare(a.Name, b.Name) })
+
+	for _, constraint := range table.GetUniqueConstraints() {
+		columns := constraint.GetColumns()
+		flags := constraint.GetFlags()
+		slices.Sort(flags)
+
+		uniqueConstraint := &UniqueConstraintSnapshot{Name: constraint.GetName(), Columns: columns, Flags: flags}
+		if len(constraint.GetOptions()) > 0 {
+			uniqueConstraint.Options = constraint.GetOptions()
+		}
+
+		t.UniqueConstraints = append(t.UniqueConstraints, uniqueConstraint)
+	}
+	slices.SortFunc(
+		t.UniqueConstraints,
+		func(a, b *UniqueConstraintSnapshot) int { return strings.Compare(a.Name, b.Name) },
+	)
+
+	for _, fk := range table.GetForeignKeys() {
+		// Column order is kept, local and foreign columns are matched by position
+		localColumns := fk.GetLocalColumns()
+		foreignColumns := fk.GetForeignColumns()
+
+		t.ForeignKeys = append(
+			t.ForeignKeys, &ForeignKeySnapshot{
+				Name:           fk.GetName(),
+				LocalColumns:   localColumns,
+				ForeignTable:   fk.GetForeignTableName(),
+				ForeignColumns: foreignColumns,
+				OnUpdate:       fk.OnUpdate(),
+				OnDelete:       fk.OnDelete(),
+			},
+		)
+	}
+	slices.SortFunc(t.ForeignKeys, func(a, b *ForeignKeySnapshot) int { return strings.Compare(a.Name, b.Name) })
+
+	for _, check := range table.GetCheckConstraints() {
+		t.CheckConstraints = append(
+			t.CheckConstraints,
+			&CheckConstraintSnapshot{Name: check.GetName(), Expression: check.GetExpression()},
+		)
+	}
+	slices.SortFunc(
+		t.CheckConstraints,
+		func(a, b *CheckConstraintSnapshot) int { return strings.Compare(a.Name, b.Name) },
+	)
+
+	return t, nil
+}
+
+// ToSchema - Restores assets.Schema from the snapshot.
+func (s *SchemaSnapshot) ToSchema() (*assets.Schema, error) {
+	if s.Version != FormatVersion {
+		return nil, errors.Errorf("unsupported schema snapshot version %d, expected %d", s.Version, FormatVersion)
+	}
+
+	schemaConfig := dtos.NewSchemaConfig()
+	schemaConfig.SetName(s.Name)
+
+	tables := make([]*assets.Table, 0, len(s.Tables))
+	for _, t := range s.Tables {
+		tables = append(tables, t.toTable())
+	}
+
+	sequences := make([]*assets.Sequence, 0, len(s.Sequences))
+	for _, seq := range s.Sequences {
+		options := []assets.SequenceOption{
+			assets.WithAllocationSize(seq.AllocationSize),
+			assets.WithInitialValue(seq.InitialValue),
+		}
+		if seq.Cache != nil {
+			options = append(options, assets.WithCache(*seq.Cache))
+		}
+
+		sequences = append(sequences, assets.NewSequence(seq.Name, options...))
+	}
+
+	schema := assets.NewSchema(tables, sequences, schemaConfig, s.Namespaces)
+
+	for _, enumType := range s.EnumTypes {
+		schema.CreateEnumType(enumType.Name, enumType.Values)
+	}
+
+	for _, view := range s.Views {
+		schema.CreateView(view.Name, view.SQL)
+	}
+
+	return schema, nil
+}
+
+func (t *TableSnapshot) toTable() *assets.Table {
+	columns := make([]*assets.Column, 0, len(t.Columns))
+	for _, c := range t.Columns {
+		options := []assets.ColumnOption{
+			assets.WithColumnLength(c.Length),
+			assets.WithColumnPrecision(c.Precision),
+			assets.WithColumnScale(c.Scale),
+			assets.WithColumnComment(c.Comment),
+		}
+		if c.Fixed {
+			options = append(options, assets.WithColumnFixed())
+		}
+		if c.NotNull {
+			options = append(options, assets.WithColumnNotNull())
+		}
+		if c.Default != nil {
+			options = append(options, assets.WithColumnDefault(*c.Default))
+		}
+		if c.Autoincrement {
+			options = append(options, assets.WithColumnAutoIncrement())
+		}
+		if c.Generated != nil {
+			options = append(options, assets.WithColumnGenerated(*c.Generated))
+		}
+
+		column := assets.NewColumn(c.Name, types.GetType(c.Type), options...)
+		if c.Unsigned {
+			column.SetUnsigned()
+		}
+		if c.ColumnDefinition != nil {
+			column.SetColumnDefinition(*c.ColumnDefinition)
+		}
+		for name, value := range c.PlatformOptions {
+			column.SetPlatformOption(name, normalizeValue(value))
+		}
+
+		columns = append(columns, column)
+	}
+
+	indexes := make([]*assets.Index, 0, len(t.Indexes))
+	for _, i := range t.Indexes {
+		options := make(map[string]any)
+		for name, value := range i.Options {
+			options[name] = normalizeValue(value)
+		}
+
+		// Index lengths are compared as []*int
+		if lengths, ok := options["lengths"].([]any); ok {
+			options["lengths"] = normalizeLengths(lengths)
+		}
+
+		// Included columns and per column options are compared as []string and map[string]string
+		if include, ok := options["include"].([]any); ok {
+			options["include"] = normalizeStrings(include)
+		}
+		for _, name := range []string{"orders", "opclasses"} {
+			if values, ok := options[name].(map[string]any); ok {
+				options[name] = normalizeStringsMap(values)
+			}
+		}
+
+		indexes = append(indexes, assets.NewIndex(i.Name, i.Columns, i.Unique, i.Primary, i.Flags, options))
+	}
+
+	foreignKeys := make([]*assets.ForeignKeyConstraint, 0, len(t.ForeignKeys))
+	for _, fk := range t.ForeignKeys {
+		options := make(map[string]any)
+		if fk.OnUpdate != nil {
+			options["onUpdate"] = fk.OnUpdate
+		}
+		if fk.OnDelete != nil {
+			options["onDelete"] = fk.OnDelete
+		}
+
+		foreignKeys = append(
+			foreignKeys,
+			assets.NewForeignKeyConstraint(fk.Name, fk.LocalColumns, fk.ForeignTable, fk.ForeignColumns, options),
+		)
+	}
+
+	uniqueConstraints := make([]*assets.UniqueConstraint, 0, len(t.UniqueConstraints))
+	for _, u := range t.UniqueConstraints {
+		constraintOptions := []assets.UniqueConstraintOption{assets.WithFlags(u.Flags)}
+		if u.Options != nil {
+			constraintOptions = append(constraintOptions, assets.WithOptions(u.Options))
+		}
+
+		uniqueConstraints = append(uniqueConstraints, assets.NewUniqueConstraint(u.Name, u.Columns, constraintOptions...))
+	}
+
+	options := make(map[string]any)
+	for name, value := range t.Options {
+		options[name] = normalizeValue(value)
+	}
+
+	table := assets.NewTable(t.Name, columns, indexes, uniqueConstraints, foreignKeys, options)
+
+	if t.Comment != nil {
+		table.SetComment(t.Comment)
+	}
+
+	for _, check := range t.CheckConstraints {
+		table.AddCheckConstraint(check.Name, check.Expression)
+	}
+
+	return table
+}
+
+// getTypeName - Resolves registered type name by the type of the column type instance.
+func getTypeName(columnType types.AbstractTypeInterface) (enums.TypesType, error) {
+	if arrayType, ok := columnType.(*types.ArrayType); ok {
+		elementTypeName, err := getTypeName(arrayType.GetElementType())
+		if err != nil {
+			return "", err
+		}
+
+		return types.GetArrayTypeName(elementTypeName), nil
+	}
+
+	if enumType, ok := columnType.(*types.EnumType); ok {
+		return types.GetEnumTypeName(enumType.GetName()), nil
+	}
+
+	columnTypeName := fmt.Sprintf("%T", columnType)
+
+	for name, typeName := range types.GetTypesMap() {
+		if typeName == columnTypeName {
+			return name, nil
+		}
+	}
+
+	return "", errors.Errorf("unknown column type %s", columnTypeName)
+}
+
+// normalizeValue - Decoders produce float64 for every number, option values are compared as ints.
+func normalizeValue(value any) any {
+	switch v := value.(type) {
+	case float64:
+		if v == math.Trunc(v) {
+			return int(v)
+		}
+	case []any:
+		result := make([]any, 0, len(v))
+		for _, item := range v {
+			result = append(result, normalizeValue(item))
+		}
+		return result
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for key, item := range v {
+			result[key] = normalizeValue(item)
+		}
+		return result
+	}
+
+	return value
+}
+
+func normalizeLengths(values []any) []*int {
+	lengths := make([]*int, 0, len(values))
+
+	for _, value := range values {
+		if length, ok := value.(int); ok {
+			lengths = append(lengths, &length)
+		} else {
+			lengths = append(lengths, nil)
+		}
+	}
+
+	return lengths
+}
+
+func normalizeStrings(values []any) []string {
+	result := make([]string, 0, len(values))
+
+	for _, value := range values {
+		result = append(result, fmt.Sprint(value))
+	}
+
+	return result
+}
+
+func normalizeStringsMap(values map[string]any) map[string]string {
+	result := make(map[string]string, len(values))
+
+	for key, value := range values {
+		result[key] = fmt.Sprint(value)
+	}
+
+	return result
+}
@@ -0,0 +1,101 @@
+package schema_snapshots
+
+import (
+	"encoding/json"
+	"github.com/KoNekoD/gormite/pkg/assets"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// Marshal - Serializes schema into the snapshot of given format, output is deterministic.
+func Marshal(schema *assets.Schema, format string) ([]byte, error) {
+	snapshot, err := NewSchemaSnapshot(schema)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	switch format {
+	case FormatJSON:
+		content, err := json.MarshalIndent(snapshot, "", "  ")
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		return append(content, '\n'), nil
+	case FormatYAML:
+		content, err := yaml.Marshal(snapshot)
+
+		return content, errors.WithStack(err)
+	}
+
+	return nil, errors.Errorf("unknown schema snapshot format %s", format)
+}
+
+// Unmarshal - Restores schema from the snapshot of given format.
+func Unmarshal(content []byte, format string) (*assets.Schema, error) {
+	snapshot := &SchemaSnapshot{}
+
+	switch format {
+	case FormatJSON:
+		if err := json.Unmarshal(content, snapshot); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	case FormatYAML:
+		if err := yaml.Unmarshal(content, snapshot); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	default:
+		return nil, errors.Errorf("unknown schema snapshot format %s", format)
+	}
+
+	return snapshot.ToSchema()
+}
+
+// ReadFile - Reads schema from the snapshot file, format is detected by the extension.
+func ReadFile(path string) (*assets.Schema, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	schema, err := Unmarshal(content, GetFileFormat(path))
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid schema snapshot %s", path)
+	}
+
+	return schema, nil
+}
+
+// WriteFile - Writes schema to the snapshot file, format is detected by the extension.
+func WriteFile(path string, schema *assets.Schema) error {
+	content, err := Marshal(schema, GetFileFormat(path))
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	return errors.WithStack(os.WriteFile(path, content, 0644))
+}
+
+// GetFileFormat - Returns yaml format for .yaml/.yml files and json for everything else.
+func GetFileFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	}
+
+	return FormatJSON
+}
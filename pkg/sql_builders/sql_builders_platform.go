@@ -16,6 +16,8 @@ type SqlBuildersPlatform interface {
 	GetCreateSequenceSQL(sequence *assets.Sequence) string
 	GetDropTablesSQL(tables []*assets.Table) []string
 	GetDropSequenceSQL(name string) string
+	GetCreateEnumTypeSQL(enumType *assets.EnumType) string
+	GetDropEnumTypeSQL(name string) string
 	GetUnionAllSQL() string
 	GetUnionDistinctSQL() string
 	GetUnionSelectPartSQL(subQuery string) string
@@ -37,10 +39,12 @@ type SqlBuildersPlatform interface {
 	GetInlineColumnCommentSQL(comment string) string
 	GetCreateTemporaryTableSnippetSQL() string
 	GetAlterSequenceSQL(sequence *assets.Sequence) string
+	GetRenameSequenceSQL(oldName string, newName string) string
 	GetCreateIndexSQL(index *assets.Index, table string) string
 	GetPartialIndexSQL(index *assets.Index) string
 	GetCreatePrimaryKeySQL(index *assets.Index, table string) string
 	GetCreateUniqueConstraintSQL(constraint *assets.UniqueConstraint, tableName string) string
+	GetCreateCheckConstraintSQL(constraint *assets.CheckConstraint, tableName string) string
 	GetDropSchemaSQL(schemaName string) string
 	GetCreateForeignKeySQL(foreignKey *assets.ForeignKeyConstraint, table string) string
 	GetRenameTableSQL(oldName string, newName string) string
@@ -48,8 +52,10 @@ type SqlBuildersPlatform interface {
 	GetColumnDeclarationListSQL(columns []map[string]any) string
 	GetColumnDeclarationSQL(name string, column map[string]any) string
 	GetDefaultValueDeclarationSQL(column map[string]any) string
+	GetGeneratedColumnDeclarationSQL(expression string) string
 	GetCheckDeclarationSQL(definition []map[string]any) string
 	GetUniqueConstraintDeclarationSQL(constraint *assets.UniqueConstraint) string
+	GetCheckConstraintDeclarationSQL(constraint *assets.CheckConstraint) string
 	GetIndexDeclarationSQL(index *assets.Index) string
 	GetForeignKeyDeclarationSQL(foreignKey *assets.ForeignKeyConstraint) string
 	GetAdvancedForeignKeyOptionsSQL(foreignKey *assets.ForeignKeyConstraint) string
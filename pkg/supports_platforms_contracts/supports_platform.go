@@ -4,6 +4,7 @@ type SupportsPlatform interface {
 	SupportsColumnLengthIndexes() bool
 	SupportsSchemas() bool
 	SupportsSequences() bool
+	SupportsEnumTypes() bool
 	SupportsIdentityColumns() bool
 	SupportsPartialIndexes() bool
 	SupportsSavepoints() bool
@@ -11,4 +12,5 @@ type SupportsPlatform interface {
 	SupportsInlineColumnComments() bool
 	SupportsCommentOnStatement() bool
 	SupportsColumnCollation() bool
+	SupportsGeneratedColumns() bool
 }
@@ -0,0 +1,32 @@
+package types
+
+import (
+	"github.com/KoNekoD/gormite/pkg/enums"
+)
+
+// arrayTypeNameSuffix - Array types are not registered, they are named by their element types, e.g. bigint[].
+const arrayTypeNameSuffix = "[]"
+
+// ArrayType - Native array of the element type, e.g. text[] or bigint[] on postgres.
+type ArrayType struct {
+	*AbstractType
+
+	elementType AbstractTypeInterface
+}
+
+func NewArrayType(elementType AbstractTypeInterface) *ArrayType {
+	return &ArrayType{AbstractType: &AbstractType{}, elementType: elementType}
+}
+
+func (a *ArrayType) GetElementType() AbstractTypeInterface {
+	return a.elementType
+}
+
+func (a *ArrayType) GetSQLDeclaration(column map[string]any, platform TypesPlatform) string {
+	return platform.GetArrayTypeDeclarationSQL(column, a.elementType)
+}
+
+// GetArrayTypeName - Returns name of the array type of the element type, see GetType.
+func GetArrayTypeName(elementTypeName enums.TypesType) enums.TypesType {
+	return elementTypeName + arrayTypeNameSuffix
+}
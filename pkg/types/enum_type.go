@@ -0,0 +1,33 @@
+package types
+
+import (
+	"github.com/KoNekoD/gormite/pkg/enums"
+)
+
+// enumTypeNamePrefix - Enum types are not registered, they are named by the database type, e.g. enum:order_status.
+const enumTypeNamePrefix = "enum:"
+
+// EnumType - Column of the database enum type, values of the enum are declared by assets.EnumType of the schema.
+type EnumType struct {
+	*AbstractType
+
+	name string
+}
+
+func NewEnumType(name string) *EnumType {
+	return &EnumType{AbstractType: &AbstractType{}, name: name}
+}
+
+// GetName - Returns name of the database enum type.
+func (e *EnumType) GetName() string {
+	return e.name
+}
+
+func (e *EnumType) GetSQLDeclaration(column map[string]any, platform TypesPlatform) string {
+	return platform.GetEnumTypeDeclarationSQL(column, e.name)
+}
+
+// GetEnumTypeName - Returns name of the column type of the database enum type, see GetType.
+func GetEnumTypeName(name string) enums.TypesType {
+	return enums.TypesType(enumTypeNamePrefix + name)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/KoNekoD/gormite/pkg/enums"
 	"github.com/KoNekoD/smt/pkg/smt"
+	"strings"
 )
 
 // BuiltinTypesMap - The map of supported doctrine mapping types.
@@ -70,6 +71,14 @@ func createTypeRegistry() *TypeRegistry {
 	return &TypeRegistry{instances: instances}
 }
 func GetType(name enums.TypesType) AbstractTypeInterface {
+	if elementTypeName, ok := strings.CutSuffix(string(name), arrayTypeNameSuffix); ok {
+		return NewArrayType(GetType(enums.TypesType(elementTypeName)))
+	}
+
+	if enumName, ok := strings.CutPrefix(string(name), enumTypeNamePrefix); ok {
+		return NewEnumType(enumName)
+	}
+
 	registry := GetTypeRegistry()
 	return registry.Get(name)
 }
@@ -19,6 +19,9 @@ type TypesPlatform interface {
 	GetGuidTypeDeclarationSQL(column map[string]any) string
 	GetFloatTypeDeclarationSQL(column map[string]any) string
 	GetDateTimeTzTypeDeclarationSQL(column map[string]any) string
+	GetDateIntervalTypeDeclarationSQL(column map[string]any) string
+	GetArrayTypeDeclarationSQL(column map[string]any, elementType AbstractTypeInterface) string
+	GetEnumTypeDeclarationSQL(column map[string]any, name string) string
 	GetSmallFloatTypeDeclarationSQL(column map[string]any) string
 	GetCharTypeDeclarationSQLSnippet(length *int) string
 	GetVarcharTypeDeclarationSQLSnippet(length *int) string
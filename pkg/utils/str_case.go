@@ -13,3 +13,36 @@ func ToSnakeCase(str string) string {
 	snake = matchAllCap.ReplaceAllString(snake, "${1}_${2}")
 	return strings.ToLower(snake)
 }
+
+var pascalCaseInitialisms = map[string]string{"id": "ID", "uuid": "UUID", "url": "URL", "ip": "IP", "api": "API"}
+
+func ToPascalCase(str string) string {
+	var pascal strings.Builder
+
+	for _, part := range strings.FieldsFunc(str, func(r rune) bool { return r == '_' || r == '-' || r == ' ' || r == '.' }) {
+		if initialism, ok := pascalCaseInitialisms[strings.ToLower(part)]; ok {
+			pascal.WriteString(initialism)
+			continue
+		}
+
+		pascal.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+
+	return pascal.String()
+}
+
+// Pluralize - Returns plural of the last word of snake_case name by the regular english rules, e.g. order_item is
+// order_items, category is categories and address is addresses.
+func Pluralize(str string) string {
+	switch {
+	case str == "":
+		return str
+	case strings.HasSuffix(str, "y") && len(str) > 1 && !strings.ContainsRune("aeiou", rune(str[len(str)-2])):
+		return str[:len(str)-1] + "ies"
+	case strings.HasSuffix(str, "s"), strings.HasSuffix(str, "x"), strings.HasSuffix(str, "z"),
+		strings.HasSuffix(str, "ch"), strings.HasSuffix(str, "sh"):
+		return str + "es"
+	default:
+		return str + "s"
+	}
+}
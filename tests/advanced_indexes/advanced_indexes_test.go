@@ -0,0 +1,191 @@
+package advanced_indexes
+
+import (
+	"github.com/KoNekoD/gormite/pkg/assets"
+	"github.com/KoNekoD/gormite/pkg/diff_calc"
+	"github.com/KoNekoD/gormite/pkg/dtos"
+	"github.com/KoNekoD/gormite/pkg/platforms/postgres_platform"
+	"github.com/KoNekoD/gormite/pkg/schema_managers/postgres_schema_manager"
+	"github.com/KoNekoD/gormite/tests/test_helpers"
+	"github.com/KoNekoD/ptrs/pkg/ptrs"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const entitySource = `package entities
+
+import "time"
+
+// User users
+type User struct {
+	ID        int       ` + "`db:\"id\" pk:\"true\"`" + `
+	Email     string    ` + "`db:\"email\" type:\"text\" index:\"idx_users_email_lower\" index_expr:\"idx_users_email_lower:lower(email)\"`" + `
+	Name      string    ` + "`db:\"name\" type:\"text\" index:\"idx_users_name_trgm\" index_using:\"idx_users_name_trgm:gin\" index_opclass:\"idx_users_name_trgm:gin_trgm_ops\"`" + `
+	Tags      []string  ` + "`db:\"tags\" index:\"idx_users_tags\" index_using:\"idx_users_tags:gin\"`" + `
+	CreatedAt time.Time ` + "`db:\"created_at\" index:\"idx_users_created_at\" index_order:\"idx_users_created_at:desc nulls last\"`" + `
+	Age       int       ` + "`db:\"age\" index_include:\"idx_users_created_at\"`" + `
+}
+`
+
+func TestAdvancedIndexes(t *testing.T) {
+	localSchema, err := test_helpers.IntrospectSource(t, entitySource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	platform := postgres_platform.NewPostgreSQLPlatform()
+	up := platform.GetAlterSchemaSQL(diff_calc.NewComparator(platform).CompareSchemas(assets.NewSchema(nil, nil, nil, []string{"public"}), localSchema))
+
+	for _, sql := range []string{
+		"CREATE INDEX idx_users_email_lower ON users ((lower(email)))",
+		"CREATE INDEX idx_users_name_trgm ON users USING gin (name gin_trgm_ops)",
+		"CREATE INDEX idx_users_tags ON users USING gin (tags)",
+		"CREATE INDEX idx_users_created_at ON users (created_at DESC NULLS LAST) INCLUDE (age)",
+	} {
+		if !slices.Contains(up, sql) {
+			t.Fatalf("expected %s in %v", sql, up)
+		}
+	}
+}
+
+func TestAdvancedIndexesAlter(t *testing.T) {
+	localSchema, err := test_helpers.IntrospectSource(t, entitySource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldSource := strings.Replace(entitySource, " index_using:\"idx_users_tags:gin\"", "", 1)
+	oldSource = strings.Replace(oldSource, "desc nulls last", "desc", 1)
+	oldSource = strings.Replace(oldSource, " index_include:\"idx_users_created_at\"", "", 1)
+
+	oldSchema, err := test_helpers.IntrospectSource(t, oldSource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	platform := postgres_platform.NewPostgreSQLPlatform()
+	comparator := diff_calc.NewComparator(platform)
+
+	up := platform.GetAlterSchemaSQL(comparator.CompareSchemas(oldSchema, localSchema))
+
+	for _, sql := range []string{
+		"DROP INDEX idx_users_tags",
+		"CREATE INDEX idx_users_tags ON users USING gin (tags)",
+		"DROP INDEX idx_users_created_at",
+		"CREATE INDEX idx_users_created_at ON users (created_at DESC NULLS LAST) INCLUDE (age)",
+	} {
+		if !slices.Contains(up, sql) {
+			t.Fatalf("expected %s in %v", sql, up)
+		}
+	}
+
+	if len(up) != 4 {
+		t.Fatalf("expected only changed indexes to be recreated, got %v", up)
+	}
+
+	// Orderings are compared in the normalized form
+	sameSource := strings.Replace(entitySource, "desc nulls last", "DESC  NULLS LAST", 1)
+	sameSource = strings.Replace(sameSource, "lower(email)", "(LOWER(email))", 1)
+
+	sameSchema, err := test_helpers.IntrospectSource(t, sameSource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := comparator.CompareSchemas(localSchema, sameSchema); !diff.IsEmpty() {
+		t.Fatalf("expected no diff, got %v", platform.GetAlterSchemaSQL(diff))
+	}
+}
+
+func TestAdvancedIndexesIntrospection(t *testing.T) {
+	platform := postgres_platform.NewPostgreSQLPlatform()
+	schemaManager := postgres_schema_manager.NewPostgreSQLSchemaManager(nil, platform)
+
+	rows := []*dtos.SelectIndexColumnsDto{
+		{
+			RelName:      "idx_users_email_lower",
+			Using:        "btree",
+			IndexColumns: ptrs.AsPtr(`[{"definition": "lower(email)", "option": 0, "opclass": null, "included": false}]`),
+		},
+		{
+			RelName:      "idx_users_name_trgm",
+			Using:        "gin",
+			IndexColumns: ptrs.AsPtr(`[{"definition": "name", "option": 0, "opclass": "gin_trgm_ops", "included": false}]`),
+		},
+		{
+			RelName:      "idx_users_tags",
+			Using:        "gin",
+			IndexColumns: ptrs.AsPtr(`[{"definition": "tags", "option": 0, "opclass": null, "included": false}]`),
+		},
+		{
+			RelName: "idx_users_created_at",
+			Using:   "btree",
+			IndexColumns: ptrs.AsPtr(
+				`[{"definition": "created_at", "option": 1, "opclass": null, "included": false},` +
+					`{"definition": "age", "option": null, "opclass": null, "included": true}]`,
+			),
+		},
+	}
+
+	indexes := schemaManager.GetPortableTableIndexesList(rows, "users")
+
+	localSchema, err := test_helpers.IntrospectSource(t, entitySource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, index := range indexes {
+		localIndex := localSchema.GetTable("users").GetIndex(index.GetName())
+
+		if localIndex == nil {
+			t.Fatalf("index %s not found", index.GetName())
+		}
+
+		if !index.IsFulfilledBy(localIndex) || !localIndex.IsFulfilledBy(index) {
+			t.Fatalf("expected introspected index %s to match the local one", index.GetName())
+		}
+	}
+
+	if order := indexes["idx_users_created_at"].GetColumnOrder("created_at"); order != "DESC NULLS LAST" {
+		t.Fatalf("expected DESC NULLS LAST, got %s", order)
+	}
+}
+
+func TestAdvancedIndexesImportAndSnapshot(t *testing.T) {
+	localSchema, err := test_helpers.IntrospectSource(t, entitySource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source := test_helpers.GenerateSource(t, localSchema)
+	for _, tag := range []string{
+		"index_expr:\"idx_users_email_lower:lower(email)\"",
+		"index_opclass:\"idx_users_name_trgm:gin_trgm_ops\"",
+		"index_order:\"idx_users_created_at:desc nulls last\"",
+		"`db:\"age\" index_include:\"idx_users_created_at\"`",
+	} {
+		if !strings.Contains(source, tag) {
+			t.Fatalf("expected %s in generated entity:\n%s", tag, source)
+		}
+	}
+
+	index := test_helpers.SnapshotSchema(t, localSchema).GetTable("users").GetIndex("idx_users_created_at")
+	if index == nil || !index.IsFulfilledBy(localSchema.GetTable("users").GetIndex("idx_users_created_at")) {
+		t.Fatalf("expected snapshot to keep options of idx_users_created_at")
+	}
+}
+
+func TestAdvancedIndexesInvalid(t *testing.T) {
+	cases := map[string]string{
+		"desc nulls last":                        "desc nulls",
+		"index:\"idx_users_tags\" index_using":   "index_using",
+		"index_include:\"idx_users_created_at\"": "index_include:\"idx_users_age\"",
+	}
+
+	for from, to := range cases {
+		if _, err := test_helpers.IntrospectSource(t, strings.Replace(entitySource, from, to, 1)); err == nil {
+			t.Fatalf("expected error when %s is replaced with %s", from, to)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package arrays
+
+import (
+	"github.com/KoNekoD/gormite/pkg/diff_calc"
+	"github.com/KoNekoD/gormite/pkg/dtos"
+	"github.com/KoNekoD/gormite/pkg/platforms/postgres_platform"
+	"github.com/KoNekoD/gormite/pkg/schema_managers/postgres_schema_manager"
+	"github.com/KoNekoD/gormite/pkg/types"
+	"github.com/KoNekoD/gormite/tests/test_helpers"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const entitySource = `package entities
+
+import "github.com/google/uuid"
+
+// Post posts
+type Post struct {
+	ID     int         ` + "`db:\"id\" pk:\"true\"`" + `
+	Tags   []string    ` + "`db:\"tags\"`" + `
+	Codes  []string    ` + "`db:\"codes\" type:\"varchar\" length:\"64\"`" + `
+	Scores []int64     ` + "`db:\"scores\" nullable:\"true\"`" + `
+	Refs   []uuid.UUID ` + "`db:\"refs\"`" + `
+	Meta   []string    ` + "`db:\"meta\" type:\"jsonb\"`" + `
+}
+`
+
+func TestArrays(t *testing.T) {
+	localSchema, err := test_helpers.IntrospectSource(t, entitySource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := localSchema.GetTable("posts").GetColumn("meta").GetColumnType().(*types.JsonType); !ok {
+		t.Fatal("expected json type of meta")
+	}
+
+	sql := postgres_platform.NewPostgreSQLPlatform().GetCreateTableSQL(localSchema.GetTable("posts"))[0]
+
+	for _, declaration := range []string{
+		"tags TEXT[] NOT NULL",
+		"codes VARCHAR(64)[] NOT NULL",
+		"scores BIGINT[],",
+		"refs UUID[] NOT NULL",
+		"meta JSONB NOT NULL",
+	} {
+		if !strings.Contains(sql, declaration) {
+			t.Fatalf("expected %s in %s", declaration, sql)
+		}
+	}
+}
+
+func TestArraysAlter(t *testing.T) {
+	localSchema, err := test_helpers.IntrospectSource(t, entitySource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldSchema, err := test_helpers.IntrospectSource(t, strings.Replace(entitySource, "[]int64  ", "[]int32  ", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	platform := postgres_platform.NewPostgreSQLPlatform()
+	up := platform.GetAlterSchemaSQL(diff_calc.NewComparator(platform).CompareSchemas(oldSchema, localSchema))
+
+	expected := []string{"ALTER TABLE posts ALTER scores TYPE BIGINT[]"}
+	if !slices.Equal(up, expected) {
+		t.Fatalf("expected %v, got %v", expected, up)
+	}
+}
+
+func TestArraysIntrospection(t *testing.T) {
+	platform := postgres_platform.NewPostgreSQLPlatform()
+	schemaManager := postgres_schema_manager.NewPostgreSQLSchemaManager(nil, platform)
+
+	columns := []struct {
+		dto         *dtos.SelectTableColumnsDto
+		declaration string
+	}{
+		{&dtos.SelectTableColumnsDto{Field: "tags", Type: "_text", CompleteType: "text[]"}, "TEXT[]"},
+		{&dtos.SelectTableColumnsDto{Field: "scores", Type: "_int8", CompleteType: "bigint[]"}, "BIGINT[]"},
+		{&dtos.SelectTableColumnsDto{Field: "codes", Type: "_varchar", CompleteType: "character varying(64)[]"}, "VARCHAR(64)[]"},
+		{&dtos.SelectTableColumnsDto{Field: "refs", Type: "_uuid", CompleteType: "uuid[]"}, "UUID[]"},
+	}
+
+	for _, c := range columns {
+		column := schemaManager.GetPortableTableColumnDefinition(c.dto)
+
+		if declaration := column.GetColumnType().GetSQLDeclaration(column.ToArray(), platform); declaration != c.declaration {
+			t.Fatalf("expected %s of %s, got %s", c.declaration, c.dto.Field, declaration)
+		}
+	}
+}
+
+func TestArraysImportAndSnapshot(t *testing.T) {
+	localSchema, err := test_helpers.IntrospectSource(t, entitySource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source := test_helpers.GenerateSource(t, localSchema)
+	for _, tag := range []string{"`db:\"tags\" type:\"text\"`", "`db:\"refs\" type:\"uuid\"`"} {
+		if !strings.Contains(source, tag) {
+			t.Fatalf("expected %s in generated entity:\n%s", tag, source)
+		}
+	}
+
+	platform := postgres_platform.NewPostgreSQLPlatform()
+	column := test_helpers.SnapshotSchema(t, localSchema).GetTable("posts").GetColumn("codes")
+
+	if declaration := column.GetColumnType().GetSQLDeclaration(column.ToArray(), platform); declaration != "VARCHAR(64)[]" {
+		t.Fatalf("expected snapshot to keep VARCHAR(64)[], got %s", declaration)
+	}
+}
@@ -0,0 +1,116 @@
+package change_safety
+
+import (
+	"github.com/KoNekoD/gormite/pkg/assets"
+	"github.com/KoNekoD/gormite/pkg/diff_calc"
+	"github.com/KoNekoD/gormite/pkg/diff_dtos"
+	"github.com/KoNekoD/gormite/pkg/enums"
+	"github.com/KoNekoD/gormite/pkg/platforms/postgres_platform"
+	"github.com/KoNekoD/gormite/pkg/types"
+	"github.com/KoNekoD/ptrs/pkg/ptrs"
+	"testing"
+)
+
+func TestChangesSafety(t *testing.T) {
+	oldSchema := assets.NewSchema(nil, nil, nil, []string{"public"})
+	oldUser := oldSchema.CreateTable("app_user")
+	oldUser.AddColumn("id", types.GetType(enums.TypeInteger), assets.WithColumnNotNull())
+	oldUser.AddColumn("email", types.GetType(enums.TypeString), assets.WithColumnLength(ptrs.AsPtr(255)))
+	oldUser.AddColumn("age", types.GetType(enums.TypeInteger))
+	oldUser.AddColumn("nickname", types.GetType(enums.TypeString), assets.WithColumnLength(ptrs.AsPtr(64)))
+	oldSchema.CreateTable("legacy").AddColumn("id", types.GetType(enums.TypeInteger))
+
+	newSchema := assets.NewSchema(nil, nil, nil, []string{"public"})
+	newUser := newSchema.CreateTable("app_user")
+	newUser.AddColumn("id", types.GetType(enums.TypeBigint), assets.WithColumnNotNull())
+	newUser.AddColumn("email", types.GetType(enums.TypeString), assets.WithColumnLength(ptrs.AsPtr(100)))
+	newUser.AddColumn("age", types.GetType(enums.TypeInteger))
+	newUser.AddColumn("bio", types.GetType(enums.TypeText))
+
+	diff := diff_calc.NewComparator(postgres_platform.NewPostgreSQLPlatform()).CompareSchemas(oldSchema, newSchema)
+
+	expected := map[string]enums.ChangeSafety{
+		"alter column app_user.id":      enums.ChangeSafetySafe,
+		"alter column app_user.email":   enums.ChangeSafetyLossy,
+		"drop column app_user.nickname": enums.ChangeSafetyDestructive,
+		"add column app_user.bio":       enums.ChangeSafetySafe,
+		"drop table legacy":             enums.ChangeSafetyDestructive,
+	}
+
+	changes := diff.GetChanges()
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes, got %v", len(expected), changes)
+	}
+
+	for _, change := range changes {
+		safety, ok := expected[change.String()]
+		if !ok {
+			t.Fatalf("unexpected change %s", change)
+		}
+		if safety != change.Safety {
+			t.Fatalf("expected %s to be %s, got %s", change, safety, change.Safety)
+		}
+	}
+
+	if destructive := diff_dtos.FilterChanges(changes, enums.ChangeSafetyDestructive); len(destructive) != 2 {
+		t.Fatalf("expected 2 destructive changes, got %d", len(destructive))
+	}
+}
+
+func TestChangesSafetyOfConstraints(t *testing.T) {
+	oldSchema := assets.NewSchema(nil, nil, nil, []string{"public"})
+	oldUser := oldSchema.CreateTable("app_user")
+	oldUser.AddColumn("id", types.GetType(enums.TypeInteger), assets.WithColumnNotNull())
+	oldUser.AddColumn("email", types.GetType(enums.TypeString), assets.WithColumnLength(ptrs.AsPtr(255)))
+	oldUser.AddColumn("age", types.GetType(enums.TypeInteger))
+	oldUser.AddColumn("role_id", types.GetType(enums.TypeInteger))
+	oldUser.SetPrimaryKey([]string{"id"}, nil)
+	oldRole := oldSchema.CreateTable("role")
+	oldRole.AddColumn("id", types.GetType(enums.TypeInteger), assets.WithColumnNotNull())
+	oldRole.SetPrimaryKey([]string{"id"}, nil)
+
+	newSchema := assets.NewSchema(nil, nil, nil, []string{"public"})
+	newUser := newSchema.CreateTable("app_user")
+	newUser.AddColumn("id", types.GetType(enums.TypeInteger), assets.WithColumnNotNull())
+	newUser.AddColumn("email", types.GetType(enums.TypeString), assets.WithColumnLength(ptrs.AsPtr(255)))
+	newUser.AddColumn("age", types.GetType(enums.TypeInteger))
+	newUser.AddColumn("role_id", types.GetType(enums.TypeInteger))
+	newUser.AddColumn("login", types.GetType(enums.TypeString), assets.WithColumnNotNull(), assets.WithColumnLength(ptrs.AsPtr(64)))
+	newUser.AddColumn("is_active", types.GetType(enums.TypeBoolean), assets.WithColumnNotNull(), assets.WithColumnDefault("true"))
+	newUser.SetPrimaryKey([]string{"id"}, nil)
+	newUser.AddUniqueIndex([]string{"email"}, ptrs.AsPtr("idx__app_user__email__uniq"), nil)
+	newUser.AddIndex([]string{"age"}, ptrs.AsPtr("idx_app_user_age"), nil, nil)
+	newUser.AddForeignKeyConstraint("role", []string{"role_id"}, []string{"id"}, nil, ptrs.AsPtr("fk_app_user_role_id"))
+	newUser.AddCheckConstraint("chk_app_user_age", "age >= 0")
+	newRole := newSchema.CreateTable("role")
+	newRole.AddColumn("id", types.GetType(enums.TypeInteger), assets.WithColumnNotNull())
+	newRole.SetPrimaryKey([]string{"id"}, nil)
+
+	diff := diff_calc.NewComparator(postgres_platform.NewPostgreSQLPlatform()).CompareSchemas(oldSchema, newSchema)
+
+	// Added constraints and not null column without default fail on the existing rows which violate them
+	expected := map[string]enums.ChangeSafety{
+		"add column app_user.login":                     enums.ChangeSafetyLossy,
+		"add column app_user.is_active":                 enums.ChangeSafetySafe,
+		"add index app_user.idx__app_user__email__uniq": enums.ChangeSafetyLossy,
+		"add index app_user.idx_app_user_age":           enums.ChangeSafetySafe,
+		"add index app_user.IDX_88BDF3E9D60322AC":       enums.ChangeSafetySafe,
+		"add foreign key app_user.fk_app_user_role_id":  enums.ChangeSafetyLossy,
+		"add check app_user.chk_app_user_age":           enums.ChangeSafetyLossy,
+	}
+
+	changes := diff.GetChanges()
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes, got %v", len(expected), changes)
+	}
+
+	for _, change := range changes {
+		safety, ok := expected[change.String()]
+		if !ok {
+			t.Fatalf("unexpected change %s", change)
+		}
+		if safety != change.Safety {
+			t.Fatalf("expected %s to be %s, got %s", change, safety, change.Safety)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package check_constraints
+
+import (
+	"github.com/KoNekoD/gormite/pkg/assets"
+	"github.com/KoNekoD/gormite/pkg/diff_calc"
+	"github.com/KoNekoD/gormite/pkg/platforms/postgres_platform"
+	"github.com/KoNekoD/gormite/tests/test_helpers"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const entitySource = `package entities
+
+// Product products check:price_range:(price < 1000 AND price > discount)
+type Product struct {
+	ID       int    ` + "`db:\"id\" pk:\"true\"`" + `
+	Status   string ` + "`db:\"status\" check:\"status_known:(status IN ('New', 'Sold'))\"`" + `
+	Price    int    ` + "`db:\"price\" check:\"price_positive:(price > 0)\"`" + `
+	Discount int    ` + "`db:\"discount\" check:\"(discount >= 0), (discount < 100)\"`" + `
+}
+`
+
+func TestCheckConstraints(t *testing.T) {
+	localSchema, err := test_helpers.IntrospectSource(t, entitySource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checks := slices.Sorted(maps.Keys(localSchema.GetTable("products").GetCheckConstraints()))
+
+	expected := []string{"price_positive", "price_range", "products_discount_check", "products_discount_check1", "status_known"}
+	if !slices.Equal(checks, expected) {
+		t.Fatalf("expected checks %v, got %v", expected, checks)
+	}
+
+	platform := postgres_platform.NewPostgreSQLPlatform()
+	up := platform.GetAlterSchemaSQL(diff_calc.NewComparator(platform).CompareSchemas(assets.NewSchema(nil, nil, nil, []string{"public"}), localSchema))
+
+	for _, pattern := range []string{
+		"CONSTRAINT price_positive CHECK (price > 0)",
+		"CONSTRAINT status_known CHECK (status IN ('New', 'Sold'))",
+		"CONSTRAINT price_range CHECK (price < 1000 AND price > discount)",
+	} {
+		if !slices.ContainsFunc(up, func(sql string) bool { return strings.Contains(sql, pattern) }) {
+			t.Fatalf("expected %s in %v", pattern, up)
+		}
+	}
+}
+
+func TestCheckConstraintsAlter(t *testing.T) {
+	localSchema, err := test_helpers.IntrospectSource(t, entitySource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Database returns expressions with extra parentheses, they must not produce a diff
+	oldSource := strings.Replace(entitySource, "price_positive:(price > 0)", "price_positive:((price  >  0))", 1)
+	oldSource = strings.Replace(oldSource, "'Sold'", "'Sold', 'Lost'", 1)
+	oldSource = strings.Replace(oldSource, " check:price_range:(price < 1000 AND price > discount)", " check:legacy:(id > 0)", 1)
+
+	oldSchema, err := test_helpers.IntrospectSource(t, oldSource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	platform := postgres_platform.NewPostgreSQLPlatform()
+	up := platform.GetAlterSchemaSQL(diff_calc.NewComparator(platform).CompareSchemas(oldSchema, localSchema))
+
+	expected := []string{
+		"ALTER TABLE products DROP CONSTRAINT status_known",
+		"ALTER TABLE products DROP CONSTRAINT legacy",
+		"ALTER TABLE products ADD CONSTRAINT price_range CHECK (price < 1000 AND price > discount)",
+		"ALTER TABLE products ADD CONSTRAINT status_known CHECK (status IN ('New', 'Sold'))",
+	}
+	if !slices.Equal(up, expected) {
+		t.Fatalf("expected %v, got %v", expected, up)
+	}
+}
+
+func TestCheckConstraintsImportAndSnapshot(t *testing.T) {
+	localSchema, err := test_helpers.IntrospectSource(t, entitySource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source := test_helpers.GenerateSource(t, localSchema)
+	if hint := "check:price_range:(price < 1000 AND price > discount)"; !strings.Contains(source, hint) {
+		t.Fatalf("expected %s in generated entity:\n%s", hint, source)
+	}
+
+	checks := test_helpers.SnapshotSchema(t, localSchema).GetTable("products").GetCheckConstraints()
+	if check, ok := checks["status_known"]; !ok || check.GetExpression() != "status IN ('New', 'Sold')" {
+		t.Fatalf("expected snapshot to keep status_known check, got %v", checks)
+	}
+}
+
+func TestCheckConstraintsInvalid(t *testing.T) {
+	source := strings.Replace(entitySource, "price_positive:(price > 0)", "price_positive:(price > 0", 1)
+
+	if _, err := test_helpers.IntrospectSource(t, source); err == nil || !strings.Contains(err.Error(), "unbalanced parentheses") {
+		t.Fatalf("expected error about unbalanced parentheses, got %v", err)
+	}
+}
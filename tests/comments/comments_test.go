@@ -0,0 +1,115 @@
+package comments
+
+import (
+	"github.com/KoNekoD/gormite/pkg/assets"
+	"github.com/KoNekoD/gormite/pkg/diff_calc"
+	"github.com/KoNekoD/gormite/pkg/platforms/postgres_platform"
+	"github.com/KoNekoD/gormite/tests/test_helpers"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const entitySource = `package entities
+
+// Product products
+// Products sold in the store.
+//
+// Prices are stored in cents.
+type Product struct {
+	// Identifier
+	ID int ` + "`db:\"id\" pk:\"true\"`" + `
+	// Price of the product in cents,
+	// without discount.
+	Price int    ` + "`db:\"price\"`" + `
+	Name  string ` + "`db:\"name\"`" + ` // Name shown to the customer
+	Code  string ` + "`db:\"code\"`" + `
+}
+`
+
+func TestComments(t *testing.T) {
+	localSchema, err := test_helpers.IntrospectSource(t, entitySource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	table := localSchema.GetTable("products")
+
+	if comment := table.GetComment(); comment == nil || *comment != "Products sold in the store.\n\nPrices are stored in cents." {
+		t.Fatalf("unexpected table comment %v", comment)
+	}
+
+	expected := map[string]string{
+		"id":    "Identifier",
+		"price": "Price of the product in cents,\nwithout discount.",
+		"name":  "Name shown to the customer",
+		"code":  "",
+	}
+	for columnName, comment := range expected {
+		if actual := table.GetColumn(columnName).GetComment(); actual != comment {
+			t.Fatalf("expected comment %q of column %s, got %q", comment, columnName, actual)
+		}
+	}
+
+	platform := postgres_platform.NewPostgreSQLPlatform()
+	up := platform.GetAlterSchemaSQL(diff_calc.NewComparator(platform).CompareSchemas(assets.NewSchema(nil, nil, nil, []string{"public"}), localSchema))
+
+	for _, sql := range []string{
+		"COMMENT ON TABLE products IS 'Products sold in the store.\n\nPrices are stored in cents.'",
+		"COMMENT ON COLUMN products.name IS 'Name shown to the customer'",
+	} {
+		if !slices.Contains(up, sql) {
+			t.Fatalf("expected %s in %v", sql, up)
+		}
+	}
+}
+
+func TestCommentsAlter(t *testing.T) {
+	localSchema, err := test_helpers.IntrospectSource(t, entitySource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldSource := strings.Replace(entitySource, "// Products sold in the store.\n//\n// Prices are stored in cents.\n", "", 1)
+	oldSource = strings.Replace(oldSource, "// Identifier", "// Id", 1)
+
+	oldSchema, err := test_helpers.IntrospectSource(t, oldSource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	platform := postgres_platform.NewPostgreSQLPlatform()
+	up := platform.GetAlterSchemaSQL(diff_calc.NewComparator(platform).CompareSchemas(oldSchema, localSchema))
+
+	expected := []string{
+		"COMMENT ON COLUMN products.id IS 'Identifier'",
+		"COMMENT ON TABLE products IS 'Products sold in the store.\n\nPrices are stored in cents.'",
+	}
+	if !slices.Equal(up, expected) {
+		t.Fatalf("expected %v, got %v", expected, up)
+	}
+
+	down := platform.GetAlterSchemaSQL(diff_calc.NewComparator(platform).CompareSchemas(localSchema, oldSchema))
+	if !slices.Contains(down, "COMMENT ON TABLE products IS ''") {
+		t.Fatalf("expected table comment to be removed, got %v", down)
+	}
+}
+
+func TestCommentsImport(t *testing.T) {
+	localSchema, err := test_helpers.IntrospectSource(t, entitySource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source := test_helpers.GenerateSource(t, localSchema)
+
+	for _, comment := range []string{
+		"// Products products\n// Products sold in the store.\n//\n// Prices are stored in cents.\n",
+		"\t// Price of the product in cents,\n\t// without discount.\n",
+		"\t// Name shown to the customer\n",
+	} {
+		if !strings.Contains(source, comment) {
+			t.Fatalf("expected %q in generated entity:\n%s", comment, source)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package composite_keys
+
+import (
+	"fmt"
+	"github.com/KoNekoD/gormite/pkg/assets"
+	"github.com/KoNekoD/gormite/pkg/diff_calc"
+	"github.com/KoNekoD/gormite/pkg/platforms/postgres_platform"
+	"github.com/KoNekoD/gormite/tests/test_helpers"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const entitySource = `package entities
+
+// Order orders
+type Order struct {
+	ID int ` + "`db:\"id\" pk:\"true\"`" + `
+}
+
+// OrderLine order_line
+type OrderLine struct {
+	Order  *Order ` + "`db:\"order_id\" pk:\"true\" on_delete:\"CASCADE\"`" + `
+	LineNo int    ` + "`db:\"line_no\" pk:\"true\"`" + `
+	Code   string ` + "`db:\"code\" uniq:\"line_code\"`" + `
+}
+
+// OrderInfo order_info
+type OrderInfo struct {
+	Order *Order ` + "`db:\"id\" pk:\"true\"`" + `
+}
+
+// Shipment shipment
+type Shipment struct {
+	ID      int    ` + "`db:\"id\" pk:\"true\"`" + `
+	OrderID int    ` + "`db:\"order_id\" fk:\"order_line\" on_delete:\"CASCADE\"`" + `
+	LineNo  int    ` + "`db:\"line_no\" fk:\"order_line\"`" + `
+	Code    string ` + "`db:\"line_code\" fk:\"order_line:order_id,code\"`" + `
+	Order   int    ` + "`db:\"line_order_id\" fk:\"order_line:order_id,code\"`" + `
+}
+`
+
+func TestCompositeKeys(t *testing.T) {
+	localSchema, err := test_helpers.IntrospectSource(t, entitySource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	orderLine := localSchema.GetTable("order_line")
+	if !slices.Equal(orderLine.GetPrimaryKey().GetColumns(), []string{"order_id", "line_no"}) {
+		t.Fatalf("expected primary key of order_id and line_no, got %v", orderLine.GetPrimaryKey().GetColumns())
+	}
+
+	shipment := localSchema.GetTable("shipment")
+
+	foreignKeys := make([]string, 0)
+	for _, fk := range shipment.GetForeignKeys() {
+		foreignKeys = append(
+			foreignKeys,
+			fmt.Sprintf("%v->%s%v", fk.GetLocalColumns(), fk.GetForeignTableName(), fk.GetForeignColumns()),
+		)
+	}
+	slices.Sort(foreignKeys)
+
+	expected := []string{"[line_code line_order_id]->order_line[order_id code]", "[order_id line_no]->order_line[order_id line_no]"}
+	if !slices.Equal(foreignKeys, expected) {
+		t.Fatalf("expected foreign keys %v, got %v", expected, foreignKeys)
+	}
+
+	sequences := make([]string, 0)
+	for _, sequence := range localSchema.GetSequences() {
+		sequences = append(sequences, sequence.GetName())
+	}
+	slices.Sort(sequences)
+
+	if !slices.Equal(sequences, []string{"orders__id__seq", "shipment__id__seq"}) {
+		t.Fatalf("expected sequences only for own integer keys, got %v", sequences)
+	}
+
+	platform := postgres_platform.NewPostgreSQLPlatform()
+	up := platform.GetAlterSchemaSQL(diff_calc.NewComparator(platform).CompareSchemas(assets.NewSchema(nil, nil, nil, []string{"public"}), localSchema))
+
+	primaryKey := "PRIMARY KEY(order_id, line_no)"
+	if !slices.ContainsFunc(up, func(sql string) bool { return strings.Contains(sql, primaryKey) }) {
+		t.Fatalf("expected %s in declared order in %v", primaryKey, up)
+	}
+
+	pattern := "FOREIGN KEY (order_id, line_no) REFERENCES order_line (order_id, line_no) ON DELETE CASCADE"
+	if !slices.ContainsFunc(up, func(sql string) bool { return strings.Contains(sql, pattern) }) {
+		t.Fatalf("expected %s in %v", pattern, up)
+	}
+}
+
+func TestCompositeKeysImport(t *testing.T) {
+	localSchema, err := test_helpers.IntrospectSource(t, entitySource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source := test_helpers.GenerateSource(t, localSchema)
+
+	// Composite foreign keys are declared by the fk tag of each column
+	for _, tag := range []string{
+		"`db:\"line_no\" index:\"IDX_2CB20DC8D9F6D3895E83A0D\" fk:\"order_line\" on_delete:\"CASCADE\"`",
+		"`db:\"line_code\" index:\"IDX_2CB20DCFC58C3415EFC3D42\" fk:\"order_line:order_id,code\"`",
+	} {
+		if !strings.Contains(source, tag) {
+			t.Fatalf("expected %s in generated entity:\n%s", tag, source)
+		}
+	}
+}
+
+func TestCompositeKeysColumnsMismatch(t *testing.T) {
+	source := strings.Replace(entitySource, `fk:"order_line:order_id,code"`, `fk:"order_line:order_id"`, 1)
+
+	if _, err := test_helpers.IntrospectSource(t, source); err == nil || !strings.Contains(err.Error(), "can't reference") {
+		t.Fatalf("expected error about referenced columns, got %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package embedded_structs
+
+import (
+	"fmt"
+	"github.com/KoNekoD/gormite/pkg/assets"
+	"github.com/KoNekoD/gormite/pkg/types"
+	"github.com/KoNekoD/gormite/tests/test_helpers"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+const baseSource = `package base
+
+import "time"
+
+type Timestamps struct {
+	CreatedAt time.Time  ` + "`db:\"created_at\" index:\"idx__created_at\"`" + `
+	UpdatedAt *time.Time ` + "`db:\"updated_at\" nullable:\"true\"`" + `
+}
+`
+
+const entitySource = `package entities
+
+import (
+	"example.com/app/base"
+	"time"
+)
+
+type SoftDelete struct {
+	DeletedAt *time.Time ` + "`db:\"deleted_at\" nullable:\"true\"`" + `
+}
+
+type Audit struct {
+	SoftDelete
+	Author string ` + "`db:\"author\" length:\"64\"`" + `
+}
+
+// Post posts
+type Post struct {
+	ID    int    ` + "`db:\"id\" pk:\"true\"`" + `
+	Slug  string ` + "`db:\"slug\" uniq:\"true\"`" + `
+	Skip  string ` + "`db:\"-\"`" + `
+	Audit Audit  ` + "`db:\"*\"`" + `
+	base.Timestamps
+}
+`
+
+func introspectEntities(t *testing.T) *assets.Schema {
+	dir := test_helpers.WriteProject(
+		t,
+		map[string]string{
+			"base/timestamps.go": baseSource,
+			"entities/post.go":   entitySource,
+		},
+	)
+
+	config := fmt.Sprintf("gormite:\n  orm:\n    mapping:\n      Entities:\n        dir: %s\n", filepath.Join(dir, "entities"))
+
+	localSchema, err := test_helpers.IntrospectConfig(t, dir, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return localSchema
+}
+
+func TestEmbeddedStructs(t *testing.T) {
+	schema := introspectEntities(t)
+
+	if len(schema.GetTables()) != 1 {
+		t.Fatalf("embedded structs must not be tables, got %d tables", len(schema.GetTables()))
+	}
+
+	table := schema.GetTable("posts")
+
+	columns := make([]string, 0)
+	for _, column := range table.GetColumns() {
+		columns = append(columns, column.GetName())
+	}
+
+	expected := []string{"id", "slug", "deleted_at", "author", "created_at", "updated_at"}
+	if !slices.Equal(columns, expected) {
+		t.Fatalf("expected columns %v, got %v", expected, columns)
+	}
+
+	if _, ok := table.GetColumn("created_at").GetColumnType().(*types.DateTimeImmutableType); !ok {
+		t.Fatalf("expected datetime type of created_at, got %T", table.GetColumn("created_at").GetColumnType())
+	}
+
+	if table.GetColumn("deleted_at").GetNotNull() {
+		t.Fatal("expected nullable deleted_at")
+	}
+
+	if author := table.GetColumn("author"); author.GetLength() == nil || *author.GetLength() != 64 {
+		t.Fatalf("expected length 64 of author, got %v", author.GetLength())
+	}
+
+	if !table.HasIndex("idx__created_at") {
+		t.Fatalf("expected index of embedded column, got %v", table.GetIndexes())
+	}
+}
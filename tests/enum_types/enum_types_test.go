@@ -0,0 +1,214 @@
+package enum_types
+
+import (
+	"github.com/KoNekoD/gormite/pkg/assets"
+	"github.com/KoNekoD/gormite/pkg/diff_calc"
+	"github.com/KoNekoD/gormite/pkg/diff_dtos"
+	"github.com/KoNekoD/gormite/pkg/dtos"
+	"github.com/KoNekoD/gormite/pkg/enums"
+	"github.com/KoNekoD/gormite/pkg/platforms/postgres_platform"
+	"github.com/KoNekoD/gormite/pkg/schema_managers/postgres_schema_manager"
+	"github.com/KoNekoD/gormite/pkg/types"
+	"github.com/KoNekoD/gormite/tests/test_helpers"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const entitySource = `package entities
+
+type OrderStatus string
+
+const (
+	OrderStatusNew  OrderStatus = "new"
+	OrderStatusPaid OrderStatus = "paid"
+)
+
+type Currency string
+
+const (
+	CurrencyUSD Currency = "usd"
+	CurrencyEUR Currency = "eur"
+)
+
+// Order orders
+type Order struct {
+	ID       int         ` + "`db:\"id\" pk:\"true\"`" + `
+	Status   OrderStatus ` + "`db:\"status\" type:\"enum\"`" + `
+	Currency Currency    ` + "`db:\"currency\" type:\"enum\" enum:\"money_currency\" nullable:\"true\"`" + `
+	Note     OrderStatus ` + "`db:\"note\"`" + `
+}
+`
+
+func TestEnumTypes(t *testing.T) {
+	localSchema, err := test_helpers.IntrospectSource(t, entitySource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if values := localSchema.GetEnumType("order_status").GetValues(); !slices.Equal(values, []string{"new", "paid"}) {
+		t.Fatalf("unexpected values of order_status %v", values)
+	}
+
+	platform := postgres_platform.NewPostgreSQLPlatform()
+	up := platform.GetAlterSchemaSQL(diff_calc.NewComparator(platform).CompareSchemas(assets.NewSchema(nil, nil, nil, []string{"public"}), localSchema))
+
+	expected := []string{
+		"CREATE TYPE money_currency AS ENUM ('usd', 'eur')",
+		"CREATE TYPE order_status AS ENUM ('new', 'paid')",
+		"CREATE SEQUENCE orders__id__seq INCREMENT BY 1 MINVALUE 1 START 1",
+		"CREATE TABLE orders (id INT NOT NULL, status order_status NOT NULL, currency money_currency, note VARCHAR(255) NOT NULL, PRIMARY KEY(id))",
+	}
+	if !slices.Equal(up, expected) {
+		t.Fatalf("expected %v, got %v", expected, up)
+	}
+}
+
+func TestEnumTypesAlter(t *testing.T) {
+	source := strings.Replace(entitySource, "\tOrderStatusPaid OrderStatus = \"paid\"\n", "\tOrderStatusPaid OrderStatus = \"paid\" // renamed_from:payed\n\tOrderStatusSent OrderStatus = \"sent\"\n", 1)
+	source = strings.Replace(source, "\tOrderStatusNew  OrderStatus = \"new\"\n", "\tOrderStatusDraft OrderStatus = \"draft\"\n\tOrderStatusNew  OrderStatus = \"new\"\n", 1)
+
+	localSchema, err := test_helpers.IntrospectSource(t, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldSource := strings.Replace(entitySource, "\"paid\"", "\"payed\"", 1)
+
+	oldSchema, err := test_helpers.IntrospectSource(t, oldSource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	platform := postgres_platform.NewPostgreSQLPlatform()
+	comparator := diff_calc.NewComparator(platform)
+
+	up := platform.GetAlterSchemaSQL(comparator.CompareSchemas(oldSchema, localSchema))
+
+	expected := []string{
+		"ALTER TYPE order_status RENAME VALUE 'payed' TO 'paid'",
+		"ALTER TYPE order_status ADD VALUE 'draft' BEFORE 'new'",
+		"ALTER TYPE order_status ADD VALUE 'sent'",
+	}
+	if !slices.Equal(up, expected) {
+		t.Fatalf("expected %v, got %v", expected, up)
+	}
+
+	diff := comparator.CompareSchemas(localSchema, oldSchema)
+	down := platform.GetAlterSchemaSQL(diff)
+
+	// Postgres can not drop enum values, so the type is recreated
+	expected = []string{
+		"ALTER TYPE order_status RENAME VALUE 'paid' TO 'payed'",
+		"ALTER TYPE order_status RENAME TO order_status__old",
+		"CREATE TYPE order_status AS ENUM ('new', 'payed')",
+		"ALTER TABLE orders ALTER status TYPE order_status USING status::text::order_status",
+		"DROP TYPE order_status__old",
+	}
+	if !slices.Equal(down, expected) {
+		t.Fatalf("expected %v, got %v", expected, down)
+	}
+
+	destructive := diff_dtos.FilterChanges(diff.GetChanges(), enums.ChangeSafetyDestructive)
+	if len(destructive) != 2 {
+		t.Fatalf("expected dropped enum values to be destructive, got %v", destructive)
+	}
+}
+
+func newOrderSchema(values []string) *assets.Schema {
+	schema := assets.NewSchema(nil, nil, nil, []string{"public", "billing"})
+	schema.CreateEnumType("billing.order_status", values)
+
+	table := schema.CreateTable("billing.orders")
+	table.AddColumn("id", types.GetType(enums.TypeInteger), assets.WithColumnNotNull())
+	table.AddColumn(
+		"status",
+		types.GetType(types.GetEnumTypeName("billing.order_status")),
+		assets.WithColumnNotNull(),
+		assets.WithColumnDefault("'new'"),
+	)
+	table.AddColumn("history", types.GetType(types.GetEnumTypeName("billing.order_status")+"[]"))
+
+	return schema
+}
+
+func TestEnumTypesRecreation(t *testing.T) {
+	platform := postgres_platform.NewPostgreSQLPlatform()
+	comparator := diff_calc.NewComparator(platform)
+
+	oldSchema := newOrderSchema([]string{"new", "paid", "cancelled"})
+	newSchema := newOrderSchema([]string{"new", "sent", "paid"})
+
+	diff := comparator.CompareSchemas(oldSchema, newSchema)
+
+	// Added values are part of the recreated type, so the migration doesn't need to run without transaction
+	if diff.HasAddedEnumValues() {
+		t.Fatal("expected recreated enum type to have no values added in place")
+	}
+
+	expected := []string{
+		"ALTER TYPE billing.order_status RENAME TO order_status__old",
+		"CREATE TYPE billing.order_status AS ENUM ('new', 'sent', 'paid')",
+		"ALTER TABLE billing.orders ALTER status DROP DEFAULT",
+		"ALTER TABLE billing.orders ALTER status TYPE billing.order_status USING status::text::billing.order_status",
+		"ALTER TABLE billing.orders ALTER status SET DEFAULT 'new'",
+		"ALTER TABLE billing.orders ALTER history TYPE billing.order_status[] USING history::text[]::billing.order_status[]",
+		"DROP TYPE billing.order_status__old",
+	}
+	if up := platform.GetAlterSchemaSQL(diff); !slices.Equal(up, expected) {
+		t.Fatalf("expected %v, got %v", expected, up)
+	}
+
+	addedDiff := comparator.CompareSchemas(newOrderSchema([]string{"new"}), newOrderSchema([]string{"new", "paid"}))
+	if !addedDiff.HasAddedEnumValues() {
+		t.Fatal("expected values added in place to require migration without transaction")
+	}
+}
+
+func TestEnumTypesIntrospection(t *testing.T) {
+	platform := postgres_platform.NewPostgreSQLPlatform()
+	schemaManager := postgres_schema_manager.NewPostgreSQLSchemaManager(nil, platform)
+
+	columns := []struct {
+		dto         *dtos.SelectTableColumnsDto
+		declaration string
+	}{
+		{&dtos.SelectTableColumnsDto{Field: "status", Type: "order_status", CompleteType: "order_status", IsEnum: true}, "order_status"},
+		{&dtos.SelectTableColumnsDto{Field: "statuses", Type: "_order_status", CompleteType: "order_status[]", IsEnum: true}, "order_status[]"},
+		{&dtos.SelectTableColumnsDto{Field: "currency", Type: "money_currency", CompleteType: "billing.money_currency", IsEnum: true}, "billing.money_currency"},
+	}
+
+	for _, c := range columns {
+		column := schemaManager.GetPortableTableColumnDefinition(c.dto)
+
+		if declaration := column.GetColumnType().GetSQLDeclaration(column.ToArray(), platform); declaration != c.declaration {
+			t.Fatalf("expected %s of %s, got %s", c.declaration, c.dto.Field, declaration)
+		}
+	}
+}
+
+func TestEnumTypesImportAndSnapshot(t *testing.T) {
+	localSchema, err := test_helpers.IntrospectSource(t, entitySource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source := test_helpers.GenerateSource(t, localSchema)
+	for _, declaration := range []string{
+		"Currency *MoneyCurrency `db:\"currency\" nullable:\"true\" type:\"enum\"`",
+		"MoneyCurrencyUsd MoneyCurrency = \"usd\"",
+	} {
+		if !strings.Contains(source, declaration) {
+			t.Fatalf("expected %s in generated entity:\n%s", declaration, source)
+		}
+	}
+
+	snapshotSchema := test_helpers.SnapshotSchema(t, localSchema)
+	if !snapshotSchema.HasEnumType("order_status") {
+		t.Fatalf("expected snapshot to keep order_status")
+	}
+
+	if values := snapshotSchema.GetEnumType("order_status").GetValues(); !slices.Equal(values, []string{"new", "paid"}) {
+		t.Fatalf("expected snapshot to keep values of order_status, got %v", values)
+	}
+}
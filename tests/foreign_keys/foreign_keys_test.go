@@ -0,0 +1,122 @@
+package foreign_keys
+
+import (
+	"github.com/KoNekoD/gormite/pkg/assets"
+	"github.com/KoNekoD/gormite/pkg/diff_calc"
+	"github.com/KoNekoD/gormite/pkg/platforms/postgres_platform"
+	"github.com/KoNekoD/gormite/tests/test_helpers"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const entitySource = `package entities
+
+// Account account
+type Account struct {
+	ID string ` + "`db:\"id\" pk:\"true\" type:\"uuid\"`" + `
+}
+
+// Country country
+type Country struct {
+	ID   int64  ` + "`db:\"id\" pk:\"true\" type:\"bigint\"`" + `
+	Code string ` + "`db:\"code\" length:\"2\" uniq:\"code\"`" + `
+}
+
+// Address address
+type Address struct {
+	ID      int64    ` + "`db:\"id\" pk:\"true\" type:\"bigint\"`" + `
+	Account *Account ` + "`db:\"account_id\" on_delete:\"CASCADE\"`" + `
+	Country *Country ` + "`db:\"country_code\" references:\"code\"`" + `
+	Region  *Country ` + "`db:\"region_id\" nullable:\"true\"`" + `
+}
+`
+
+func TestForeignKeyTypes(t *testing.T) {
+	platform := postgres_platform.NewPostgreSQLPlatform()
+	comparator := diff_calc.NewComparator(platform)
+
+	localSchema, err := test_helpers.IntrospectSource(t, entitySource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	up := platform.GetAlterSchemaSQL(comparator.CompareSchemas(assets.NewSchema(nil, nil, nil, []string{"public"}), localSchema))
+
+	expected := "CREATE TABLE address (id BIGINT NOT NULL, account_id UUID NOT NULL, country_code VARCHAR(2) NOT NULL, region_id BIGINT, PRIMARY KEY(id))"
+	if !slices.Contains(up, expected) {
+		t.Fatalf("expected %s in %v", expected, up)
+	}
+
+	for _, pattern := range []string{
+		"FOREIGN KEY (account_id) REFERENCES account (id) ON DELETE CASCADE",
+		"FOREIGN KEY (country_code) REFERENCES country (code)",
+		"FOREIGN KEY (region_id) REFERENCES country (id)",
+	} {
+		if !slices.ContainsFunc(up, func(sql string) bool { return strings.Contains(sql, pattern) }) {
+			t.Fatalf("expected %s in %v", pattern, up)
+		}
+	}
+}
+
+func TestForeignKeyTypesImport(t *testing.T) {
+	localSchema, err := test_helpers.IntrospectSource(t, entitySource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source := test_helpers.GenerateSource(t, localSchema)
+
+	// Relations keep the referenced column, column types are taken from it
+	for _, tag := range []string{
+		"`db:\"account_id\" index:\"IDX_D4E6F819B6B5FBA\" on_delete:\"CASCADE\"`",
+		"`db:\"country_code\" references:\"code\" index:\"IDX_D4E6F81F026BB7C\"`",
+	} {
+		if !strings.Contains(source, tag) {
+			t.Fatalf("expected %s in generated entity:\n%s", tag, source)
+		}
+	}
+}
+
+func TestForeignKeyUnknownColumn(t *testing.T) {
+	source := strings.Replace(entitySource, `references:"code"`, `references:"iso"`, 1)
+
+	if _, err := test_helpers.IntrospectSource(t, source); err == nil || !strings.Contains(err.Error(), "column iso") {
+		t.Fatalf("expected error about unknown referenced column, got %v", err)
+	}
+}
+
+func TestForeignKeyTypesOfChainedRelations(t *testing.T) {
+	// Delivery references the profile before the type of the profile key is taken from the account
+	source := entitySource + `
+// Delivery delivery
+type Delivery struct {
+	ID      int64    ` + "`db:\"id\" pk:\"true\" type:\"bigint\"`" + `
+	Profile *Profile ` + "`db:\"profile_id\"`" + `
+}
+
+// Profile profile
+type Profile struct {
+	Account *Account ` + "`db:\"account_id\" pk:\"true\"`" + `
+}
+`
+
+	platform := postgres_platform.NewPostgreSQLPlatform()
+	comparator := diff_calc.NewComparator(platform)
+
+	localSchema, err := test_helpers.IntrospectSource(t, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	up := platform.GetAlterSchemaSQL(comparator.CompareSchemas(assets.NewSchema(nil, nil, nil, []string{"public"}), localSchema))
+
+	for _, expected := range []string{
+		"CREATE TABLE profile (account_id UUID NOT NULL, PRIMARY KEY(account_id))",
+		"CREATE TABLE delivery (id BIGINT NOT NULL, profile_id UUID NOT NULL, PRIMARY KEY(id))",
+	} {
+		if !slices.Contains(up, expected) {
+			t.Fatalf("expected %s in %v", expected, up)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package generated_columns
+
+import (
+	"github.com/KoNekoD/gormite/pkg/assets"
+	"github.com/KoNekoD/gormite/pkg/diff_calc"
+	"github.com/KoNekoD/gormite/pkg/diff_dtos"
+	"github.com/KoNekoD/gormite/pkg/dtos"
+	"github.com/KoNekoD/gormite/pkg/enums"
+	"github.com/KoNekoD/gormite/pkg/platforms/postgres_platform"
+	"github.com/KoNekoD/gormite/pkg/schema_managers/postgres_schema_manager"
+	"github.com/KoNekoD/gormite/tests/test_helpers"
+	"github.com/KoNekoD/ptrs/pkg/ptrs"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const entitySource = `package entities
+
+// OrderLine order_lines
+type OrderLine struct {
+	ID       int ` + "`db:\"id\" pk:\"true\"`" + `
+	Price    int ` + "`db:\"price\"`" + `
+	Quantity int ` + "`db:\"quantity\"`" + `
+	Total    int ` + "`db:\"total\" generated:\"price * quantity\"`" + `
+}
+`
+
+func TestGeneratedColumns(t *testing.T) {
+	localSchema, err := test_helpers.IntrospectSource(t, entitySource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	platform := postgres_platform.NewPostgreSQLPlatform()
+	up := platform.GetAlterSchemaSQL(diff_calc.NewComparator(platform).CompareSchemas(assets.NewSchema(nil, nil, nil, []string{"public"}), localSchema))
+
+	pattern := "total INT GENERATED ALWAYS AS (price * quantity) STORED NOT NULL"
+	if !slices.ContainsFunc(up, func(sql string) bool { return strings.Contains(sql, pattern) }) {
+		t.Fatalf("expected %s in %v", pattern, up)
+	}
+}
+
+func TestGeneratedColumnsAlter(t *testing.T) {
+	localSchema, err := test_helpers.IntrospectSource(t, entitySource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	platform := postgres_platform.NewPostgreSQLPlatform()
+	comparator := diff_calc.NewComparator(platform)
+
+	// Database returns the expression in parentheses, it must not produce a diff
+	sameSchema, err := test_helpers.IntrospectSource(t, strings.Replace(entitySource, "price * quantity", "(price  *  quantity)", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := comparator.CompareSchemas(sameSchema, localSchema); !diff.IsEmpty() {
+		t.Fatalf("expected no diff, got %v", platform.GetAlterSchemaSQL(diff))
+	}
+
+	changedSchema, err := test_helpers.IntrospectSource(t, strings.Replace(entitySource, "price * quantity", "price * quantity * 2", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	up := platform.GetAlterSchemaSQL(comparator.CompareSchemas(localSchema, changedSchema))
+
+	expected := []string{
+		"ALTER TABLE order_lines DROP total",
+		"ALTER TABLE order_lines ADD total INT GENERATED ALWAYS AS (price * quantity * 2) STORED NOT NULL",
+	}
+	if !slices.Equal(up, expected) {
+		t.Fatalf("expected %v, got %v", expected, up)
+	}
+
+	plainSchema, err := test_helpers.IntrospectSource(t, strings.Replace(entitySource, " generated:\"price * quantity\"", "", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	down := platform.GetAlterSchemaSQL(comparator.CompareSchemas(localSchema, plainSchema))
+	if !slices.Equal(down, []string{"ALTER TABLE order_lines ALTER total DROP EXPRESSION"}) {
+		t.Fatalf("unexpected migration to plain column %v", down)
+	}
+
+	// Plain column is recreated, so its values are lost
+	changes := comparator.CompareSchemas(plainSchema, localSchema).GetChanges()
+	if destructive := diff_dtos.FilterChanges(changes, enums.ChangeSafetyDestructive); len(destructive) != 1 {
+		t.Fatalf("expected destructive change, got %v", changes)
+	}
+}
+
+func TestGeneratedColumnsRecreationKeepsDependents(t *testing.T) {
+	source := strings.Replace(
+		entitySource,
+		"`db:\"total\" generated:\"price * quantity\"`",
+		"`db:\"total\" generated:\"price * quantity\" index:\"idx_order_lines_total\" check:\"chk_order_lines_total:(total >= 0)\"`",
+		1,
+	)
+
+	localSchema, err := test_helpers.IntrospectSource(t, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changedSchema, err := test_helpers.IntrospectSource(t, strings.Replace(source, "price * quantity", "price * quantity * 2", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	platform := postgres_platform.NewPostgreSQLPlatform()
+	up := platform.GetAlterSchemaSQL(diff_calc.NewComparator(platform).CompareSchemas(localSchema, changedSchema))
+
+	expected := []string{
+		"ALTER TABLE order_lines DROP CONSTRAINT chk_order_lines_total",
+		"DROP INDEX idx_order_lines_total",
+		"ALTER TABLE order_lines DROP total",
+		"ALTER TABLE order_lines ADD total INT GENERATED ALWAYS AS (price * quantity * 2) STORED NOT NULL",
+		"CREATE INDEX idx_order_lines_total ON order_lines (total)",
+		"ALTER TABLE order_lines ADD CONSTRAINT chk_order_lines_total CHECK (total >= 0)",
+	}
+	if !slices.Equal(up, expected) {
+		t.Fatalf("expected %v, got %v", expected, up)
+	}
+}
+
+func TestGeneratedColumnsIntrospection(t *testing.T) {
+	platform := postgres_platform.NewPostgreSQLPlatform()
+	schemaManager := postgres_schema_manager.NewPostgreSQLSchemaManager(nil, platform)
+
+	column := schemaManager.GetPortableTableColumnDefinition(
+		&dtos.SelectTableColumnsDto{
+			Field:        "total",
+			Type:         "int4",
+			CompleteType: "integer",
+			IsNotnull:    true,
+			Default:      ptrs.AsPtr("(price * quantity)"),
+			IsGenerated:  true,
+		},
+	)
+
+	if column.GetColumnDefault() != nil {
+		t.Fatalf("expected no default of generated column, got %s", *column.GetColumnDefault())
+	}
+
+	if generated := column.GetGenerated(); generated == nil || *generated != "(price * quantity)" {
+		t.Fatalf("unexpected generated expression %v", generated)
+	}
+
+	localSchema, err := test_helpers.IntrospectSource(t, entitySource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !platform.ColumnsEqual(column, localSchema.GetTable("order_lines").GetColumn("total")) {
+		t.Fatalf("expected introspected column to match the local one")
+	}
+}
+
+func TestGeneratedColumnsImportAndSnapshot(t *testing.T) {
+	localSchema, err := test_helpers.IntrospectSource(t, entitySource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source := test_helpers.GenerateSource(t, localSchema)
+	if tag := "`db:\"total\" generated:\"price * quantity\"`"; !strings.Contains(source, tag) {
+		t.Fatalf("expected %s in generated entity:\n%s", tag, source)
+	}
+
+	column := test_helpers.SnapshotSchema(t, localSchema).GetTable("order_lines").GetColumn("total")
+	if generated := column.GetGenerated(); generated == nil || *generated != "price * quantity" {
+		t.Fatalf("expected snapshot to keep generated expression, got %v", generated)
+	}
+}
+
+func TestGeneratedColumnsInvalid(t *testing.T) {
+	source := strings.Replace(entitySource, "generated:\"price * quantity\"", "generated:\"price * quantity\" default:\"0\"", 1)
+
+	if _, err := test_helpers.IntrospectSource(t, source); err == nil || !strings.Contains(err.Error(), "can't have default value") {
+		t.Fatalf("expected error about default value, got %v", err)
+	}
+}
@@ -0,0 +1,272 @@
+package id_strategy
+
+import (
+	"fmt"
+	"github.com/KoNekoD/gormite/pkg/assets"
+	"github.com/KoNekoD/gormite/pkg/diff_calc"
+	"github.com/KoNekoD/gormite/pkg/dtos"
+	"github.com/KoNekoD/gormite/pkg/enums"
+	"github.com/KoNekoD/gormite/pkg/platforms/postgres_platform"
+	"github.com/KoNekoD/gormite/pkg/schema_managers/postgres_schema_manager"
+	"github.com/KoNekoD/gormite/pkg/types"
+	"github.com/KoNekoD/gormite/tests/test_helpers"
+	"github.com/KoNekoD/ptrs/pkg/ptrs"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const entitySource = `package entities
+
+// Order orders
+type Order struct {
+	ID int ` + "`db:\"id\" pk:\"true\"`" + `
+}
+
+// Invoice invoices id_strategy:serial
+type Invoice struct {
+	ID int ` + "`db:\"id\" pk:\"true\"`" + `
+}
+
+// Payment payments id_strategy:identity
+type Payment struct {
+	ID int64 ` + "`db:\"id\" pk:\"true\"`" + `
+}
+
+// Country countries id_strategy:none
+type Country struct {
+	ID int ` + "`db:\"id\" pk:\"true\"`" + `
+}
+`
+
+func introspect(t *testing.T, source string, idStrategy string) (*assets.Schema, error) {
+	return test_helpers.Introspect(t, map[string]string{"entities.go": source}, fmt.Sprintf("    id_strategy: %s\n", idStrategy))
+}
+
+func TestIdStrategies(t *testing.T) {
+	localSchema, err := introspect(t, entitySource, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	platform := postgres_platform.NewPostgreSQLPlatform()
+	up := platform.GetAlterSchemaSQL(diff_calc.NewComparator(platform).CompareSchemas(assets.NewSchema(nil, nil, nil, []string{"public"}), localSchema))
+
+	expected := []string{
+		"CREATE SEQUENCE invoices__id__seq INCREMENT BY 1 MINVALUE 1 START 1",
+		"CREATE SEQUENCE orders__id__seq INCREMENT BY 1 MINVALUE 1 START 1",
+		"CREATE TABLE countries (id INT NOT NULL, PRIMARY KEY(id))",
+		"CREATE TABLE invoices (id INT DEFAULT nextval('invoices__id__seq'::regclass) NOT NULL, PRIMARY KEY(id))",
+		"CREATE TABLE orders (id INT NOT NULL, PRIMARY KEY(id))",
+		"CREATE TABLE payments (id BIGINT GENERATED BY DEFAULT AS IDENTITY NOT NULL, PRIMARY KEY(id))",
+	}
+
+	// Tables are created in any order
+	slices.Sort(up)
+
+	if !slices.Equal(up, expected) {
+		t.Fatalf("expected %v, got %v", expected, up)
+	}
+
+	// Global strategy is used by the tables without id_strategy comment
+	identitySchema, err := introspect(t, entitySource, "identity")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !identitySchema.GetTable("orders").GetColumn("id").GetAutoincrement() || identitySchema.HasSequence("orders__id__seq") {
+		t.Fatalf("expected identity id of orders")
+	}
+
+	if identitySchema.GetTable("countries").GetColumn("id").GetAutoincrement() {
+		t.Fatalf("expected id_strategy comment to win over the global one")
+	}
+}
+
+// createDatabaseSchema - Schema of the entities as it is introspected from the database, sequences of identity columns
+// are not listed.
+func createDatabaseSchema() *assets.Schema {
+	schema := assets.NewSchema(nil, nil, nil, []string{"public"})
+
+	for _, name := range []string{"orders", "countries"} {
+		table := schema.CreateTable(name)
+		table.AddColumn("id", types.GetType(enums.TypeInteger), assets.WithColumnNotNull())
+		table.SetPrimaryKey([]string{"id"}, ptrs.AsPtr(name+"_pkey"))
+	}
+
+	invoices := schema.CreateTable("invoices")
+	invoices.AddColumn("id", types.GetType(enums.TypeInteger), assets.WithColumnNotNull(), assets.WithColumnDefault("nextval('invoices__id__seq'::regclass)"))
+	invoices.SetPrimaryKey([]string{"id"}, ptrs.AsPtr("invoices_pkey"))
+
+	payments := schema.CreateTable("payments")
+	payments.AddColumn("id", types.GetType(enums.TypeBigint), assets.WithColumnNotNull(), assets.WithColumnAutoIncrement())
+	payments.SetPrimaryKey([]string{"id"}, ptrs.AsPtr("payments_pkey"))
+
+	schema.CreateSequence("orders__id__seq", 1, 1)
+	schema.CreateSequence("invoices__id__seq", 1, 1)
+
+	return schema
+}
+
+func TestIdStrategiesInSyncWithDatabase(t *testing.T) {
+	localSchema, err := introspect(t, entitySource, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	platform := postgres_platform.NewPostgreSQLPlatform()
+	comparator := diff_calc.NewComparator(platform)
+	databaseSchema := createDatabaseSchema()
+
+	// Sequences of the default strategy are kept, serial and identity tables don't get stray sequences
+	if up := platform.GetAlterSchemaSQL(comparator.CompareSchemas(databaseSchema, localSchema)); len(up) > 0 {
+		t.Fatalf("expected no up changes, got %v", up)
+	}
+
+	if down := platform.GetAlterSchemaSQL(comparator.CompareSchemas(localSchema, databaseSchema)); len(down) > 0 {
+		t.Fatalf("expected no down changes, got %v", down)
+	}
+}
+
+func TestIdStrategiesSwitch(t *testing.T) {
+	platform := postgres_platform.NewPostgreSQLPlatform()
+	comparator := diff_calc.NewComparator(platform)
+
+	cases := []struct {
+		from     string
+		to       string
+		expected []string
+	}{
+		{
+			from: "sequence",
+			to:   "identity",
+			expected: []string{
+				"DROP SEQUENCE orders__id__seq CASCADE",
+				"ALTER TABLE orders ALTER id ADD GENERATED BY DEFAULT AS IDENTITY",
+				"SELECT setval(pg_get_serial_sequence('orders', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM orders",
+			},
+		},
+		{
+			from: "identity",
+			to:   "serial",
+			expected: []string{
+				"CREATE SEQUENCE orders__id__seq INCREMENT BY 1 MINVALUE 1 START 1",
+				"ALTER TABLE orders ALTER id DROP IDENTITY",
+				"ALTER TABLE orders ALTER id SET DEFAULT nextval('orders__id__seq'::regclass)",
+				"SELECT setval('orders__id__seq', COALESCE(MAX(id), 0) + 1, false) FROM orders",
+			},
+		},
+		{
+			from: "serial",
+			to:   "identity",
+			expected: []string{
+				"DROP SEQUENCE orders__id__seq CASCADE",
+				"ALTER TABLE orders ALTER id DROP DEFAULT",
+				"ALTER TABLE orders ALTER id ADD GENERATED BY DEFAULT AS IDENTITY",
+				"SELECT setval(pg_get_serial_sequence('orders', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM orders",
+			},
+		},
+		{
+			from: "identity",
+			to:   "sequence",
+			expected: []string{
+				"CREATE SEQUENCE orders__id__seq INCREMENT BY 1 MINVALUE 1 START 1",
+				"ALTER TABLE orders ALTER id DROP IDENTITY",
+				"SELECT setval('orders__id__seq', COALESCE(MAX(id), 0) + 1, false) FROM orders",
+			},
+		},
+		{
+			from: "sequence",
+			to:   "serial",
+			expected: []string{
+				"ALTER TABLE orders ALTER id SET DEFAULT nextval('orders__id__seq'::regclass)",
+				"SELECT setval('orders__id__seq', COALESCE(MAX(id), 0) + 1, false) FROM orders",
+			},
+		},
+		{
+			from: "serial",
+			to:   "none",
+			expected: []string{
+				"DROP SEQUENCE orders__id__seq CASCADE",
+				"ALTER TABLE orders ALTER id DROP DEFAULT",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		oldSchema, err := introspect(t, entitySource, c.from)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		newSchema, err := introspect(t, entitySource, c.to)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if up := platform.GetAlterSchemaSQL(comparator.CompareSchemas(oldSchema, newSchema)); !slices.Equal(up, c.expected) {
+			t.Fatalf("%s to %s: expected %v, got %v", c.from, c.to, c.expected, up)
+		}
+	}
+}
+
+func TestIdStrategiesIntrospection(t *testing.T) {
+	platform := postgres_platform.NewPostgreSQLPlatform()
+	schemaManager := postgres_schema_manager.NewPostgreSQLSchemaManager(nil, platform)
+
+	localSchema, err := introspect(t, entitySource, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]*dtos.SelectTableColumnsDto{
+		"invoices": {Field: "id", Type: "int4", CompleteType: "integer", IsNotnull: true, Default: ptrs.AsPtr("nextval('invoices__id__seq'::regclass)")},
+		"payments": {Field: "id", Type: "int8", CompleteType: "bigint", IsNotnull: true, Attidentity: 'd'},
+		"orders":   {Field: "id", Type: "int4", CompleteType: "integer", IsNotnull: true},
+	}
+
+	for tableName, row := range cases {
+		column := schemaManager.GetPortableTableColumnDefinition(row)
+
+		if !platform.ColumnsEqual(column, localSchema.GetTable(tableName).GetColumn("id")) {
+			t.Fatalf("expected introspected id of %s to match the local one", tableName)
+		}
+	}
+
+	// GENERATED ALWAYS is identity too
+	always := &dtos.SelectTableColumnsDto{Field: "id", Type: "int8", CompleteType: "bigint", IsNotnull: true, Attidentity: 'a'}
+	if !schemaManager.GetPortableTableColumnDefinition(always).GetAutoincrement() {
+		t.Fatalf("expected GENERATED ALWAYS column to be identity")
+	}
+}
+
+func TestIdStrategiesImport(t *testing.T) {
+	localSchema, err := test_helpers.IntrospectSource(t, entitySource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source := test_helpers.GenerateSource(t, localSchema)
+
+	// Default sequence strategy has no hint
+	for _, comment := range []string{
+		"// Orders orders\n",
+		"// Invoices invoices id_strategy:serial\n",
+		"// Payments payments id_strategy:identity\n",
+		"// Countries countries id_strategy:none\n",
+	} {
+		if !strings.Contains(source, comment) {
+			t.Fatalf("expected %q in generated entities:\n%s", comment, source)
+		}
+	}
+}
+
+func TestIdStrategiesInvalid(t *testing.T) {
+	if _, err := introspect(t, entitySource, "uuid"); err == nil {
+		t.Fatalf("expected error of invalid global id_strategy")
+	}
+
+	if _, err := introspect(t, strings.Replace(entitySource, "id_strategy:none", "id_strategy:auto", 1), ""); err == nil {
+		t.Fatalf("expected error of invalid id_strategy comment")
+	}
+}
@@ -0,0 +1,138 @@
+package import_entities
+
+import (
+	"github.com/KoNekoD/gormite/pkg/assets"
+	"github.com/KoNekoD/gormite/pkg/diff_calc"
+	"github.com/KoNekoD/gormite/pkg/enums"
+	"github.com/KoNekoD/gormite/pkg/local_schema"
+	"github.com/KoNekoD/gormite/pkg/platforms/postgres_platform"
+	"github.com/KoNekoD/gormite/pkg/types"
+	"github.com/KoNekoD/gormite/tests/test_helpers"
+	"github.com/KoNekoD/ptrs/pkg/ptrs"
+	"strings"
+	"testing"
+)
+
+// assertImport - Generates entities of the schema and checks that they are introspected to the same schema.
+func assertImport(t *testing.T, schema *assets.Schema) {
+	t.Helper()
+
+	entities, err := local_schema.GenerateEntities(schema, "entities", &local_schema.DefaultNamingStrategy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := make(map[string]string)
+	for _, entity := range entities {
+		files[entity.FileName] = string(entity.Content)
+	}
+
+	importedSchema, err := test_helpers.Introspect(t, files, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff := diff_calc.NewComparator(postgres_platform.NewPostgreSQLPlatform()).CompareSchemas(schema, importedSchema)
+	if !diff.IsEmpty() {
+		t.Fatalf("expected generated entities to be in sync with schema:\n%s", test_helpers.GenerateSource(t, schema))
+	}
+}
+
+// createDatabaseSchema - Schema as it would be introspected from database created by gormite
+func createDatabaseSchema() *assets.Schema {
+	schema := assets.NewSchema(nil, nil, nil, []string{"public"})
+
+	length := 180
+
+	user := schema.CreateTable("app_user")
+	user.AddColumn("id", types.GetType(enums.TypeInteger), assets.WithColumnNotNull())
+	user.AddColumn("email", types.GetType(enums.TypeString), assets.WithColumnNotNull(), assets.WithColumnLength(&length))
+	user.AddColumn("full_name", types.GetType(enums.TypeString), assets.WithColumnLength(ptrs.AsPtr(255)), assets.WithColumnDefault("Anonymous"))
+	user.AddColumn("bio", types.GetType(enums.TypeText))
+	user.AddColumn("is_active", types.GetType(enums.TypeBoolean), assets.WithColumnNotNull())
+	user.AddColumn("balance", types.GetType(enums.TypeDecimal), assets.WithColumnNotNull(), assets.WithColumnPrecision(ptrs.AsPtr(10)), assets.WithColumnScale(ptrs.AsPtr(2)))
+	user.AddColumn("settings", types.GetType(enums.TypeJson)).SetPlatformOption("jsonb", ptrs.AsPtr(true))
+	user.AddColumn("created_at", types.GetType(enums.TypeDatetimeMutable), assets.WithColumnNotNull())
+	user.SetPrimaryKey([]string{"id"}, nil)
+	user.AddUniqueIndex([]string{"email"}, ptrs.AsPtr("idx__app_user__email__uniq"), map[string]any{"where": "((email)::text <> ''::text)"})
+	user.AddIndex([]string{"full_name"}, ptrs.AsPtr("index_full_name"), nil, map[string]any{"where": "(is_active = true)"})
+
+	profile := schema.CreateTable("user_profile")
+	profile.AddColumn("id", types.GetType(enums.TypeInteger), assets.WithColumnNotNull())
+	profile.AddColumn("user_id", types.GetType(enums.TypeInteger), assets.WithColumnNotNull())
+	profile.AddColumn("age", types.GetType(enums.TypeBigint))
+	profile.SetPrimaryKey([]string{"id"}, nil)
+	profile.AddForeignKeyConstraint("app_user", []string{"user_id"}, []string{"id"}, map[string]any{"onDelete": ptrs.AsPtr("CASCADE")}, nil)
+
+	schema.CreateSequence("app_user__id__seq", 1, 1)
+	schema.CreateSequence("user_profile__id__seq", 1, 1)
+
+	return schema
+}
+
+func TestGenerateEntities(t *testing.T) {
+	databaseSchema := createDatabaseSchema()
+
+	entities, err := local_schema.GenerateEntities(databaseSchema, "entities", &local_schema.DefaultNamingStrategy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entities) != 2 {
+		t.Fatalf("expected 2 entities, got %d", len(entities))
+	}
+
+	assertImport(t, databaseSchema)
+}
+
+func TestGenerateEntitiesWithNamingStrategy(t *testing.T) {
+	schema := assets.NewSchema(nil, nil, nil, []string{"public"})
+
+	invoice := schema.CreateTable("invoice")
+	invoice.AddColumn("id", types.GetType(enums.TypeInteger), assets.WithColumnNotNull())
+	invoice.AddColumn("number", types.GetType(enums.TypeString), assets.WithColumnNotNull(), assets.WithColumnLength(ptrs.AsPtr(32)))
+	invoice.SetPrimaryKey([]string{"id"}, ptrs.AsPtr("pk_invoice"))
+	invoice.AddUniqueIndex([]string{"number"}, ptrs.AsPtr("uq_invoice_number"), nil)
+
+	schema.CreateSequence("invoice_id_seq", 1, 1)
+
+	entities, err := local_schema.GenerateEntities(schema, "entities", &local_schema.PrefixedNamingStrategy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entities) != 1 {
+		t.Fatalf("expected 1 entity, got %d", len(entities))
+	}
+
+	// Sequence of the prefixed strategy exists, so the default sequence strategy is kept
+	if content := string(entities[0].Content); strings.Contains(content, "id_strategy") {
+		t.Fatalf("expected no id strategy, got:\n%s", content)
+	}
+}
+
+func TestGenerateEntitiesKeepsIndexColumnOrder(t *testing.T) {
+	schema := assets.NewSchema(nil, nil, nil, []string{"public"})
+
+	post := schema.CreateTable("post")
+	post.AddColumn("id", types.GetType(enums.TypeInteger), assets.WithColumnNotNull())
+	post.AddColumn("a", types.GetType(enums.TypeInteger), assets.WithColumnNotNull())
+	post.AddColumn("b", types.GetType(enums.TypeInteger), assets.WithColumnNotNull())
+	post.SetPrimaryKey([]string{"id"}, ptrs.AsPtr("post_pkey"))
+	post.AddIndex([]string{"b", "a"}, ptrs.AsPtr("idx_post_b_a"), nil, nil)
+	post.AddIndex([]string{"a", "b"}, ptrs.AsPtr("idx_post_a_b"), nil, nil)
+	post.AddUniqueIndex([]string{"b", "a"}, ptrs.AsPtr("idx__post__b_a__uniq"), nil)
+
+	schema.CreateSequence("post__id__seq", 1, 1)
+
+	source := test_helpers.GenerateSource(t, schema)
+	if tag := "idx_post_b_a:2"; !strings.Contains(source, tag) {
+		t.Fatalf("expected %s in generated entity:\n%s", tag, source)
+	}
+
+	if strings.Contains(source, "idx_post_a_b:") {
+		t.Fatalf("expected no positions of the index in the order of the fields:\n%s", source)
+	}
+
+	assertImport(t, schema)
+}
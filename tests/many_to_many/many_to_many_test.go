@@ -0,0 +1,125 @@
+package many_to_many
+
+import (
+	"github.com/KoNekoD/gormite/pkg/assets"
+	"github.com/KoNekoD/gormite/pkg/diff_calc"
+	"github.com/KoNekoD/gormite/pkg/platforms/postgres_platform"
+	"github.com/KoNekoD/gormite/tests/test_helpers"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const entitySource = `package entities
+
+// Post posts
+type Post struct {
+	ID   int    ` + "`db:\"id\" pk:\"true\"`" + `
+	Tags []*Tag ` + "`m2m:\"post_tag\"`" + `
+}
+
+// Tag tags
+type Tag struct {
+	ID    int     ` + "`db:\"id\" pk:\"true\"`" + `
+	Posts []*Post ` + "`m2m:\"post_tag\"`" + `
+}
+
+// User users
+type User struct {
+	ID      int     ` + "`db:\"id\" pk:\"true\"`" + `
+	Friends []*User ` + "`m2m:\"user_friend\" m2m_columns:\"user_id,friend_id\" on_delete:\"RESTRICT\"`" + `
+}
+`
+
+func TestManyToMany(t *testing.T) {
+	platform := postgres_platform.NewPostgreSQLPlatform()
+	comparator := diff_calc.NewComparator(platform)
+
+	localSchema, err := test_helpers.IntrospectSource(t, entitySource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	postTag := localSchema.GetTable("post_tag")
+	if !slices.Equal(postTag.GetPrimaryKey().GetColumns(), []string{"post_id", "tag_id"}) {
+		t.Fatalf("expected primary key of post_id and tag_id, got %v", postTag.GetPrimaryKey().GetColumns())
+	}
+
+	for _, fk := range postTag.GetForeignKeys() {
+		if onDelete := fk.OnDelete(); onDelete == nil || *onDelete != "CASCADE" {
+			t.Fatalf("expected cascade delete of %s, got %v", fk.GetName(), onDelete)
+		}
+	}
+
+	diff := comparator.CompareSchemas(assets.NewSchema(nil, nil, nil, []string{"public"}), localSchema)
+	up := platform.GetAlterSchemaSQL(diff)
+
+	for _, sql := range []string{
+		"CREATE TABLE post_tag (post_id INT NOT NULL, tag_id INT NOT NULL, PRIMARY KEY(post_id, tag_id))",
+		"CREATE TABLE user_friend (user_id INT NOT NULL, friend_id INT NOT NULL, PRIMARY KEY(user_id, friend_id))",
+	} {
+		if !slices.Contains(up, sql) {
+			t.Fatalf("expected %s in %v", sql, up)
+		}
+	}
+
+	for _, pattern := range []string{
+		"CREATE INDEX IDX_%s ON post_tag (post_id)",
+		"CREATE INDEX IDX_%s ON post_tag (tag_id)",
+		"ALTER TABLE post_tag ADD CONSTRAINT FK_%s FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE",
+		"ALTER TABLE post_tag ADD CONSTRAINT FK_%s FOREIGN KEY (tag_id) REFERENCES tags (id) ON DELETE CASCADE",
+		"ALTER TABLE user_friend ADD CONSTRAINT FK_%s FOREIGN KEY (friend_id) REFERENCES users (id) ON DELETE RESTRICT",
+	} {
+		before, after, _ := strings.Cut(pattern, "%s")
+
+		if !slices.ContainsFunc(up, func(sql string) bool {
+			return strings.HasPrefix(sql, before) && strings.Contains(sql, after)
+		}) {
+			t.Fatalf("expected %s in %v", pattern, up)
+		}
+	}
+
+	if slices.ContainsFunc(up, func(sql string) bool { return strings.Contains(sql, "post_tag__id__seq") }) {
+		t.Fatalf("join table must not have sequence, got %v", up)
+	}
+
+	if diff := comparator.CompareSchemas(localSchema, localSchema); !diff.IsEmpty() {
+		t.Fatalf("expected empty diff, got %v", platform.GetAlterSchemaSQL(diff))
+	}
+}
+
+func TestManyToManySameColumns(t *testing.T) {
+	source := `package entities
+
+// User users
+type User struct {
+	ID      int     ` + "`db:\"id\" pk:\"true\"`" + `
+	Friends []*User ` + "`m2m:\"user_friend\"`" + `
+}
+`
+
+	if _, err := test_helpers.IntrospectSource(t, source); err == nil || !strings.Contains(err.Error(), "m2m_columns") {
+		t.Fatalf("expected error about m2m_columns tag, got %v", err)
+	}
+}
+
+func TestManyToManyConflictingSides(t *testing.T) {
+	cases := map[string]string{
+		"on_delete": `m2m:"post_tag" on_delete:"RESTRICT"`,
+		"on_update": `m2m:"post_tag" on_update:"CASCADE"`,
+		"columns":   `m2m:"post_tag" m2m_columns:"tag_id,article_id"`,
+		"swapped":   `m2m:"post_tag" m2m_columns:"post_id,tag_id"`,
+	}
+
+	for name, tag := range cases {
+		// Tags side declares the join table of the posts side differently
+		source := strings.Replace(entitySource, "Posts []*Post `m2m:\"post_tag\"`", "Posts []*Post `"+tag+"`", 1)
+		if source == entitySource {
+			t.Fatalf("%s: expected tag of the tags side to be replaced", name)
+		}
+
+		if _, err := test_helpers.IntrospectSource(t, source); err == nil || !strings.Contains(err.Error(), "post_tag") {
+			t.Fatalf("%s: expected error about join table post_tag, got %v", name, err)
+		}
+	}
+}
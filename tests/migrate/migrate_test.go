@@ -0,0 +1,297 @@
+package migrate
+
+import (
+	"bytes"
+	"context"
+	gdh "github.com/KoNekoD/gormite/pkg/gormite_databases_helpers"
+	"github.com/KoNekoD/gormite/pkg/runners"
+	"github.com/charmbracelet/log"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pkg/errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestReadMigrations(t *testing.T) {
+	migrations, err := runners.ReadMigrations("migrations")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(migrations) != 2 {
+		t.Fatalf("expected 2 migrations, got %d", len(migrations))
+	}
+
+	goose, migrate := migrations[0], migrations[1]
+
+	if goose.Version != "20250204121625" || migrate.Version != "20250205100000" {
+		t.Fatalf("unexpected migrations order: %s, %s", goose.Version, migrate.Version)
+	}
+
+	if strings.Contains(goose.Up, "+goose") || !strings.Contains(goose.Up, "CREATE TABLE player") {
+		t.Fatalf("unexpected goose up sql: %s", goose.Up)
+	}
+
+	if strings.Contains(goose.Down, "CREATE TABLE") || !strings.Contains(goose.Down, "DROP TABLE player") {
+		t.Fatalf("unexpected goose down sql: %s", goose.Down)
+	}
+
+	if !strings.Contains(migrate.Up, "ADD nickname") || !strings.Contains(migrate.Down, "DROP nickname") {
+		t.Fatalf("unexpected migrate sql: %s / %s", migrate.Up, migrate.Down)
+	}
+}
+
+// fakeDatabase - Records executed sql and keeps applied versions in memory, failed transaction restores them.
+type fakeDatabase struct {
+	applied      []string
+	executed     []string
+	failOn       string
+	transactions int
+}
+
+func (d *fakeDatabase) Select(sql string, args ...any) gdh.QueryInterface { return nil }
+
+func (d *fakeDatabase) Get(sql string, args ...any) gdh.QueryInterface { return nil }
+
+func (d *fakeDatabase) GetNamedArgs(args any) any { return args }
+
+func (d *fakeDatabase) Exec(ctx context.Context, sql string, args ...any) (gdh.CommandTag, error) {
+	if d.failOn != "" && strings.Contains(sql, d.failOn) {
+		return nil, errors.New("syntax error")
+	}
+
+	d.executed = append(d.executed, sql)
+
+	switch {
+	case strings.HasPrefix(sql, "INSERT INTO "+runners.MigrationsTableName):
+		d.applied = append(d.applied, args[0].(string))
+	case strings.HasPrefix(sql, "DELETE FROM "+runners.MigrationsTableName):
+		d.applied = slices.DeleteFunc(d.applied, func(v string) bool { return v == args[0].(string) })
+	}
+
+	return pgconn.NewCommandTag(""), nil
+}
+
+func (d *fakeDatabase) Query(ctx context.Context, sql string, args ...any) (gdh.Rows, error) {
+	return &fakeRows{values: slices.Clone(d.applied)}, nil
+}
+
+func (d *fakeDatabase) WrapInTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	d.transactions++
+	applied, executed := slices.Clone(d.applied), slices.Clone(d.executed)
+
+	if err := fn(ctx); err != nil {
+		d.applied, d.executed = applied, executed
+		return err
+	}
+
+	return nil
+}
+
+type fakeRows struct {
+	values []string
+	i      int
+}
+
+func (r *fakeRows) Next() bool {
+	r.i++
+	return r.i <= len(r.values)
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	*dest[0].(*string) = r.values[r.i-1]
+	return nil
+}
+
+func runMigrate(t *testing.T, db *fakeDatabase, action string, steps int) error {
+	t.Helper()
+
+	opts := runners.MigrateRunnerOptions{Dir: "migrations", Action: action, Steps: steps}
+
+	return runners.NewMigrateRunner(opts).RunWithDatabase(context.Background(), db)
+}
+
+func TestMigrateUp(t *testing.T) {
+	db := &fakeDatabase{}
+
+	if err := runMigrate(t, db, runners.MigrateActionUp, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(db.applied, []string{"20250204121625", "20250205100000"}) {
+		t.Fatalf("expected both migrations applied, got %v", db.applied)
+	}
+
+	if !slices.ContainsFunc(db.executed, func(sql string) bool { return strings.Contains(sql, "CREATE TABLE player") }) {
+		t.Fatalf("expected goose up sql executed, got %v", db.executed)
+	}
+
+	executed := len(db.executed)
+	if err := runMigrate(t, db, runners.MigrateActionUp, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the migrations table is created again
+	if len(db.executed) != executed+1 {
+		t.Fatalf("expected applied migrations to be skipped, got %v", db.executed[executed:])
+	}
+}
+
+func TestMigrateUpFailureIsRolledBack(t *testing.T) {
+	db := &fakeDatabase{failOn: "ADD nickname"}
+
+	err := runMigrate(t, db, runners.MigrateActionUp, 0)
+	if err == nil || !strings.Contains(err.Error(), "20250205100000_gen") {
+		t.Fatalf("expected error of the second migration, got %v", err)
+	}
+
+	if !slices.Equal(db.applied, []string{"20250204121625"}) {
+		t.Fatalf("expected only the first migration to be recorded, got %v", db.applied)
+	}
+}
+
+const noTransactionMigration = `-- +goose NO TRANSACTION
+-- +goose Up
+ALTER TYPE order_status ADD VALUE 'sent';
+-- +goose StatementBegin
+CREATE FUNCTION touch() RETURNS trigger AS $$
+BEGIN
+    NEW.updated_at = now();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP FUNCTION touch;
+`
+
+func TestMigrateUpNoTransaction(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "20250301000000_gen.sql"), []byte(noTransactionMigration), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	migrations, err := runners.ReadMigrations(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !migrations[0].NoTransaction || len(migrations[0].UpStatements) != 2 {
+		t.Fatalf("expected migration without transaction with 2 statements, got %+v", migrations[0])
+	}
+
+	db := &fakeDatabase{}
+
+	opts := runners.MigrateRunnerOptions{Dir: dir, Action: runners.MigrateActionUp}
+	if err := runners.NewMigrateRunner(opts).RunWithDatabase(context.Background(), db); err != nil {
+		t.Fatal(err)
+	}
+
+	if db.transactions != 0 {
+		t.Fatalf("expected migration to be applied without transaction, got %d transactions", db.transactions)
+	}
+
+	// Migrations table, two statements and the version record
+	if len(db.executed) != 4 || db.executed[1] != "ALTER TYPE order_status ADD VALUE 'sent';" {
+		t.Fatalf("expected statements to be executed one by one, got %v", db.executed)
+	}
+
+	if !slices.Equal(db.applied, []string{"20250301000000"}) {
+		t.Fatalf("expected migration to be recorded, got %v", db.applied)
+	}
+}
+
+func TestMigrateDown(t *testing.T) {
+	db := &fakeDatabase{applied: []string{"20250204121625", "20250205100000"}}
+
+	if err := runMigrate(t, db, runners.MigrateActionDown, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(db.applied, []string{"20250204121625"}) {
+		t.Fatalf("expected the last migration reverted, got %v", db.applied)
+	}
+
+	if !slices.ContainsFunc(db.executed, func(sql string) bool { return strings.Contains(sql, "DROP nickname") }) {
+		t.Fatalf("expected down sql executed, got %v", db.executed)
+	}
+
+	if err := runMigrate(t, db, runners.MigrateActionDown, 5); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(db.applied) != 0 {
+		t.Fatalf("expected all migrations reverted, got %v", db.applied)
+	}
+}
+
+func TestMigrateDownMissingFile(t *testing.T) {
+	db := &fakeDatabase{applied: []string{"20250204121625", "20990101000000"}}
+
+	if err := runMigrate(t, db, runners.MigrateActionDown, 1); err == nil || !strings.Contains(err.Error(), "20990101000000") {
+		t.Fatalf("expected error about missing migration file, got %v", err)
+	}
+}
+
+func TestMigrateStatus(t *testing.T) {
+	var output bytes.Buffer
+	log.SetOutput(&output)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	db := &fakeDatabase{applied: []string{"20250204121625", "20990101000000"}}
+
+	if err := runMigrate(t, db, runners.MigrateActionStatus, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{"applied  20250204121625_gen", "pending  20250205100000_gen", "missing  20990101000000"} {
+		if !strings.Contains(output.String(), expected) {
+			t.Fatalf("expected %q in status output:\n%s", expected, output.String())
+		}
+	}
+}
+
+func TestParseMigrateSteps(t *testing.T) {
+	if steps, err := runners.ParseMigrateSteps("3"); err != nil || steps != 3 {
+		t.Fatalf("expected 3 steps, got %d, %v", steps, err)
+	}
+
+	for _, value := range []string{"abc", "0", "-1"} {
+		if _, err := runners.ParseMigrateSteps(value); err == nil {
+			t.Fatalf("expected error for %s steps", value)
+		}
+	}
+}
+
+func TestIsMigrateStepsArg(t *testing.T) {
+	for _, value := range []string{"3", "abc", "0", "-1", "-2.5"} {
+		if !runners.IsMigrateStepsArg(value) {
+			t.Fatalf("expected %s to be validated as steps", value)
+		}
+	}
+
+	for _, value := range []string{"-d", "--dir", "-dsn=postgres://"} {
+		if runners.IsMigrateStepsArg(value) {
+			t.Fatalf("expected %s to be a flag", value)
+		}
+	}
+}
+
+func TestValidateFileNameTemplate(t *testing.T) {
+	for _, template := range []string{runners.DefaultFileNameTemplate, "{version}_add_users", "{version}_{version}"} {
+		if err := runners.ValidateFileNameTemplate(template); err != nil {
+			t.Fatalf("expected %s to be valid, got %v", template, err)
+		}
+	}
+
+	// Files named by these templates are skipped by migrate
+	for _, template := range []string{"gen_{version}", "{version}", "{version}gen", "v{version}_gen", "{version}_gen/up"} {
+		if err := runners.ValidateFileNameTemplate(template); err == nil {
+			t.Fatalf("expected error for %s template", template)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- THIS FILE WAS GENERATED BY GORMITE, EDIT IT IF YOU WANT <3
+
+CREATE TABLE player (id INT NOT NULL, PRIMARY KEY(id));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- THIS FILE WAS GENERATED BY GORMITE, EDIT IT IF YOU WANT <3
+
+DROP TABLE player;
+-- +goose StatementEnd
@@ -0,0 +1,3 @@
+-- THIS FILE WAS GENERATED BY GORMITE, EDIT IT IF YOU WANT <3
+
+ALTER TABLE player DROP nickname;
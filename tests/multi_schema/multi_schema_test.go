@@ -0,0 +1,121 @@
+package multi_schema
+
+import (
+	"fmt"
+	"github.com/KoNekoD/gormite/pkg/assets"
+	"github.com/KoNekoD/gormite/pkg/diff_calc"
+	"github.com/KoNekoD/gormite/pkg/platforms/postgres_platform"
+	"github.com/KoNekoD/gormite/pkg/schema_snapshots"
+	"github.com/KoNekoD/gormite/tests/test_helpers"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const userSource = `package entities
+
+// User app_user
+type User struct {
+	ID int ` + "`db:\"id\" pk:\"true\"`" + `
+}
+
+// AuditLog audit.log
+type AuditLog struct {
+	ID      int    ` + "`db:\"id\" pk:\"true\"`" + `
+	Message string ` + "`db:\"message\" uniq:\"message\"`" + `
+}
+`
+
+const invoiceSource = `package billing
+
+import "example.com/app/entities"
+
+type Invoice struct {
+	ID    int            ` + "`db:\"id\" pk:\"true\"`" + `
+	Owner *entities.User ` + "`db:\"owner_id\" nullable:\"true\"`" + `
+}
+`
+
+func introspectEntities(t *testing.T) *assets.Schema {
+	dir := test_helpers.WriteProject(t, map[string]string{"entities/user.go": userSource, "billing/invoice.go": invoiceSource})
+
+	config := fmt.Sprintf(
+		"gormite:\n  orm:\n    mapping:\n      Entities:\n        dir: %s\n      Billing:\n        dir: %s\n        schema: billing\n",
+		filepath.Join(dir, "entities"),
+		filepath.Join(dir, "billing"),
+	)
+
+	localSchema, err := test_helpers.IntrospectConfig(t, dir, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return localSchema
+}
+
+func TestMultiSchema(t *testing.T) {
+	platform := postgres_platform.NewPostgreSQLPlatform()
+	comparator := diff_calc.NewComparator(platform)
+
+	localSchema := introspectEntities(t)
+
+	for _, name := range []string{"app_user", "billing.invoice", "audit.log"} {
+		if !localSchema.HasTable(name) {
+			t.Fatalf("expected table %s", name)
+		}
+	}
+
+	databaseSchema := assets.NewSchema(nil, nil, nil, []string{"public"})
+	diff := comparator.CompareSchemas(databaseSchema, localSchema)
+
+	createdSchemas := diff.GetCreatedSchemas()
+	slices.Sort(createdSchemas)
+	if !slices.Equal(createdSchemas, []string{"audit", "billing"}) {
+		t.Fatalf("expected audit and billing schemas to be created, got %v", createdSchemas)
+	}
+
+	up := platform.GetAlterSchemaSQL(diff)
+	for _, sql := range []string{
+		"CREATE SCHEMA audit",
+		"CREATE SCHEMA billing",
+		"CREATE SEQUENCE billing.invoice__id__seq INCREMENT BY 1 MINVALUE 1 START 1",
+		"CREATE TABLE audit.log (id INT NOT NULL, message VARCHAR(255) NOT NULL, PRIMARY KEY(id))",
+		"CREATE UNIQUE INDEX idx__log__message__uniq ON audit.log (message)",
+		"CREATE TABLE billing.invoice (id INT NOT NULL, owner_id INT, PRIMARY KEY(id))",
+		"CREATE TABLE app_user (id INT NOT NULL, PRIMARY KEY(id))",
+	} {
+		if !slices.Contains(up, sql) {
+			t.Fatalf("expected %s in %v", sql, up)
+		}
+	}
+
+	if !slices.ContainsFunc(up, func(sql string) bool { return strings.HasPrefix(sql, "ALTER TABLE billing.invoice ADD CONSTRAINT") }) {
+		t.Fatalf("expected foreign key on billing.invoice in %v", up)
+	}
+
+	if slices.IndexFunc(up, func(sql string) bool { return strings.HasPrefix(sql, "CREATE TABLE") }) <
+		slices.Index(up, "CREATE SCHEMA billing") {
+		t.Fatalf("schemas must be created before tables, got %v", up)
+	}
+}
+
+func TestMultiSchemaInSync(t *testing.T) {
+	comparator := diff_calc.NewComparator(postgres_platform.NewPostgreSQLPlatform())
+
+	localSchema := introspectEntities(t)
+
+	content, err := schema_snapshots.Marshal(localSchema, schema_snapshots.FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restoredSchema, err := schema_snapshots.Unmarshal(content, schema_snapshots.FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := comparator.CompareSchemas(restoredSchema, localSchema); !diff.IsEmpty() {
+		t.Fatalf("expected empty diff, got %v", diff.GetChanges())
+	}
+}
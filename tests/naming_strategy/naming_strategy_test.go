@@ -0,0 +1,173 @@
+package naming_strategy
+
+import (
+	"github.com/KoNekoD/gormite/pkg/assets"
+	"github.com/KoNekoD/gormite/pkg/diff_calc"
+	"github.com/KoNekoD/gormite/pkg/dtos"
+	"github.com/KoNekoD/gormite/pkg/enums"
+	"github.com/KoNekoD/gormite/pkg/local_schema"
+	"github.com/KoNekoD/gormite/pkg/platforms/postgres_platform"
+	"github.com/KoNekoD/gormite/pkg/types"
+	"github.com/KoNekoD/gormite/tests/test_helpers"
+	"github.com/KoNekoD/ptrs/pkg/ptrs"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const entitySource = `package entities
+
+type User struct {
+	ID    int    ` + "`db:\"id\" pk:\"true\"`" + `
+	Email string ` + "`db:\"email\" uniq:\"email\"`" + `
+	Name  string ` + "`db:\"name\" index:\"users_name\"`" + `
+}
+
+type OrderItem struct {
+	ID   int   ` + "`db:\"id\" pk:\"true\"`" + `
+	User *User ` + "`db:\"user_id\"`" + `
+}
+
+// Category category
+type Category struct {
+	ID int ` + "`db:\"id\" pk:\"true\"`" + `
+}
+`
+
+func introspect(t *testing.T, naming string) (*assets.Schema, error) {
+	return test_helpers.Introspect(t, map[string]string{"entities.go": entitySource}, naming)
+}
+
+func getNames(schema *assets.Schema) []string {
+	names := make([]string, 0)
+
+	for _, table := range schema.GetTables() {
+		names = append(names, "table "+table.GetName())
+
+		for _, index := range table.GetIndexes() {
+			names = append(names, "index "+index.GetName())
+		}
+
+		for _, fk := range table.GetForeignKeys() {
+			names = append(names, "fk "+fk.GetName())
+		}
+	}
+
+	for _, sequence := range schema.GetSequences() {
+		names = append(names, "sequence "+sequence.GetName())
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+func TestDefaultNamingStrategy(t *testing.T) {
+	schema, err := introspect(t, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := getNames(schema)
+
+	for _, name := range []string{
+		"table user",
+		"table order_item",
+		"table category",
+		"index user_pkey",
+		"index idx__user__email__uniq",
+		"index users_name",
+		"sequence user__id__seq",
+	} {
+		if !slices.Contains(names, name) {
+			t.Fatalf("expected %s in %v", name, names)
+		}
+	}
+
+	// Foreign key name is generated from hash
+	if !slices.ContainsFunc(names, func(name string) bool { return strings.HasPrefix(name, "fk FK_") }) {
+		t.Fatalf("expected generated foreign key name in %v", names)
+	}
+}
+
+func TestDefaultNamingStrategyKeepsBaselineNames(t *testing.T) {
+	source := `package entities
+
+type Invoice struct {
+	Number int ` + "`db:\"number\" pk:\"true\"`" + `
+}
+`
+
+	schema, err := test_helpers.Introspect(t, map[string]string{"entities.go": source}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Schema created by the earlier versions names the id sequence by the table only
+	baselineSchema := assets.NewSchema(nil, nil, nil, []string{"public"})
+	baselineSchema.CreateSequence("invoice__id__seq", 1, 1)
+	invoice := baselineSchema.CreateTable("invoice")
+	invoice.AddColumn("number", types.GetType(enums.TypeInteger), assets.WithColumnNotNull())
+	invoice.SetPrimaryKey([]string{"number"}, ptrs.AsPtr("invoice_pkey"))
+
+	diff := diff_calc.NewComparator(postgres_platform.NewPostgreSQLPlatform()).CompareSchemas(baselineSchema, schema)
+	if !diff.IsEmpty() {
+		t.Fatalf("expected no changes against baseline names, got %v", diff.GetChanges())
+	}
+}
+
+func TestPrefixedNamingStrategy(t *testing.T) {
+	schema, err := introspect(t, "    naming:\n      strategy: prefixed\n      plural: true\n      table_prefix: app_\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"fk fk_app_order_items_user_id",
+		"index ix_app_order_items_user_id",
+		"index ix_users_name",
+		"index pk_app_order_items",
+		"index pk_app_users",
+		"index pk_category",
+		"index uq_app_users_email",
+		"sequence app_order_items_id_seq",
+		"sequence app_users_id_seq",
+		"sequence category_id_seq",
+		"table app_order_items",
+		"table app_users",
+		"table category",
+	}
+
+	if names := getNames(schema); !slices.Equal(names, expected) {
+		t.Fatalf("expected %v, got %v", expected, names)
+	}
+}
+
+type upperNamingStrategy struct {
+	local_schema.DefaultNamingStrategy
+}
+
+func (s *upperNamingStrategy) TableName(structName string) string {
+	return "T_" + strings.ToUpper(structName)
+}
+
+func TestRegisterNamingStrategy(t *testing.T) {
+	local_schema.RegisterNamingStrategy(
+		"upper", func(config dtos.ConfigDataNaming) local_schema.NamingStrategy {
+			return &upperNamingStrategy{}
+		},
+	)
+
+	schema, err := introspect(t, "    naming:\n      strategy: upper\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !schema.HasTable("T_USER") || !schema.HasTable("T_ORDERITEM") {
+		t.Fatalf("expected tables named by the registered strategy, got %v", getNames(schema))
+	}
+
+	if _, err := introspect(t, "    naming:\n      strategy: unknown\n"); err == nil {
+		t.Fatalf("expected error of unknown naming strategy")
+	}
+}
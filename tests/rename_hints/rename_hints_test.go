@@ -0,0 +1,178 @@
+package rename_hints
+
+import (
+	"github.com/KoNekoD/gormite/pkg/assets"
+	"github.com/KoNekoD/gormite/pkg/diff_calc"
+	"github.com/KoNekoD/gormite/pkg/diff_dtos"
+	"github.com/KoNekoD/gormite/pkg/enums"
+	"github.com/KoNekoD/gormite/pkg/local_schema"
+	"github.com/KoNekoD/gormite/pkg/platforms/postgres_platform"
+	"github.com/KoNekoD/gormite/pkg/types"
+	"github.com/KoNekoD/gormite/tests/test_helpers"
+	"github.com/KoNekoD/ptrs/pkg/ptrs"
+	"slices"
+	"testing"
+)
+
+const entitySource = `package entities
+
+// User app_user renamed_from:users
+type User struct {
+	ID       int    ` + "`db:\"id\" pk:\"true\"`" + `
+	FullName string ` + "`db:\"full_name\" renamed_from:\"name\"`" + `
+	Email    string ` + "`db:\"email\" renamed_from:\"mail\" length:\"180\"`" + `
+}
+`
+
+func createDatabaseSchema() *assets.Schema {
+	schema := assets.NewSchema(nil, nil, nil, []string{"public"})
+
+	user := schema.CreateTable("users")
+	user.AddColumn("id", types.GetType(enums.TypeInteger), assets.WithColumnNotNull())
+	user.AddColumn("name", types.GetType(enums.TypeString), assets.WithColumnNotNull(), assets.WithColumnLength(ptrs.AsPtr(255)))
+	user.AddColumn("mail", types.GetType(enums.TypeString), assets.WithColumnNotNull(), assets.WithColumnLength(ptrs.AsPtr(255)))
+	user.SetPrimaryKey([]string{"id"}, nil)
+
+	schema.CreateSequence("users__id__seq", 1, 1)
+
+	return schema
+}
+
+func introspectEntities(t *testing.T) *assets.Schema {
+	localSchema, err := test_helpers.IntrospectSource(t, entitySource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return localSchema
+}
+
+func TestRenameHints(t *testing.T) {
+	platform := postgres_platform.NewPostgreSQLPlatform()
+	comparator := diff_calc.NewComparator(platform)
+
+	databaseSchema := createDatabaseSchema()
+	localSchema := introspectEntities(t)
+
+	upDiff := comparator.CompareSchemas(databaseSchema, localSchema)
+	if len(upDiff.GetCreatedTables()) != 0 || len(upDiff.GetDroppedTables()) != 0 {
+		t.Fatal("renamed table must not be dropped and created")
+	}
+
+	up := platform.GetAlterSchemaSQL(upDiff)
+	expectedUp := []string{
+		"ALTER SEQUENCE users__id__seq RENAME TO app_user__id__seq",
+		"ALTER TABLE users RENAME COLUMN name TO full_name",
+		"ALTER TABLE users RENAME COLUMN mail TO email",
+		"ALTER TABLE users ALTER email TYPE VARCHAR(180)",
+		"ALTER TABLE users RENAME TO app_user",
+	}
+	assertSQL(t, up, expectedUp)
+
+	if up[len(up)-1] != "ALTER TABLE users RENAME TO app_user" {
+		t.Fatalf("table must be renamed after columns, got %v", up)
+	}
+
+	if destructive := diff_dtos.FilterChanges(upDiff.GetChanges(), enums.ChangeSafetyDestructive); len(destructive) > 0 {
+		t.Fatalf("expected no destructive changes, got %v", destructive)
+	}
+
+	// Hints of the local schema are applied in reverse for the down migration
+	down := platform.GetAlterSchemaSQL(comparator.CompareSchemas(localSchema, databaseSchema))
+	expectedDown := []string{
+		"ALTER SEQUENCE app_user__id__seq RENAME TO users__id__seq",
+		"ALTER TABLE app_user RENAME COLUMN full_name TO name",
+		"ALTER TABLE app_user RENAME COLUMN email TO mail",
+		"ALTER TABLE app_user ALTER mail TYPE VARCHAR(255)",
+		"ALTER TABLE app_user RENAME TO users",
+	}
+	assertSQL(t, down, expectedDown)
+}
+
+func TestRenameHintsAlreadyApplied(t *testing.T) {
+	platform := postgres_platform.NewPostgreSQLPlatform()
+	comparator := diff_calc.NewComparator(platform)
+
+	localSchema := introspectEntities(t)
+
+	databaseSchema := assets.NewSchema(nil, nil, nil, []string{"public"})
+	user := databaseSchema.CreateTable("app_user")
+	user.AddColumn("id", types.GetType(enums.TypeInteger), assets.WithColumnNotNull())
+	user.AddColumn("full_name", types.GetType(enums.TypeString), assets.WithColumnNotNull(), assets.WithColumnLength(ptrs.AsPtr(255)))
+	user.AddColumn("email", types.GetType(enums.TypeString), assets.WithColumnNotNull(), assets.WithColumnLength(ptrs.AsPtr(180)))
+	user.SetPrimaryKey([]string{"id"}, nil)
+	databaseSchema.CreateSequence("app_user__id__seq", 1, 1)
+
+	diff := comparator.CompareSchemas(databaseSchema, localSchema)
+	if !diff.IsEmpty() {
+		t.Fatalf("expected empty diff, got %v", platform.GetAlterSchemaSQL(diff))
+	}
+}
+
+func TestRenameHintsWithNamingStrategy(t *testing.T) {
+	platform := postgres_platform.NewPostgreSQLPlatform()
+	comparator := diff_calc.NewComparator(platform).SetSequenceNaming(&local_schema.PrefixedNamingStrategy{})
+
+	localSchema, err := test_helpers.Introspect(
+		t,
+		map[string]string{"entities.go": entitySource},
+		"    naming:\n      strategy: prefixed\n",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	databaseSchema := assets.NewSchema(nil, nil, nil, []string{"public"})
+	user := databaseSchema.CreateTable("users")
+	user.AddColumn("id", types.GetType(enums.TypeInteger), assets.WithColumnNotNull())
+	user.AddColumn("full_name", types.GetType(enums.TypeString), assets.WithColumnNotNull(), assets.WithColumnLength(ptrs.AsPtr(255)))
+	user.AddColumn("email", types.GetType(enums.TypeString), assets.WithColumnNotNull(), assets.WithColumnLength(ptrs.AsPtr(180)))
+	user.SetPrimaryKey([]string{"id"}, ptrs.AsPtr("pk_users"))
+	databaseSchema.CreateSequence("users_id_seq", 1, 1)
+
+	up := platform.GetAlterSchemaSQL(comparator.CompareSchemas(databaseSchema, localSchema))
+	expectedUp := []string{
+		"ALTER SEQUENCE users_id_seq RENAME TO app_user_id_seq",
+		"ALTER TABLE users RENAME TO app_user",
+	}
+	assertSQL(t, up, expectedUp)
+}
+
+func TestRenamedSequencesOrder(t *testing.T) {
+	platform := postgres_platform.NewPostgreSQLPlatform()
+
+	newSchema := assets.NewSchema(nil, nil, nil, []string{"public"})
+	renamedSequences := make(map[string]*assets.Sequence)
+	for _, name := range []string{"c", "a", "d", "b", "e"} {
+		renamedSequences["old_"+name+"__id__seq"] = newSchema.CreateSequence(name+"__id__seq", 1, 1)
+	}
+
+	diff := diff_dtos.NewSchemaDiff(nil, nil, nil, nil, nil, nil, nil, nil, renamedSequences)
+	expected := []string{
+		"ALTER SEQUENCE old_a__id__seq RENAME TO a__id__seq",
+		"ALTER SEQUENCE old_b__id__seq RENAME TO b__id__seq",
+		"ALTER SEQUENCE old_c__id__seq RENAME TO c__id__seq",
+		"ALTER SEQUENCE old_d__id__seq RENAME TO d__id__seq",
+		"ALTER SEQUENCE old_e__id__seq RENAME TO e__id__seq",
+	}
+
+	for range 10 {
+		if sql := platform.GetAlterSchemaSQL(diff); !slices.Equal(sql, expected) {
+			t.Fatalf("expected sequences renamed in sorted order, got %v", sql)
+		}
+	}
+}
+
+func assertSQL(t *testing.T, actual []string, expected []string) {
+	t.Helper()
+
+	if len(actual) != len(expected) {
+		t.Fatalf("expected %d statements, got %v", len(expected), actual)
+	}
+
+	for _, sql := range expected {
+		if !slices.Contains(actual, sql) {
+			t.Fatalf("expected %s in %v", sql, actual)
+		}
+	}
+}
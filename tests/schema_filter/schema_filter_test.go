@@ -0,0 +1,139 @@
+package schema_filter
+
+import (
+	"github.com/KoNekoD/gormite/pkg/assets"
+	"github.com/KoNekoD/gormite/pkg/dtos"
+	"github.com/KoNekoD/gormite/pkg/enums"
+	"github.com/KoNekoD/gormite/pkg/types"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func readConfig(t *testing.T, content string) (*dtos.ConfigData, error) {
+	path := filepath.Join(t.TempDir(), "gormite.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return dtos.NewConfigData(path)
+}
+
+func createSchema() *assets.Schema {
+	schema := assets.NewSchema(nil, nil, nil, []string{"public"})
+
+	for _, name := range []string{"user", "user_profile", "spatial_ref_sys", "billing_invoice", "tmp_1"} {
+		schema.CreateTable(name).AddColumn("id", types.GetType(enums.TypeInteger), assets.WithColumnNotNull())
+		schema.CreateSequence(name+"__id__seq", 1, 1)
+	}
+
+	schema.CreateSequence("billing_invoice_id_seq", 1, 1)
+	schema.CreateSequence("tmp_counter", 1, 1)
+	schema.CreateView("billing_report", "SELECT 1")
+	schema.CreateView("user_report", "SELECT 1")
+
+	return schema
+}
+
+func getNames(schema *assets.Schema) []string {
+	names := make([]string, 0)
+	for _, table := range schema.GetTables() {
+		names = append(names, table.GetName())
+	}
+	for _, sequence := range schema.GetSequences() {
+		names = append(names, sequence.GetName())
+	}
+	for _, view := range schema.GetViews() {
+		names = append(names, view.GetName())
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+func TestSchemaFilterExclude(t *testing.T) {
+	config, err := readConfig(
+		t,
+		"gormite:\n  schema_filter:\n    exclude:\n      - spatial_ref_sys\n      - \"billing_*\"\n      - \"/^tmp_\\\\d+$/\"\n",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema := createSchema().FilterAssets(config.Gormite.SchemaFilter.IsIncluded)
+
+	expected := []string{
+		"tmp_counter",
+		"user",
+		"user__id__seq",
+		"user_profile",
+		"user_profile__id__seq",
+		"user_report",
+	}
+	if actual := getNames(schema); !slices.Equal(actual, expected) {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestSchemaFilterInclude(t *testing.T) {
+	config, err := readConfig(
+		t,
+		"gormite:\n  schema_filter:\n    include:\n      - \"user*\"\n    exclude:\n      - user_report\n",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema := createSchema().FilterAssets(config.Gormite.SchemaFilter.IsIncluded)
+
+	expected := []string{"user", "user__id__seq", "user_profile", "user_profile__id__seq"}
+	if actual := getNames(schema); !slices.Equal(actual, expected) {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestSchemaFilterSequenceOwner(t *testing.T) {
+	config, err := readConfig(t, "gormite:\n  schema_filter:\n    exclude:\n      - order\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema := assets.NewSchema(nil, nil, nil, []string{"public"})
+	order := schema.CreateTable("order")
+	order.AddColumn("id", types.GetType(enums.TypeInteger), assets.WithColumnNotNull())
+	order.AddColumn("number", types.GetType(enums.TypeInteger), assets.WithColumnNotNull())
+	schema.CreateSequence("order__id__seq", 1, 1)
+	schema.CreateSequence("order_number_seq", 1, 1)
+	schema.CreateSequence("order_status_seq", 1, 1)
+
+	// Sequence only starts with the table name, so it is not owned by the table
+	expected := []string{"order_status_seq"}
+	if actual := getNames(schema.FilterAssets(config.Gormite.SchemaFilter.IsIncluded)); !slices.Equal(actual, expected) {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestSchemaFilterEmpty(t *testing.T) {
+	config, err := readConfig(t, "gormite:\n  orm:\n    mapping:\n      Entities:\n        dir: entities\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema := createSchema()
+	expected := getNames(schema)
+
+	if actual := getNames(schema.FilterAssets(config.Gormite.SchemaFilter.IsIncluded)); !slices.Equal(actual, expected) {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestSchemaFilterInvalidPattern(t *testing.T) {
+	if _, err := readConfig(t, "gormite:\n  schema_filter:\n    exclude:\n      - \"/(/\"\n"); err == nil {
+		t.Fatal("expected error for invalid regular expression")
+	}
+
+	if _, err := readConfig(t, "gormite:\n  schema_filter:\n    include:\n      - \"[\"\n"); err == nil {
+		t.Fatal("expected error for invalid glob")
+	}
+}
@@ -0,0 +1,187 @@
+package schema_snapshots
+
+import (
+	"github.com/KoNekoD/gormite/pkg/assets"
+	"github.com/KoNekoD/gormite/pkg/diff_calc"
+	"github.com/KoNekoD/gormite/pkg/enums"
+	"github.com/KoNekoD/gormite/pkg/platforms/postgres_platform"
+	"github.com/KoNekoD/gormite/pkg/schema_snapshots"
+	"github.com/KoNekoD/gormite/pkg/types"
+	"github.com/KoNekoD/gormite/tests/test_helpers"
+	"github.com/KoNekoD/ptrs/pkg/ptrs"
+	"github.com/pkg/errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func createSchema() *assets.Schema {
+	schema := assets.NewSchema(nil, nil, nil, []string{"public"})
+
+	length := 255
+
+	user := schema.CreateTable("app_user")
+	user.AddColumn("id", types.GetType(enums.TypeInteger), assets.WithColumnNotNull())
+	user.AddColumn("email", types.GetType(enums.TypeString), assets.WithColumnNotNull(), assets.WithColumnLength(&length))
+	user.AddColumn("created_at", types.GetType(enums.TypeDatetimeImmutable), assets.WithColumnDefault("now()"))
+	user.AddColumn("settings", types.GetType(enums.TypeJson)).SetPlatformOption("jsonb", true)
+	user.SetPrimaryKey([]string{"id"}, nil)
+	user.AddUniqueIndex([]string{"email"}, nil, nil)
+	user.AddIndex([]string{"created_at"}, nil, nil, map[string]any{"where": "(created_at IS NOT NULL)"})
+	user.AddOption("unlogged", true)
+
+	post := schema.CreateTable("post")
+	post.AddColumn("id", types.GetType(enums.TypeInteger), assets.WithColumnNotNull())
+	post.AddColumn("author_id", types.GetType(enums.TypeInteger), assets.WithColumnNotNull())
+	post.AddColumn("title", types.GetType(enums.TypeString), assets.WithColumnNotNull(), assets.WithColumnLength(&length))
+	post.SetPrimaryKey([]string{"id"}, nil)
+	post.AddIndex([]string{"author_id"}, nil, nil, nil)
+	post.AddIndex([]string{"title", "author_id"}, ptrs.AsPtr("idx_post_title_author"), nil, nil)
+	post.AddForeignKeyConstraint("app_user", []string{"author_id"}, []string{"id"}, map[string]any{}, nil)
+
+	schema.CreateSequence("app_user_id_seq", 1, 1)
+	schema.CreateView("active_user", "SELECT id FROM app_user")
+
+	return schema
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	for _, fileName := range []string{"schema.json", "schema.yaml"} {
+		t.Run(fileName, func(t *testing.T) { testSnapshotRoundTrip(t, filepath.Join(t.TempDir(), fileName)) })
+	}
+}
+
+func testSnapshotRoundTrip(t *testing.T, path string) {
+
+	if err := schema_snapshots.WriteFile(path, createSchema()); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := schema_snapshots.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff := diff_calc.NewComparator(postgres_platform.NewPostgreSQLPlatform()).CompareSchemas(restored, createSchema())
+	if !diff.IsEmpty() {
+		t.Fatal("expected no changes between restored snapshot and original schema")
+	}
+
+	if len(restored.GetViews()) != 1 || !restored.HasView("active_user") {
+		t.Fatal("expected view to be restored")
+	}
+
+	if !restored.GetTable("app_user").HasOption("unlogged") {
+		t.Fatal("expected table options to be restored")
+	}
+
+	first, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := schema_snapshots.WriteFile(path, restored); err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(first) != string(second) {
+		t.Fatalf("snapshot output is not stable:\n%s\n%s", first, second)
+	}
+}
+
+func TestSnapshotKeepsIndexColumnOrder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schema.json")
+
+	if err := schema_snapshots.WriteFile(path, createSchema()); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := schema_snapshots.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	columns := restored.GetTable("post").GetIndex("idx_post_title_author").GetColumns()
+	if !slices.Equal(columns, []string{"title", "author_id"}) {
+		t.Fatalf("expected index columns in declared order, got %v", columns)
+	}
+
+	reordered := createSchema()
+	post := reordered.GetTable("post")
+	post.DropIndex("idx_post_title_author")
+	post.AddIndex([]string{"author_id", "title"}, ptrs.AsPtr("idx_post_title_author"), nil, nil)
+
+	diff := diff_calc.NewComparator(postgres_platform.NewPostgreSQLPlatform()).CompareSchemas(restored, reordered)
+	if diff.IsEmpty() {
+		t.Fatal("expected changed column order of the index to be detected")
+	}
+}
+
+const indexPositionsSource = `package entities
+
+// Post post
+type Post struct {
+	ID       int    ` + "`db:\"id\" pk:\"true\"`" + `
+	AuthorID int    ` + "`db:\"author_id\" index:\"idx_post_title_author\" index_position:\"idx_post_title_author:2\"`" + `
+	Title    string ` + "`db:\"title\" index:\"idx_post_title_author\" index_position:\"idx_post_title_author:1\"`" + `
+}
+`
+
+func TestIndexPositions(t *testing.T) {
+	localSchema, err := test_helpers.IntrospectSource(t, indexPositionsSource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	columns := localSchema.GetTable("post").GetIndex("idx_post_title_author").GetColumns()
+	if !slices.Equal(columns, []string{"title", "author_id"}) {
+		t.Fatalf("expected index columns in declared positions, got %v", columns)
+	}
+
+	databaseSchema := assets.NewSchema(nil, nil, nil, []string{"public"})
+	post := databaseSchema.CreateTable("post")
+	post.AddColumn("id", types.GetType(enums.TypeInteger), assets.WithColumnNotNull())
+	post.AddColumn("author_id", types.GetType(enums.TypeInteger), assets.WithColumnNotNull())
+	post.AddColumn("title", types.GetType(enums.TypeString), assets.WithColumnNotNull(), assets.WithColumnLength(ptrs.AsPtr(255)))
+	post.SetPrimaryKey([]string{"id"}, ptrs.AsPtr("post_pkey"))
+	post.AddIndex([]string{"title", "author_id"}, ptrs.AsPtr("idx_post_title_author"), nil, nil)
+	databaseSchema.CreateSequence("post__id__seq", 1, 1)
+
+	platform := postgres_platform.NewPostgreSQLPlatform()
+	if diff := diff_calc.NewComparator(platform).CompareSchemas(databaseSchema, localSchema); !diff.IsEmpty() {
+		t.Fatalf("expected empty diff, got %v", platform.GetAlterSchemaSQL(diff))
+	}
+
+	cases := map[string]string{
+		"idx_post_title_author:2":                    "idx_post_title_author:1",
+		"index_position:\"idx_post_title_author:1\"": "index_position:\"idx_post_title_author:x\"",
+		"index_position:\"idx_post_title_author:2\"": "index_position:\"idx_other:2\"",
+	}
+
+	for from, to := range cases {
+		if _, err := test_helpers.IntrospectSource(t, strings.Replace(indexPositionsSource, from, to, 1)); err == nil {
+			t.Fatalf("expected error when %s is replaced with %s", from, to)
+		}
+	}
+}
+
+func TestReadMissingSnapshot(t *testing.T) {
+	_, err := schema_snapshots.ReadFile(filepath.Join(t.TempDir(), "missing.json"))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected not exist error, got %v", err)
+	}
+}
+
+func TestUnsupportedSnapshotVersion(t *testing.T) {
+	_, err := schema_snapshots.Unmarshal([]byte(`{"version": 999, "tables": []}`), schema_snapshots.FormatJSON)
+	if err == nil {
+		t.Fatal("expected unsupported version error")
+	}
+}
@@ -0,0 +1,137 @@
+package table_metadata
+
+import (
+	"github.com/KoNekoD/gormite/pkg/assets"
+	"github.com/KoNekoD/gormite/tests/test_helpers"
+	"strings"
+	"testing"
+)
+
+const entitySource = `// Package entities contains the shop entities.
+package entities
+
+// User is a customer of the shop, who places orders.
+// gormite:table=customers schema=shop comment="Customers of the shop"
+type User struct {
+	ID int ` + "`db:\"id\" pk:\"true\"`" + `
+}
+
+// Order is placed by the customer.
+// It is never deleted.
+type Order struct {
+	ID   int   ` + "`db:\"id\" pk:\"true\"`" + `
+	User *User ` + "`db:\"user_id\"`" + `
+}
+
+// TableName returns the table name of the order.
+func (Order) TableName() string {
+	return "orders"
+}
+
+const invoiceTable = "invoices"
+
+// Invoice invoice
+type Invoice struct {
+	ID int ` + "`db:\"id\" pk:\"true\"`" + `
+}
+
+func (i *Invoice) TableName() string {
+	return invoiceTable
+}
+
+// Product products
+// Products sold in the store.
+type Product struct {
+	ID int ` + "`db:\"id\" pk:\"true\"`" + `
+}
+
+// Price returns the price of the product.
+func (p *Product) Price() int {
+	// Product price is not stored, id_strategy:none check:(price > 0) are not hints
+	return 0
+}
+
+// Shipment shipments
+
+// Shipment is sent to the customer, renamed_from:deliveries is not a hint.
+type Shipment struct {
+	ID int ` + "`db:\"id\" pk:\"true\"`" + `
+}
+`
+
+func getComment(table *assets.Table) string {
+	if comment := table.GetComment(); comment != nil {
+		return *comment
+	}
+
+	return ""
+}
+
+func TestTableMetadata(t *testing.T) {
+	schema, err := test_helpers.IntrospectSource(t, entitySource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	comments := map[string]string{
+		"shop.customers": "Customers of the shop",
+		"orders":         "Order is placed by the customer.\nIt is never deleted.",
+		"invoices":       "",
+		"products":       "Products sold in the store.",
+		"shipments":      "Shipment is sent to the customer, renamed_from:deliveries is not a hint.",
+	}
+
+	for tableName, comment := range comments {
+		if !schema.HasTable(tableName) {
+			t.Fatalf("expected table %s", tableName)
+		}
+
+		if actual := getComment(schema.GetTable(tableName)); actual != comment {
+			t.Fatalf("expected comment %q of %s, got %q", comment, tableName, actual)
+		}
+	}
+
+	if len(schema.GetTables()) != len(comments) {
+		t.Fatalf("expected %d tables, got %d", len(comments), len(schema.GetTables()))
+	}
+
+	// Hints are read only from the "// StructName table_name" line
+	if renamedFrom := schema.GetTable("shipments").GetRenamedFrom(); renamedFrom != nil {
+		t.Fatalf("expected shipments not to be renamed, got %s", *renamedFrom)
+	}
+
+	for _, fk := range schema.GetTable("orders").GetForeignKeys() {
+		if fk.GetForeignTableName() != "shop.customers" {
+			t.Fatalf("expected orders to reference shop.customers, got %s", fk.GetForeignTableName())
+		}
+	}
+}
+
+func TestTableMetadataDirectiveWithoutSpace(t *testing.T) {
+	source := strings.Replace(entitySource, "// gormite:table=customers", "//gormite:table=customers", 1)
+
+	schema, err := test_helpers.IntrospectSource(t, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !schema.HasTable("shop.customers") {
+		t.Fatalf("expected //gormite: directive to set the table")
+	}
+}
+
+func TestTableMetadataInvalid(t *testing.T) {
+	cases := map[string]string{
+		"comment=\"Customers of the shop\"":   "owner=admin",
+		"comment=\"Customers of the shop\"\n": "comment=\"Customers of the shop\n",
+		"return invoiceTable":                 "return strings.ToLower(\"INVOICES\")",
+		"type Order struct":                   "// gormite:table=orders\ntype Order struct",
+		"const invoiceTable":                  "// gormite:table=invoices\n\nconst invoiceTable",
+	}
+
+	for from, to := range cases {
+		if _, err := test_helpers.IntrospectSource(t, strings.Replace(entitySource, from, to, 1)); err == nil {
+			t.Fatalf("expected error when %s is replaced with %s", from, to)
+		}
+	}
+}
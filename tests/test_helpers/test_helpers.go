@@ -0,0 +1,132 @@
+package test_helpers
+
+import (
+	"fmt"
+	"github.com/KoNekoD/gormite/pkg/assets"
+	"github.com/KoNekoD/gormite/pkg/local_schema"
+	"github.com/KoNekoD/gormite/pkg/schema_snapshots"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// defaultProjectFiles - Module of the project and stubs of the third party packages which are used by entities, since
+// packages of entities are loaded by the go command. go.mod is replaced when the project has its own.
+var defaultProjectFiles = map[string]string{
+	"go.mod": `module example.com/app
+
+go 1.23
+
+require (
+	github.com/google/uuid v1.6.0
+	github.com/shopspring/decimal v1.4.0
+)
+
+replace (
+	github.com/google/uuid => ./stubs/uuid
+	github.com/shopspring/decimal => ./stubs/decimal
+)
+`,
+	"stubs/uuid/go.mod":        "module github.com/google/uuid\n\ngo 1.23\n",
+	"stubs/uuid/uuid.go":       "package uuid\n\ntype UUID [16]byte\n\ntype NullUUID struct {\n\tUUID  UUID\n\tValid bool\n}\n",
+	"stubs/decimal/go.mod":     "module github.com/shopspring/decimal\n\ngo 1.23\n",
+	"stubs/decimal/decimal.go": "package decimal\n\ntype Decimal struct {\n\tvalue string\n}\n",
+}
+
+// WriteProject - Writes the files into a temporary project dir, paths are relative to the dir.
+func WriteProject(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	// go command of the entities packages must use the module of the project, not the modfile of the tests
+	t.Setenv("GOFLAGS", "-mod=mod")
+
+	dir := t.TempDir()
+
+	projectFiles := maps.Clone(defaultProjectFiles)
+	maps.Copy(projectFiles, files)
+
+	for path, content := range projectFiles {
+		path = filepath.Join(dir, path)
+
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+// IntrospectConfig - Writes gormite.yaml with the content into the project dir and introspects entities of it.
+func IntrospectConfig(t *testing.T, dir string, config string) (*assets.Schema, error) {
+	t.Helper()
+
+	configPath := filepath.Join(dir, "gormite.yaml")
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return local_schema.IntrospectLocalSchema(configPath)
+}
+
+// Introspect - Introspects the files of the "entities" mapping, orm is appended to the orm section of the config,
+// e.g. "    id_strategy: identity\n".
+func Introspect(t *testing.T, files map[string]string, orm string) (*assets.Schema, error) {
+	t.Helper()
+
+	projectFiles := make(map[string]string)
+	for name, content := range files {
+		projectFiles[filepath.Join("entities", name)] = content
+	}
+
+	dir := WriteProject(t, projectFiles)
+	config := fmt.Sprintf("gormite:\n  orm:\n%s    mapping:\n      Entities:\n        dir: %s\n", orm, filepath.Join(dir, "entities"))
+
+	return IntrospectConfig(t, dir, config)
+}
+
+// IntrospectSource - Introspects the "entities" mapping of the single file.
+func IntrospectSource(t *testing.T, source string) (*assets.Schema, error) {
+	t.Helper()
+
+	return Introspect(t, map[string]string{"entities.go": source}, "")
+}
+
+// GenerateSource - Generates entities of the schema with the default naming strategy and returns their sources.
+func GenerateSource(t *testing.T, schema *assets.Schema) string {
+	t.Helper()
+
+	entities, err := local_schema.GenerateEntities(schema, "entities", &local_schema.DefaultNamingStrategy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source := &strings.Builder{}
+	for _, entity := range entities {
+		source.Write(entity.Content)
+	}
+
+	return source.String()
+}
+
+// SnapshotSchema - Writes the schema into the YAML snapshot and reads it back.
+func SnapshotSchema(t *testing.T, schema *assets.Schema) *assets.Schema {
+	t.Helper()
+
+	content, err := schema_snapshots.Marshal(schema, schema_snapshots.FormatYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshotSchema, err := schema_snapshots.Unmarshal(content, schema_snapshots.FormatYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return snapshotSchema
+}
@@ -0,0 +1,235 @@
+package type_inference
+
+import (
+	"fmt"
+	"github.com/KoNekoD/gormite/pkg/platforms/postgres_platform"
+	"github.com/KoNekoD/gormite/pkg/types"
+	"github.com/KoNekoD/gormite/tests/test_helpers"
+	"strings"
+	"testing"
+)
+
+const entitySource = `package entities
+
+import "time"
+
+type Status string
+
+type Code = string
+
+const StatusActive Status = "active"
+
+// Order orders
+type Order struct {
+	ID      int        ` + "`db:\"id\" pk:\"true\"`" + `
+	Status  Status     ` + "`db:\"status\" length:\"32\"`" + `
+	Code    *Code      ` + "`db:\"code\" nullable:\"true\"`" + `
+	Month   time.Month ` + "`db:\"month\"`" + `
+	Tags    []Status   ` + "`db:\"tags\" type:\"jsonb\"`" + `
+	Created time.Time  ` + "`db:\"created_at\"`" + `
+}
+`
+
+func TestUnderlyingTypes(t *testing.T) {
+	schema, err := test_helpers.IntrospectSource(t, entitySource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	table := schema.GetTable("orders")
+
+	status := table.GetColumn("status")
+	if _, ok := status.GetColumnType().(*types.StringType); !ok {
+		t.Fatalf("expected string type of status, got %T", status.GetColumnType())
+	}
+	if status.GetLength() == nil || *status.GetLength() != 32 {
+		t.Fatalf("expected length 32 of status, got %v", status.GetLength())
+	}
+
+	code := table.GetColumn("code")
+	if _, ok := code.GetColumnType().(*types.StringType); !ok || code.GetNotNull() {
+		t.Fatalf("expected nullable string type of code, got %T", code.GetColumnType())
+	}
+
+	if month := table.GetColumn("month"); month == nil {
+		t.Fatal("expected month column")
+	} else if _, ok := month.GetColumnType().(*types.IntegerType); !ok {
+		t.Fatalf("expected integer type of month, got %T", month.GetColumnType())
+	}
+
+	if _, ok := table.GetColumn("tags").GetColumnType().(*types.JsonType); !ok {
+		t.Fatalf("expected json type of tags, got %T", table.GetColumn("tags").GetColumnType())
+	}
+
+	if _, ok := table.GetColumn("created_at").GetColumnType().(*types.DateTimeImmutableType); !ok {
+		t.Fatalf("expected datetime type of created_at, got %T", table.GetColumn("created_at").GetColumnType())
+	}
+
+	if len(schema.GetTables()) != 1 {
+		t.Fatalf("expected only orders table, got %d tables", len(schema.GetTables()))
+	}
+}
+
+func TestUnknownTypeError(t *testing.T) {
+	source := `package entities
+
+// Order orders
+type Order struct {
+	ID    int        ` + "`db:\"id\" pk:\"true\"`" + `
+	Ratio complex128 ` + "`db:\"ratio\"`" + `
+}
+`
+
+	_, err := test_helpers.IntrospectSource(t, source)
+	if err == nil {
+		t.Fatal("expected error for unknown type")
+	}
+
+	for _, part := range []string{"Order.Ratio", "unknown type complex128", "ratio"} {
+		if !strings.Contains(err.Error(), part) {
+			t.Fatalf("expected %q in error, got %v", part, err)
+		}
+	}
+}
+
+func TestPackageTypeError(t *testing.T) {
+	source := `package entities
+
+// Order orders
+type Order struct {
+	ID     int      ` + "`db:\"id\" pk:\"true\"`" + `
+	Status Statuses ` + "`db:\"status\"`" + `
+}
+`
+
+	_, err := test_helpers.IntrospectSource(t, source)
+	if err == nil {
+		t.Fatal("expected error for undefined type")
+	}
+
+	for _, part := range []string{"entities.go:6", "undefined: Statuses"} {
+		if !strings.Contains(err.Error(), part) {
+			t.Fatalf("expected %q in error, got %v", part, err)
+		}
+	}
+}
+
+const goTypesSource = `package entities
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Event events
+type Event struct {
+	ID       int64              ` + "`db:\"id\" pk:\"true\"`" + `
+	Priority int16              ` + "`db:\"priority\"`" + `
+	Counter  uint64             ` + "`db:\"counter\"`" + `
+	Payload  []byte             ` + "`db:\"payload\"`" + `
+	Meta     map[string]any     ` + "`db:\"meta\"`" + `
+	Raw      json.RawMessage    ` + "`db:\"raw\"`" + `
+	Timeout  time.Duration      ` + "`db:\"timeout\"`" + `
+	Note     sql.NullString     ` + "`db:\"note\"`" + `
+	Retries  sql.Null[int64]    ` + "`db:\"retries\"`" + `
+	Amount   decimal.Decimal    ` + "`db:\"amount\" precision:\"10\" scale:\"2\"`" + `
+	Owner    uuid.NullUUID      ` + "`db:\"owner\"`" + `
+	Ratio    float32            ` + "`db:\"ratio\"`" + `
+	Label    string             ` + "`db:\"label\" type:\"text\"`" + `
+}
+`
+
+func TestGoTypes(t *testing.T) {
+	schema, err := test_helpers.IntrospectSource(t, goTypesSource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	table := schema.GetTable("events")
+
+	expected := []struct {
+		column   string
+		typ      types.AbstractTypeInterface
+		nullable bool
+	}{
+		{"id", &types.BigintType{}, false},
+		{"priority", &types.SmallIntType{}, false},
+		{"counter", &types.DecimalType{}, false},
+		{"payload", &types.BlobType{}, false},
+		{"meta", &types.JsonType{}, false},
+		{"raw", &types.JsonType{}, false},
+		{"timeout", &types.DateIntervalType{}, false},
+		{"note", &types.StringType{}, true},
+		{"retries", &types.BigintType{}, true},
+		{"amount", &types.DecimalType{}, false},
+		{"owner", &types.GuidType{}, true},
+		{"ratio", &types.SmallFloatType{}, false},
+		{"label", &types.TextType{}, false},
+	}
+
+	for _, e := range expected {
+		column := table.GetColumn(e.column)
+		if fmt.Sprintf("%T", column.GetColumnType()) != fmt.Sprintf("%T", e.typ) {
+			t.Fatalf("expected %T type of %s, got %T", e.typ, e.column, column.GetColumnType())
+		}
+		if column.GetNotNull() == e.nullable {
+			t.Fatalf("expected nullable %v of %s", e.nullable, e.column)
+		}
+	}
+
+	counter := table.GetColumn("counter")
+	if *counter.GetPrecision() != 20 || *counter.GetScale() != 0 {
+		t.Fatalf("expected numeric(20, 0) of counter, got %v, %v", *counter.GetPrecision(), *counter.GetScale())
+	}
+
+	amount := table.GetColumn("amount")
+	if *amount.GetPrecision() != 10 || *amount.GetScale() != 2 {
+		t.Fatalf("expected numeric(10, 2) of amount, got %v, %v", *amount.GetPrecision(), *amount.GetScale())
+	}
+
+	if jsonb, _ := table.GetColumn("meta").GetPlatformOption("jsonb").(bool); !jsonb {
+		t.Fatal("expected jsonb of meta")
+	}
+
+	platform := postgres_platform.NewPostgreSQLPlatform()
+	sql := platform.GetCreateTableSQL(table)[0]
+
+	for _, declaration := range []string{
+		"id BIGINT NOT NULL",
+		"priority SMALLINT NOT NULL",
+		"counter NUMERIC(20, 0) NOT NULL",
+		"payload BYTEA NOT NULL",
+		"meta JSONB NOT NULL",
+		"timeout INTERVAL NOT NULL",
+		"note VARCHAR(255),",
+		"owner UUID,",
+	} {
+		if !strings.Contains(sql, declaration) {
+			t.Fatalf("expected %s in %s", declaration, sql)
+		}
+	}
+}
+
+func TestGoTypesImport(t *testing.T) {
+	schema, err := test_helpers.IntrospectSource(t, goTypesSource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source := test_helpers.GenerateSource(t, schema)
+
+	for _, field := range []string{
+		"Priority int16           `db:\"priority\"`",
+		"Timeout  time.Duration   `db:\"timeout\"`",
+		"Owner    *string         `db:\"owner\" nullable:\"true\" type:\"uuid\"`",
+		"Ratio    float32         `db:\"ratio\"`",
+	} {
+		if !strings.Contains(source, field) {
+			t.Fatalf("expected %s in generated entity:\n%s", field, source)
+		}
+	}
+}